@@ -3,6 +3,7 @@ package benchmark
 import (
 	"bytes"
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	gomarkdown "github.com/gomarkdown/markdown"
@@ -16,38 +17,39 @@ import (
 	"github.com/88250/lute"
 )
 
-func BenchmarkMarkdown(b *testing.B) {
-	b.Run("Blackfriday-v2", func(b *testing.B) {
-		r := func(src []byte) ([]byte, error) {
+type renderFunc func(src []byte) ([]byte, error)
+
+// libraries returns constructors of renderers for each library.
+// Renderers are constructed outside of the measured loop.
+var libraries = []struct {
+	name string
+	new  func() renderFunc
+}{
+	{"Blackfriday-v2", func() renderFunc {
+		return func(src []byte) ([]byte, error) {
 			out := blackfriday.Run(src)
 			return out, nil
 		}
-		doBenchmark(b, r)
-	})
-
-	b.Run("GoldMark", func(b *testing.B) {
+	}},
+	{"GoldMark", func() renderFunc {
 		markdown := goldmark.New(
 			goldmark.WithRendererOptions(html.WithXHTML(), html.WithUnsafe()),
 		)
-		r := func(src []byte) ([]byte, error) {
+		return func(src []byte) ([]byte, error) {
 			var out bytes.Buffer
 			err := markdown.Convert(src, &out)
 			return out.Bytes(), err
 		}
-		doBenchmark(b, r)
-	})
-
-	b.Run("CommonMark", func(b *testing.B) {
+	}},
+	{"CommonMark", func() renderFunc {
 		md := markdown.New(markdown.XHTMLOutput(true))
-		r := func(src []byte) ([]byte, error) {
+		return func(src []byte) ([]byte, error) {
 			var out bytes.Buffer
 			err := md.Render(&out, src)
 			return out.Bytes(), err
 		}
-		doBenchmark(b, r)
-	})
-
-	b.Run("Lute", func(b *testing.B) {
+	}},
+	{"Lute", func() renderFunc {
 		luteEngine := lute.New()
 		luteEngine.SetGFMAutoLink(false)
 		luteEngine.SetGFMStrikethrough(false)
@@ -57,32 +59,104 @@ func BenchmarkMarkdown(b *testing.B) {
 		luteEngine.SetSoftBreak2HardBreak(false)
 		luteEngine.SetAutoSpace(false)
 		luteEngine.SetFixTermTypo(false)
-		r := func(src []byte) ([]byte, error) {
+		return func(src []byte) ([]byte, error) {
 			out := luteEngine.MarkdownStr("Benchmark", util.BytesToReadOnlyString(src))
 			return util.StringToReadOnlyBytes(out), nil
 		}
-		doBenchmark(b, r)
-	})
-
-	b.Run("GoMarkdown", func(b *testing.B) {
-		r := func(src []byte) ([]byte, error) {
+	}},
+	{"GoMarkdown", func() renderFunc {
+		return func(src []byte) ([]byte, error) {
 			out := gomarkdown.ToHTML(src, nil, nil)
 			return out, nil
 		}
-		doBenchmark(b, r)
-	})
-
+	}},
 }
 
-// The different frameworks have different APIs. Create an adapter that
-// should behave the same in the memory department.
-func doBenchmark(b *testing.B, render func(src []byte) ([]byte, error)) {
+func BenchmarkMarkdown(b *testing.B) {
 	b.StopTimer()
 	source, err := ioutil.ReadFile("_data.md")
 	if err != nil {
 		b.Fatal(err)
 	}
 	b.StartTimer()
+	for _, lib := range libraries {
+		lib := lib
+		b.Run(lib.name, func(b *testing.B) {
+			doBenchmark(b, lib.new(), source)
+		})
+	}
+}
+
+// documents are representative documents for BenchmarkDocuments.
+var documents = []struct {
+	name   string
+	source func() ([]byte, error)
+}{
+	{"spec", func() ([]byte, error) {
+		return ioutil.ReadFile("_data.md")
+	}},
+	{"readme", func() ([]byte, error) {
+		return ioutil.ReadFile("../../README.md")
+	}},
+	{"paragraphs", repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit,\n"+
+		"sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.\n\n", 2000)},
+	{"emphasis", repeat("*emphasis* **strong** ***both*** _a_ __b__ *a **b** c* **a *b* c**\n\n", 2000)},
+	{"links", repeat("[inline](https://example.com/ \"title\") [ref][a] [a] ![image](/a.png) <https://example.com/>\n\n", 1000,
+		"[a]: https://example.com/a \"title\"\n")},
+	{"code", repeat("`code span` and ``code `span` ``\n\n```go\nfunc main() {\n\tprintln(\"<hello>\")\n}\n```\n\n    indented\n    code\n\n", 1000)},
+	{"html", repeat("<div class=\"note\">\n<p>block</p>\n</div>\n\ninline <span>html</span> <!-- comment --> &amp; &copy;\n\n", 1000)},
+	{"lists", repeat("- a\n- b\n  1. c\n  2. d\n     - e\n\n     - f\n- g\n\n", 1000)},
+	{"nested-lists", nested("- ", "  ", 100)},
+	{"nested-blockquotes", nested("> ", "> ", 100)},
+}
+
+func repeat(s string, n int, suffix ...string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return []byte(strings.Repeat(s, n) + strings.Join(suffix, "")), nil
+	}
+}
+
+// nested returns a document that nests containers depth times.
+func nested(marker, indent string, depth int) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		var b bytes.Buffer
+		for n := 0; n < 10; n++ {
+			for i := 0; i < depth; i++ {
+				b.WriteString(strings.Repeat(indent, i))
+				b.WriteString(marker)
+				b.WriteString("item *emphasis* `code`\n")
+			}
+			b.WriteString("\n")
+		}
+		return b.Bytes(), nil
+	}
+}
+
+// BenchmarkDocuments runs benchmarks for each pair of the document and
+// the library. Results can be compared with benchstat, e.g.:
+//
+//	go test -run=NONE -bench=Documents -count=10 > new.txt
+//	benchstat -col /lib new.txt
+func BenchmarkDocuments(b *testing.B) {
+	for _, doc := range documents {
+		source, err := doc.source()
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, lib := range libraries {
+			lib := lib
+			b.Run("doc="+doc.name+"/lib="+lib.name, func(b *testing.B) {
+				b.SetBytes(int64(len(source)))
+				doBenchmark(b, lib.new(), source)
+			})
+		}
+	}
+}
+
+// The different frameworks have different APIs. Create an adapter that
+// should behave the same in the memory department.
+func doBenchmark(b *testing.B, render renderFunc, source []byte) {
+	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		out, err := render(source)
 		if err != nil {
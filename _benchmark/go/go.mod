@@ -21,4 +21,6 @@ require (
 	golang.org/x/text v0.10.0 // indirect
 )
 
+replace github.com/yuin/goldmark => ../../
+
 replace gopkg.in/russross/blackfriday.v2 v2.0.1 => github.com/russross/blackfriday/v2 v2.0.1
@@ -0,0 +1,118 @@
+// This program runs examples of the CommonMark spec and reports
+// the compliance of goldmark.
+//
+//	go run spectest.go [-spec path or URL] [-q]
+//
+// Examples are read from ../_test/spec.json by default.
+// Use -spec https://spec.commonmark.org/0.31.2/spec.json to test against
+// an other version of the spec.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+type specExample struct {
+	Markdown string `json:"markdown"`
+	HTML     string `json:"html"`
+	Example  int    `json:"example"`
+	Section  string `json:"section"`
+}
+
+func readSpec(path string) ([]byte, error) {
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		return os.ReadFile(path)
+	}
+	resp, err := http.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", path, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func main() {
+	specPath := flag.String("spec", "../_test/spec.json", "a path or an URL of spec.json")
+	quiet := flag.Bool("q", false, "do not print failed examples")
+	flag.Parse()
+
+	bs, err := readSpec(*specPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read the spec: %v\n", err)
+		os.Exit(2)
+	}
+	var examples []specExample
+	if err := json.Unmarshal(bs, &examples); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse the spec: %v\n", err)
+		os.Exit(2)
+	}
+
+	markdown := goldmark.New(goldmark.WithRendererOptions(
+		html.WithXHTML(),
+		html.WithUnsafe(),
+	))
+	type sectionResult struct {
+		passed int
+		total  int
+	}
+	sections := map[string]*sectionResult{}
+	passed := 0
+	for _, e := range examples {
+		s, ok := sections[e.Section]
+		if !ok {
+			s = &sectionResult{}
+			sections[e.Section] = s
+		}
+		s.total++
+		var out bytes.Buffer
+		if err := markdown.Convert([]byte(e.Markdown), &out); err != nil {
+			fmt.Printf("Example %d (%s): %v\n", e.Example, e.Section, err)
+			continue
+		}
+		if bytes.Equal(bytes.TrimSpace(out.Bytes()), bytes.TrimSpace([]byte(e.HTML))) {
+			passed++
+			s.passed++
+			continue
+		}
+		if !*quiet {
+			fmt.Printf("Example %d (%s):\n%s\n", e.Example, e.Section,
+				testutil.DiffPretty([]byte(e.HTML), out.Bytes()))
+		}
+	}
+
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := sections[name]
+		if s.passed != s.total {
+			fmt.Printf("%-40s %4d/%4d\n", name, s.passed, s.total)
+		}
+	}
+	total := len(examples)
+	if total == 0 {
+		fmt.Println("No examples found")
+		os.Exit(2)
+	}
+	fmt.Printf("Passed %d/%d examples (%.1f%%)\n", passed, total, float64(passed)*100/float64(total))
+	if passed != total {
+		os.Exit(1)
+	}
+}
@@ -146,6 +146,8 @@ type Node interface {
 	IsRaw() bool
 
 	// SetAttribute sets the given value to the attributes.
+	// If an attribute with the same name exists, its value is replaced
+	// and its position is kept.
 	SetAttribute(name []byte, value interface{})
 
 	// SetAttributeString sets the given value to the attributes.
@@ -161,7 +163,7 @@ type Node interface {
 	// (nil, false)
 	AttributeString(name string) (interface{}, bool)
 
-	// Attributes returns a list of attributes.
+	// Attributes returns a list of attributes in insertion order.
 	// This may be a nil if there are no attributes.
 	Attributes() []Attribute
 
@@ -506,3 +508,71 @@ func walkHelper(n Node, walker Walker) (WalkStatus, error) {
 	}
 	return WalkContinue, nil
 }
+
+// TextContent returns a plain text of the given node like a text of
+// headings and list items without any markup. TextContent collects texts
+// of descendant Text and String nodes. Raw HTML, code spans and images
+// are excluded. Soft line breaks are treated as spaces, and hard line
+// breaks and boundaries of blocks are treated as newlines. Backslash
+// escapes and character references are resolved and whitespaces are
+// normalized.
+func TextContent(n Node, source []byte) string {
+	var buf bytes.Buffer
+	_ = Walk(n, func(c Node, entering bool) (WalkStatus, error) {
+		if !entering {
+			if c != n && c.Type() == TypeBlock {
+				_ = buf.WriteByte('\n')
+			}
+			return WalkContinue, nil
+		}
+		switch v := c.(type) {
+		case *Text:
+			if !v.IsRaw() {
+				value := util.UnescapePunctuations(v.Segment.Value(source))
+				value = util.ResolveNumericReferences(value)
+				_, _ = buf.Write(util.ResolveEntityNames(value))
+			}
+			if v.HardLineBreak() {
+				_ = buf.WriteByte('\n')
+			} else if v.SoftLineBreak() {
+				_ = buf.WriteByte(' ')
+			}
+		case *String:
+			if !v.IsRaw() {
+				_, _ = buf.Write(v.Value)
+			}
+		case *AutoLink:
+			_, _ = buf.Write(v.Label(source))
+		case *CodeSpan, *Image, *RawHTML, *HTMLBlock:
+			return WalkSkipChildren, nil
+		}
+		return WalkContinue, nil
+	})
+	return normalizeTextContent(buf.Bytes())
+}
+
+// normalizeTextContent collapses consecutive spaces into a space and
+// consecutive newlines into a newline, and trims spaces around newlines.
+func normalizeTextContent(value []byte) string {
+	var b strings.Builder
+	space, newline := false, false
+	for _, c := range value {
+		switch {
+		case c == '\n':
+			newline = true
+		case util.IsSpace(c):
+			space = true
+		default:
+			if b.Len() != 0 {
+				if newline {
+					_ = b.WriteByte('\n')
+				} else if space {
+					_ = b.WriteByte(' ')
+				}
+			}
+			space, newline = false, false
+			_ = b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -73,3 +73,22 @@ func node(n Node, children ...Node) Node {
 	}
 	return n
 }
+
+func TestAttributesOrder(t *testing.T) {
+	n := NewParagraph()
+	n.SetAttributeString("id", []byte("a"))
+	n.SetAttributeString("class", []byte("b"))
+	n.SetAttributeString("data-x", []byte("c"))
+	n.SetAttributeString("id", []byte("d"))
+
+	var names []string
+	for _, attr := range n.Attributes() {
+		names = append(names, string(attr.Name))
+	}
+	if want := []string{"id", "class", "data-x"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected %v, but got %v", want, names)
+	}
+	if v, ok := n.AttributeString("id"); !ok || string(v.([]byte)) != "d" {
+		t.Errorf("expected id to be replaced, but got %v", v)
+	}
+}
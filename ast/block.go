@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	textm "github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 // A BaseBlock struct implements the Node interface partialliy.
@@ -52,6 +53,20 @@ type Document struct {
 	BaseBlock
 
 	meta map[string]interface{}
+	refs map[string]LinkReference
+}
+
+// A LinkReference struct represents a link reference definition
+// like '[label]: destination "title"'.
+type LinkReference struct {
+	// Label is a label of the reference as written in the source.
+	Label []byte
+
+	// Destination is a destination of the reference.
+	Destination []byte
+
+	// Title is a title of the reference.
+	Title []byte
 }
 
 // KindDocument is a NodeKind of the Document node.
@@ -103,6 +118,28 @@ func (n *Document) AddMeta(key string, value interface{}) {
 	n.meta[key] = value
 }
 
+// LinkReferenceMap returns link reference definitions of this document.
+// Keys are normalized labels as returned by util.ToLinkReference.
+func (n *Document) LinkReferenceMap() map[string]LinkReference {
+	if n.refs == nil {
+		n.refs = map[string]LinkReference{}
+	}
+	return n.refs
+}
+
+// AddLinkReference adds given link reference definition to this document.
+// A definition that has the same normalized label as an existing one
+// is ignored.
+func (n *Document) AddLinkReference(ref LinkReference) {
+	if n.refs == nil {
+		n.refs = map[string]LinkReference{}
+	}
+	key := util.ToLinkReference(ref.Label)
+	if _, ok := n.refs[key]; !ok {
+		n.refs[key] = ref
+	}
+}
+
 // NewDocument returns a new Document node.
 func NewDocument() *Document {
 	return &Document{
@@ -169,18 +206,44 @@ func IsParagraph(node Node) bool {
 	return ok
 }
 
+// HeadingStyle is a syntax of headings.
+type HeadingStyle int
+
+const (
+	// HeadingStyleATX indicates headings like '# Title'.
+	HeadingStyleATX HeadingStyle = iota
+
+	// HeadingStyleSetext indicates headings underlined by '=' or '-'.
+	HeadingStyleSetext
+)
+
+func (s HeadingStyle) String() string {
+	switch s {
+	case HeadingStyleATX:
+		return "ATX"
+	case HeadingStyleSetext:
+		return "Setext"
+	}
+	return ""
+}
+
 // A Heading struct represents headings like SetextHeading and ATXHeading.
 type Heading struct {
 	BaseBlock
 	// Level returns a level of this heading.
 	// This value is between 1 and 6.
 	Level int
+
+	// Style is a syntax of this heading in the source.
+	// Renderers that write Markdown can use this to keep the original style.
+	Style HeadingStyle
 }
 
 // Dump implements Node.Dump .
 func (n *Heading) Dump(source []byte, level int) {
 	m := map[string]string{
 		"Level": fmt.Sprintf("%d", n.Level),
+		"Style": n.Style.String(),
 	}
 	DumpHelper(n, source, level, m, nil)
 }
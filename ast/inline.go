@@ -44,6 +44,8 @@ func (b *BaseInline) SetLines(v *textm.Segments) {
 }
 
 // A Text struct represents a textual content of the Markdown text.
+// A Text node does not hold a copy of the content. It holds only a
+// position in the source text, and Text slices the source on demand.
 type Text struct {
 	BaseInline
 	// Segment is a position in a source text.
@@ -0,0 +1,41 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Callout struct represents a Notion style callout like '> 💡 Text'.
+type Callout struct {
+	gast.BaseBlock
+
+	// Icon is an emoji of the callout.
+	Icon []byte
+
+	// Color is a Notion color of the callout like 'yellow_background'.
+	Color string
+}
+
+// Dump implements Node.Dump.
+func (n *Callout) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Icon":  string(n.Icon),
+		"Color": n.Color,
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindCallout is a NodeKind of the Callout node.
+var KindCallout = gast.NewNodeKind("Callout")
+
+// Kind implements Node.Kind.
+func (n *Callout) Kind() gast.NodeKind {
+	return KindCallout
+}
+
+// NewCallout returns a new Callout node.
+func NewCallout(icon []byte, color string) *Callout {
+	return &Callout{
+		Icon:  icon,
+		Color: color,
+	}
+}
@@ -0,0 +1,95 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Citation struct represents a citation like '[@key]' or
+// '[@key, pp. 10-12]'.
+type Citation struct {
+	gast.BaseInline
+
+	// Key is a citation key.
+	Key []byte
+
+	// Locator is a locator of the citation like 'pp. 10-12'.
+	// Locator is nil if the citation has no locator.
+	Locator []byte
+}
+
+// Dump implements Node.Dump.
+func (n *Citation) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Key"] = string(n.Key)
+	m["Locator"] = string(n.Locator)
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindCitation is a NodeKind of the Citation node.
+var KindCitation = gast.NewNodeKind("Citation")
+
+// Kind implements Node.Kind.
+func (n *Citation) Kind() gast.NodeKind {
+	return KindCitation
+}
+
+// NewCitation returns a new Citation node.
+func NewCitation(key, locator []byte) *Citation {
+	return &Citation{
+		Key:     key,
+		Locator: locator,
+	}
+}
+
+// A Bibliography struct represents a list of cited works.
+type Bibliography struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *Bibliography) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindBibliography is a NodeKind of the Bibliography node.
+var KindBibliography = gast.NewNodeKind("Bibliography")
+
+// Kind implements Node.Kind.
+func (n *Bibliography) Kind() gast.NodeKind {
+	return KindBibliography
+}
+
+// NewBibliography returns a new Bibliography node.
+func NewBibliography() *Bibliography {
+	return &Bibliography{}
+}
+
+// A BibliographyItem struct represents a cited work in the Bibliography.
+type BibliographyItem struct {
+	gast.BaseBlock
+
+	// Key is a citation key of the work.
+	Key []byte
+}
+
+// Dump implements Node.Dump.
+func (n *BibliographyItem) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Key"] = string(n.Key)
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindBibliographyItem is a NodeKind of the BibliographyItem node.
+var KindBibliographyItem = gast.NewNodeKind("BibliographyItem")
+
+// Kind implements Node.Kind.
+func (n *BibliographyItem) Kind() gast.NodeKind {
+	return KindBibliographyItem
+}
+
+// NewBibliographyItem returns a new BibliographyItem node.
+func NewBibliographyItem(key []byte) *BibliographyItem {
+	return &BibliographyItem{
+		Key: key,
+	}
+}
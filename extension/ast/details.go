@@ -0,0 +1,62 @@
+package ast
+
+import (
+	"fmt"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Details struct represents a collapsible details block like
+// '??? summary'.
+// A Details node has a Summary node as its first child.
+type Details struct {
+	gast.BaseBlock
+
+	// Open is true if the details block is expanded by default.
+	Open bool
+}
+
+// Dump implements Node.Dump.
+func (n *Details) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Open"] = fmt.Sprintf("%v", n.Open)
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindDetails is a NodeKind of the Details node.
+var KindDetails = gast.NewNodeKind("Details")
+
+// Kind implements Node.Kind.
+func (n *Details) Kind() gast.NodeKind {
+	return KindDetails
+}
+
+// NewDetails returns a new Details node.
+func NewDetails(open bool) *Details {
+	return &Details{
+		Open: open,
+	}
+}
+
+// A Summary struct represents a summary of the Details block.
+type Summary struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *Summary) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindSummary is a NodeKind of the Summary node.
+var KindSummary = gast.NewNodeKind("Summary")
+
+// Kind implements Node.Kind.
+func (n *Summary) Kind() gast.NodeKind {
+	return KindSummary
+}
+
+// NewSummary returns a new Summary node.
+func NewSummary() *Summary {
+	return &Summary{}
+}
@@ -0,0 +1,97 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// An Insert struct represents an inserted text like '{+text+}'.
+type Insert struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *Insert) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindInsert is a NodeKind of the Insert node.
+var KindInsert = gast.NewNodeKind("Insert")
+
+// Kind implements Node.Kind.
+func (n *Insert) Kind() gast.NodeKind {
+	return KindInsert
+}
+
+// NewInsert returns a new Insert node.
+func NewInsert() *Insert {
+	return &Insert{}
+}
+
+// A Delete struct represents a deleted text like '{-text-}'.
+type Delete struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *Delete) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindDelete is a NodeKind of the Delete node.
+var KindDelete = gast.NewNodeKind("Delete")
+
+// Kind implements Node.Kind.
+func (n *Delete) Kind() gast.NodeKind {
+	return KindDelete
+}
+
+// NewDelete returns a new Delete node.
+func NewDelete() *Delete {
+	return &Delete{}
+}
+
+// A Highlight struct represents a highlighted text like '{==text==}'.
+type Highlight struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *Highlight) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindHighlight is a NodeKind of the Highlight node.
+var KindHighlight = gast.NewNodeKind("Highlight")
+
+// Kind implements Node.Kind.
+func (n *Highlight) Kind() gast.NodeKind {
+	return KindHighlight
+}
+
+// NewHighlight returns a new Highlight node.
+func NewHighlight() *Highlight {
+	return &Highlight{}
+}
+
+// An EditComment struct represents an editorial comment like '{>>text<<}'.
+type EditComment struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *EditComment) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindEditComment is a NodeKind of the EditComment node.
+var KindEditComment = gast.NewNodeKind("EditComment")
+
+// Kind implements Node.Kind.
+func (n *EditComment) Kind() gast.NodeKind {
+	return KindEditComment
+}
+
+// NewEditComment returns a new EditComment node.
+func NewEditComment() *EditComment {
+	return &EditComment{}
+}
@@ -0,0 +1,34 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Graphviz struct represents a Graphviz DOT diagram like
+// "```dot". Lines of the node are the DOT source.
+type Graphviz struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *Graphviz) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// IsRaw implements Node.IsRaw.
+func (n *Graphviz) IsRaw() bool {
+	return true
+}
+
+// KindGraphviz is a NodeKind of the Graphviz node.
+var KindGraphviz = gast.NewNodeKind("Graphviz")
+
+// Kind implements Node.Kind.
+func (n *Graphviz) Kind() gast.NodeKind {
+	return KindGraphviz
+}
+
+// NewGraphviz returns a new Graphviz node.
+func NewGraphviz() *Graphviz {
+	return &Graphviz{}
+}
@@ -0,0 +1,40 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Hashtag struct represents a hashtag like '#topic'.
+type Hashtag struct {
+	gast.BaseInline
+
+	// Tag is a tag name without the leading '#'.
+	Tag []byte
+
+	// Destination is a destination URL of the hashtag.
+	// Destination is nil if the hashtag is not a link.
+	Destination []byte
+}
+
+// Dump implements Node.Dump.
+func (n *Hashtag) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Tag"] = string(n.Tag)
+	m["Destination"] = string(n.Destination)
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindHashtag is a NodeKind of the Hashtag node.
+var KindHashtag = gast.NewNodeKind("Hashtag")
+
+// Kind implements Node.Kind.
+func (n *Hashtag) Kind() gast.NodeKind {
+	return KindHashtag
+}
+
+// NewHashtag returns a new Hashtag node.
+func NewHashtag(tag []byte) *Hashtag {
+	return &Hashtag{
+		Tag: tag,
+	}
+}
@@ -0,0 +1,53 @@
+package ast
+
+import (
+	"fmt"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// An IssueLink struct represents a reference to an issue like '#123',
+// 'owner/repo#123' or a merge request like '!123'.
+type IssueLink struct {
+	gast.BaseInline
+
+	// Repository is a repository name like 'owner/repo'.
+	// Repository is nil if the reference points the current repository.
+	Repository []byte
+
+	// Number is an issue number.
+	Number []byte
+
+	// MergeRequest is true if the reference points a merge request.
+	MergeRequest bool
+
+	// Destination is a destination URL of the reference.
+	Destination []byte
+}
+
+// Dump implements Node.Dump.
+func (n *IssueLink) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Repository"] = string(n.Repository)
+	m["Number"] = string(n.Number)
+	m["MergeRequest"] = fmt.Sprintf("%v", n.MergeRequest)
+	m["Destination"] = string(n.Destination)
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindIssueLink is a NodeKind of the IssueLink node.
+var KindIssueLink = gast.NewNodeKind("IssueLink")
+
+// Kind implements Node.Kind.
+func (n *IssueLink) Kind() gast.NodeKind {
+	return KindIssueLink
+}
+
+// NewIssueLink returns a new IssueLink node.
+func NewIssueLink(repository, number []byte, mergeRequest bool) *IssueLink {
+	return &IssueLink{
+		Repository:   repository,
+		Number:       number,
+		MergeRequest: mergeRequest,
+	}
+}
@@ -0,0 +1,48 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A LinkCard struct represents a rich preview card of an external link.
+type LinkCard struct {
+	gast.BaseBlock
+
+	// Destination is an URL of the link.
+	Destination []byte
+
+	// Title is a title of the linked page.
+	Title []byte
+
+	// Description is a description of the linked page.
+	Description []byte
+
+	// Image is an URL of an image of the linked page.
+	Image []byte
+}
+
+// Dump implements Node.Dump.
+func (n *LinkCard) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Destination": string(n.Destination),
+		"Title":       string(n.Title),
+		"Description": string(n.Description),
+		"Image":       string(n.Image),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindLinkCard is a NodeKind of the LinkCard node.
+var KindLinkCard = gast.NewNodeKind("LinkCard")
+
+// Kind implements Node.Kind.
+func (n *LinkCard) Kind() gast.NodeKind {
+	return KindLinkCard
+}
+
+// NewLinkCard returns a new LinkCard node.
+func NewLinkCard(destination []byte) *LinkCard {
+	return &LinkCard{
+		Destination: destination,
+	}
+}
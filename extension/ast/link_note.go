@@ -0,0 +1,42 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A LinkNote struct represents a note of the preceding link that is
+// written as a title of the link.
+type LinkNote struct {
+	gast.BaseInline
+
+	// Destination is an URL of the link.
+	Destination []byte
+
+	// Note is a text of the note.
+	Note []byte
+}
+
+// Dump implements Node.Dump.
+func (n *LinkNote) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Destination": string(n.Destination),
+		"Note":        string(n.Note),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindLinkNote is a NodeKind of the LinkNote node.
+var KindLinkNote = gast.NewNodeKind("LinkNote")
+
+// Kind implements Node.Kind.
+func (n *LinkNote) Kind() gast.NodeKind {
+	return KindLinkNote
+}
+
+// NewLinkNote returns a new LinkNote node.
+func NewLinkNote(destination, note []byte) *LinkNote {
+	return &LinkNote{
+		Destination: destination,
+		Note:        note,
+	}
+}
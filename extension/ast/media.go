@@ -0,0 +1,69 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// MediaType is a type of embedded media.
+type MediaType int
+
+const (
+	// MediaVideo indicates a video that is rendered as a '<video>' element.
+	MediaVideo MediaType = iota + 1
+
+	// MediaAudio indicates an audio that is rendered as an '<audio>' element.
+	MediaAudio
+)
+
+func (t MediaType) String() string {
+	switch t {
+	case MediaVideo:
+		return "video"
+	case MediaAudio:
+		return "audio"
+	}
+	return ""
+}
+
+// A Media struct represents an embedded video or audio written as
+// an image like '![alt](video.mp4)'. Children of the node are an
+// alternative text of the media.
+type Media struct {
+	gast.BaseInline
+
+	// MediaType is a type of the media.
+	MediaType MediaType
+
+	// Destination is an URL of the media.
+	Destination []byte
+
+	// Title is a title of the media.
+	Title []byte
+}
+
+// Dump implements Node.Dump.
+func (n *Media) Dump(source []byte, level int) {
+	m := map[string]string{
+		"MediaType":   n.MediaType.String(),
+		"Destination": string(n.Destination),
+		"Title":       string(n.Title),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindMedia is a NodeKind of the Media node.
+var KindMedia = gast.NewNodeKind("Media")
+
+// Kind implements Node.Kind.
+func (n *Media) Kind() gast.NodeKind {
+	return KindMedia
+}
+
+// NewMedia returns a new Media node.
+func NewMedia(typ MediaType, destination, title []byte) *Media {
+	return &Media{
+		MediaType:   typ,
+		Destination: destination,
+		Title:       title,
+	}
+}
@@ -0,0 +1,51 @@
+package ast
+
+import (
+	"fmt"
+	"sort"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A ObsidianProperties struct represents an Obsidian properties block
+// between '---' lines at the start of the document.
+type ObsidianProperties struct {
+	gast.BaseBlock
+
+	// Properties are parsed properties.
+	Properties map[string]interface{}
+}
+
+// Dump implements Node.Dump.
+func (n *ObsidianProperties) Dump(source []byte, level int) {
+	m := map[string]string{}
+	keys := make([]string, 0, len(n.Properties))
+	for key := range n.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		m[key] = fmt.Sprintf("%v", n.Properties[key])
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// IsRaw implements Node.IsRaw.
+func (n *ObsidianProperties) IsRaw() bool {
+	return true
+}
+
+// KindObsidianProperties is a NodeKind of the ObsidianProperties node.
+var KindObsidianProperties = gast.NewNodeKind("ObsidianProperties")
+
+// Kind implements Node.Kind.
+func (n *ObsidianProperties) Kind() gast.NodeKind {
+	return KindObsidianProperties
+}
+
+// NewObsidianProperties returns a new ObsidianProperties node.
+func NewObsidianProperties() *ObsidianProperties {
+	return &ObsidianProperties{
+		Properties: map[string]interface{}{},
+	}
+}
@@ -0,0 +1,29 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A PageBreak struct represents a page break hint like '\pagebreak'
+// for printed outputs.
+type PageBreak struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *PageBreak) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindPageBreak is a NodeKind of the PageBreak node.
+var KindPageBreak = gast.NewNodeKind("PageBreak")
+
+// Kind implements Node.Kind.
+func (n *PageBreak) Kind() gast.NodeKind {
+	return KindPageBreak
+}
+
+// NewPageBreak returns a new PageBreak node.
+func NewPageBreak() *PageBreak {
+	return &PageBreak{}
+}
@@ -0,0 +1,41 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A RawBlock struct represents a raw content block like
+// "```{=html}" that is passed through to the output of the Format as is.
+type RawBlock struct {
+	gast.BaseBlock
+
+	// Format is a name of the output format like 'html' or 'latex'.
+	Format []byte
+}
+
+// Dump implements Node.Dump.
+func (n *RawBlock) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Format"] = string(n.Format)
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// IsRaw implements Node.IsRaw.
+func (n *RawBlock) IsRaw() bool {
+	return true
+}
+
+// KindRawBlock is a NodeKind of the RawBlock node.
+var KindRawBlock = gast.NewNodeKind("RawBlock")
+
+// Kind implements Node.Kind.
+func (n *RawBlock) Kind() gast.NodeKind {
+	return KindRawBlock
+}
+
+// NewRawBlock returns a new RawBlock node.
+func NewRawBlock(format []byte) *RawBlock {
+	return &RawBlock{
+		Format: format,
+	}
+}
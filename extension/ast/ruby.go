@@ -0,0 +1,75 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Ruby struct represents a ruby annotation like '{漢字}(かんじ)'.
+// A Ruby node has a RubyBase node and a RubyText node as its children.
+type Ruby struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *Ruby) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindRuby is a NodeKind of the Ruby node.
+var KindRuby = gast.NewNodeKind("Ruby")
+
+// Kind implements Node.Kind.
+func (n *Ruby) Kind() gast.NodeKind {
+	return KindRuby
+}
+
+// NewRuby returns a new Ruby node.
+func NewRuby() *Ruby {
+	return &Ruby{}
+}
+
+// A RubyBase struct represents a base text of the ruby annotation.
+type RubyBase struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *RubyBase) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindRubyBase is a NodeKind of the RubyBase node.
+var KindRubyBase = gast.NewNodeKind("RubyBase")
+
+// Kind implements Node.Kind.
+func (n *RubyBase) Kind() gast.NodeKind {
+	return KindRubyBase
+}
+
+// NewRubyBase returns a new RubyBase node.
+func NewRubyBase() *RubyBase {
+	return &RubyBase{}
+}
+
+// A RubyText struct represents an annotation text of the ruby annotation.
+type RubyText struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *RubyText) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindRubyText is a NodeKind of the RubyText node.
+var KindRubyText = gast.NewNodeKind("RubyText")
+
+// Kind implements Node.Kind.
+func (n *RubyText) Kind() gast.NodeKind {
+	return KindRubyText
+}
+
+// NewRubyText returns a new RubyText node.
+func NewRubyText() *RubyText {
+	return &RubyText{}
+}
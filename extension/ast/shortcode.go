@@ -0,0 +1,57 @@
+package ast
+
+import (
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// A Shortcode struct represents a Hugo shortcode like '{{< name >}}' or
+// '{{% name %}}'.
+type Shortcode struct {
+	gast.BaseInline
+
+	// Name is a name of the shortcode like 'figure'.
+	// Name of a closing shortcode like '{{< /figure >}}' starts with '/'.
+	Name []byte
+
+	// IsMarkdown is true if the shortcode is delimited by '{{%' and '%}}'.
+	IsMarkdown bool
+
+	// Segments are raw segments of the shortcode including delimiters.
+	Segments *text.Segments
+}
+
+// Dump implements Node.Dump.
+func (n *Shortcode) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Name"] = string(n.Name)
+	if n.IsMarkdown {
+		m["IsMarkdown"] = "true"
+	}
+	t := []string{}
+	for i := 0; i < n.Segments.Len(); i++ {
+		segment := n.Segments.At(i)
+		t = append(t, string(segment.Value(source)))
+	}
+	m["RawText"] = strings.Join(t, "")
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindShortcode is a NodeKind of the Shortcode node.
+var KindShortcode = gast.NewNodeKind("Shortcode")
+
+// Kind implements Node.Kind.
+func (n *Shortcode) Kind() gast.NodeKind {
+	return KindShortcode
+}
+
+// NewShortcode returns a new Shortcode node.
+func NewShortcode(name []byte, isMarkdown bool) *Shortcode {
+	return &Shortcode{
+		Name:       name,
+		IsMarkdown: isMarkdown,
+		Segments:   text.NewSegments(),
+	}
+}
@@ -0,0 +1,64 @@
+package ast
+
+import (
+	"fmt"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A SidenoteRef struct represents a reference to a sidenote.
+type SidenoteRef struct {
+	gast.BaseInline
+	Index int
+}
+
+// Dump implements Node.Dump.
+func (n *SidenoteRef) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Index"] = fmt.Sprintf("%v", n.Index)
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindSidenoteRef is a NodeKind of the SidenoteRef node.
+var KindSidenoteRef = gast.NewNodeKind("SidenoteRef")
+
+// Kind implements Node.Kind.
+func (n *SidenoteRef) Kind() gast.NodeKind {
+	return KindSidenoteRef
+}
+
+// NewSidenoteRef returns a new SidenoteRef node.
+func NewSidenoteRef(index int) *SidenoteRef {
+	return &SidenoteRef{
+		Index: index,
+	}
+}
+
+// A Sidenote struct represents a note that is placed beside the text
+// instead of the bottom of the document.
+type Sidenote struct {
+	gast.BaseInline
+	Index int
+}
+
+// Dump implements Node.Dump.
+func (n *Sidenote) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Index"] = fmt.Sprintf("%v", n.Index)
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindSidenote is a NodeKind of the Sidenote node.
+var KindSidenote = gast.NewNodeKind("Sidenote")
+
+// Kind implements Node.Kind.
+func (n *Sidenote) Kind() gast.NodeKind {
+	return KindSidenote
+}
+
+// NewSidenote returns a new Sidenote node.
+func NewSidenote(index int) *Sidenote {
+	return &Sidenote{
+		Index: index,
+	}
+}
@@ -0,0 +1,52 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Spoiler struct represents a spoiler text like '||text||'.
+type Spoiler struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *Spoiler) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindSpoiler is a NodeKind of the Spoiler node.
+var KindSpoiler = gast.NewNodeKind("Spoiler")
+
+// Kind implements Node.Kind.
+func (n *Spoiler) Kind() gast.NodeKind {
+	return KindSpoiler
+}
+
+// NewSpoiler returns a new Spoiler node.
+func NewSpoiler() *Spoiler {
+	return &Spoiler{}
+}
+
+// A SpoilerScript struct represents a script that reveals spoiler texts
+// when they are clicked.
+type SpoilerScript struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *SpoilerScript) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindSpoilerScript is a NodeKind of the SpoilerScript node.
+var KindSpoilerScript = gast.NewNodeKind("SpoilerScript")
+
+// Kind implements Node.Kind.
+func (n *SpoilerScript) Kind() gast.NodeKind {
+	return KindSpoilerScript
+}
+
+// NewSpoilerScript returns a new SpoilerScript node.
+func NewSpoilerScript() *SpoilerScript {
+	return &SpoilerScript{}
+}
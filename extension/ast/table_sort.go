@@ -0,0 +1,36 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A TableSortScript struct represents a script that makes sortable tables
+// sortable by clicking their headers.
+type TableSortScript struct {
+	gast.BaseBlock
+
+	// Script is a JavaScript source code.
+	Script []byte
+}
+
+// Dump implements Node.Dump.
+func (n *TableSortScript) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Script"] = string(n.Script)
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindTableSortScript is a NodeKind of the TableSortScript node.
+var KindTableSortScript = gast.NewNodeKind("TableSortScript")
+
+// Kind implements Node.Kind.
+func (n *TableSortScript) Kind() gast.NodeKind {
+	return KindTableSortScript
+}
+
+// NewTableSortScript returns a new TableSortScript node.
+func NewTableSortScript(script []byte) *TableSortScript {
+	return &TableSortScript{
+		Script: script,
+	}
+}
@@ -0,0 +1,29 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A TableOfContents struct represents a table of contents of the document.
+// A TableOfContents node has a List node that contains links to headings.
+type TableOfContents struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *TableOfContents) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindTableOfContents is a NodeKind of the TableOfContents node.
+var KindTableOfContents = gast.NewNodeKind("TableOfContents")
+
+// Kind implements Node.Kind.
+func (n *TableOfContents) Kind() gast.NodeKind {
+	return KindTableOfContents
+}
+
+// NewTableOfContents returns a new TableOfContents node.
+func NewTableOfContents() *TableOfContents {
+	return &TableOfContents{}
+}
@@ -0,0 +1,373 @@
+package extension
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A BibEntry struct represents a cited work.
+type BibEntry struct {
+	// Authors is a list of authors like 'Knuth, Donald E.'.
+	Authors []string
+
+	// Title is a title of the work.
+	Title string
+
+	// Container is a title of the journal or the book that contains the work.
+	Container string
+
+	// Publisher is a publisher of the work.
+	Publisher string
+
+	// Year is a year of the publication.
+	Year string
+
+	// URL is an URL of the work.
+	URL string
+}
+
+// A BibliographyProvider interface supplies bibliography data
+// of citation keys.
+type BibliographyProvider interface {
+	// Lookup returns a BibEntry of the given citation key.
+	Lookup(key string) (BibEntry, bool)
+}
+
+var citationListKey = parser.NewContextKey()
+
+type citationParser struct {
+}
+
+var defaultCitationParser = &citationParser{}
+
+// NewCitationParser returns a new parser.InlineParser that can parse
+// citations like '[@key]' and '[@key, pp. 10-12]'.
+func NewCitationParser() parser.InlineParser {
+	return defaultCitationParser
+}
+
+func (s *citationParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+func isCitationKeyChar(c byte) bool {
+	return util.IsAlphaNumeric(c) || c == '_' || c == '-' || c == ':' || c == '.' || c == '/'
+}
+
+func (s *citationParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, segment := block.PeekLine()
+	if len(line) < 3 || line[1] != '@' {
+		return nil
+	}
+	pos := 2
+	for ; pos < len(line) && isCitationKeyChar(line[pos]); pos++ {
+	}
+	if pos == 2 || pos >= len(line) {
+		return nil
+	}
+	key := line[2:pos]
+	var locator []byte
+	if line[pos] == ',' {
+		start := pos + 1
+		for pos++; pos < len(line) && line[pos] != ']'; pos++ {
+			if line[pos] == '[' || line[pos] == '\n' {
+				return nil
+			}
+		}
+		if pos >= len(line) {
+			return nil
+		}
+		locator = util.TrimRightSpace(util.TrimLeftSpace(line[start:pos]))
+		if len(locator) == 0 {
+			locator = nil
+		}
+	}
+	if line[pos] != ']' {
+		return nil
+	}
+	// '[@key](url)' is a link
+	if pos+1 < len(line) && line[pos+1] == '(' {
+		return nil
+	}
+	node := ast.NewCitation(key, locator)
+	node.AppendChild(node, gast.NewTextSegment(text.NewSegment(segment.Start, segment.Start+pos+1)))
+	block.Advance(pos + 1)
+
+	var list []*ast.Citation
+	if tmp := pc.Get(citationListKey); tmp != nil {
+		list = tmp.([]*ast.Citation)
+	}
+	pc.Set(citationListKey, append(list, node))
+	return node
+}
+
+// A CitationConfig struct is a data structure that holds configuration of the
+// Citation extension.
+type CitationConfig struct {
+	// Provider is a BibliographyProvider that supplies cited works.
+	// Citations are rendered as is if Provider is nil.
+	Provider BibliographyProvider
+}
+
+const optBibliographyProvider parser.OptionName = "BibliographyProvider"
+
+// SetOption implements SetOptioner.
+func (c *CitationConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optBibliographyProvider:
+		c.Provider = value.(BibliographyProvider)
+	}
+}
+
+// A CitationOption interface sets options for the Citation extension.
+type CitationOption interface {
+	parser.Option
+	SetCitationOption(*CitationConfig)
+}
+
+type withBibliographyProvider struct {
+	value BibliographyProvider
+}
+
+func (o *withBibliographyProvider) SetParserOption(c *parser.Config) {
+	c.Options[optBibliographyProvider] = o.value
+}
+
+func (o *withBibliographyProvider) SetCitationOption(c *CitationConfig) {
+	c.Provider = o.value
+}
+
+// WithBibliographyProvider is a functional option that specify a
+// BibliographyProvider that supplies cited works.
+func WithBibliographyProvider(value BibliographyProvider) CitationOption {
+	return &withBibliographyProvider{value}
+}
+
+type citationASTTransformer struct {
+	CitationConfig
+}
+
+// NewCitationASTTransformer returns a new parser.ASTTransformer that
+// resolves citations and appends a bibliography to the document.
+func NewCitationASTTransformer(opts ...CitationOption) parser.ASTTransformer {
+	t := &citationASTTransformer{}
+	for _, o := range opts {
+		o.SetCitationOption(&t.CitationConfig)
+	}
+	return t
+}
+
+func (a *citationASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	var list []*ast.Citation
+	if tmp := pc.Get(citationListKey); tmp != nil {
+		list = tmp.([]*ast.Citation)
+	}
+	pc.Set(citationListKey, nil)
+	if len(list) == 0 || a.Provider == nil {
+		return
+	}
+
+	bibliography := ast.NewBibliography()
+	cited := map[string]bool{}
+	for _, citation := range list {
+		key := string(citation.Key)
+		entry, ok := a.Provider.Lookup(key)
+		if !ok {
+			continue
+		}
+		citation.RemoveChildren(citation)
+		link := gast.NewLink()
+		link.Destination = []byte("#ref-" + key)
+		link.AppendChild(link, gast.NewString([]byte(citationLabel(key, entry, citation.Locator))))
+		citation.AppendChild(citation, link)
+
+		if cited[key] {
+			continue
+		}
+		cited[key] = true
+		item := ast.NewBibliographyItem(citation.Key)
+		item.AppendChild(item, gast.NewString([]byte(bibliographyText(entry))))
+		if len(entry.URL) != 0 {
+			item.AppendChild(item, gast.NewString([]byte(" ")))
+			link := gast.NewLink()
+			link.Destination = []byte(entry.URL)
+			link.AppendChild(link, gast.NewString([]byte(entry.URL)))
+			item.AppendChild(item, link)
+		}
+		bibliography.AppendChild(bibliography, item)
+	}
+	if bibliography.HasChildren() {
+		node.AppendChild(node, bibliography)
+	}
+}
+
+func authorSurname(author string) string {
+	if i := strings.IndexByte(author, ','); i > -1 {
+		return strings.TrimSpace(author[:i])
+	}
+	if i := strings.LastIndexByte(author, ' '); i > -1 {
+		return author[i+1:]
+	}
+	return author
+}
+
+func citationLabel(key string, entry BibEntry, locator []byte) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	switch len(entry.Authors) {
+	case 0:
+		if len(entry.Title) != 0 {
+			b.WriteString(entry.Title)
+		} else {
+			b.WriteString(key)
+		}
+	case 1:
+		b.WriteString(authorSurname(entry.Authors[0]))
+	case 2:
+		b.WriteString(authorSurname(entry.Authors[0]))
+		b.WriteString(" and ")
+		b.WriteString(authorSurname(entry.Authors[1]))
+	default:
+		b.WriteString(authorSurname(entry.Authors[0]))
+		b.WriteString(" et al.")
+	}
+	if len(entry.Year) != 0 {
+		b.WriteByte(' ')
+		b.WriteString(entry.Year)
+	}
+	if len(locator) != 0 {
+		b.WriteString(", ")
+		b.Write(locator)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func bibliographyText(entry BibEntry) string {
+	var b strings.Builder
+	if len(entry.Authors) != 0 {
+		b.WriteString(strings.Join(entry.Authors, "; "))
+	}
+	if len(entry.Year) != 0 {
+		if b.Len() != 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString("(" + entry.Year + ").")
+	} else if b.Len() != 0 {
+		b.WriteByte('.')
+	}
+	for _, s := range []string{entry.Title, entry.Container, entry.Publisher} {
+		if len(s) == 0 {
+			continue
+		}
+		if b.Len() != 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s)
+		b.WriteByte('.')
+	}
+	return b.String()
+}
+
+// CitationHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Citation nodes.
+type CitationHTMLRenderer struct {
+	html.Config
+}
+
+// NewCitationHTMLRenderer returns a new CitationHTMLRenderer.
+func NewCitationHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &CitationHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *CitationHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindCitation, r.renderCitation)
+	reg.Register(ast.KindBibliography, r.renderBibliography)
+	reg.Register(ast.KindBibliographyItem, r.renderBibliographyItem)
+}
+
+func (r *CitationHTMLRenderer) renderCitation(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Citation)
+		_, _ = w.WriteString(`<cite class="citation" data-cites="`)
+		_, _ = w.Write(util.EscapeHTML(n.Key))
+		_, _ = w.WriteString(`">`)
+	} else {
+		_, _ = w.WriteString("</cite>")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *CitationHTMLRenderer) renderBibliography(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<section class="bibliography" role="doc-bibliography"`)
+		if node.Attributes() != nil {
+			html.RenderAttributes(w, node, html.GlobalAttributeFilter)
+		}
+		_, _ = w.WriteString(">\n<ol>\n")
+	} else {
+		_, _ = w.WriteString("</ol>\n</section>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *CitationHTMLRenderer) renderBibliographyItem(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.BibliographyItem)
+		_, _ = w.WriteString(`<li id="ref-`)
+		_, _ = w.Write(util.EscapeHTML(n.Key))
+		_, _ = w.WriteString(`">`)
+	} else {
+		_, _ = w.WriteString("</li>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+type citation struct {
+	options []CitationOption
+}
+
+// Citation is an extension that allow you to use citations like '[@key]'.
+// Citation does not render a bibliography because it has no
+// BibliographyProvider. Use NewCitation with WithBibliographyProvider
+// to render it.
+var Citation = &citation{}
+
+// NewCitation returns a new extension with given options.
+func NewCitation(opts ...CitationOption) goldmark.Extender {
+	return &citation{
+		options: opts,
+	}
+}
+
+func (e *citation) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithInlineParsers(
+			util.Prioritized(NewCitationParser(), 102),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(NewCitationASTTransformer(e.options...), 999),
+		),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewCitationHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,54 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+type testBibliographyProvider map[string]BibEntry
+
+func (p testBibliographyProvider) Lookup(key string) (BibEntry, bool) {
+	entry, ok := p[key]
+	return entry, ok
+}
+
+func TestCitation(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewCitation(
+				WithBibliographyProvider(testBibliographyProvider{
+					"knuth1984": {
+						Authors:   []string{"Knuth, Donald E."},
+						Title:     "Literate Programming",
+						Container: "The Computer Journal",
+						Year:      "1984",
+					},
+					"go2015": {
+						Authors:   []string{"Alan A. A. Donovan", "Brian W. Kernighan"},
+						Title:     "The Go Programming Language",
+						Publisher: "Addison-Wesley",
+						Year:      "2015",
+						URL:       "https://www.gopl.io/",
+					},
+				}),
+			),
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/citation.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestCitationWithoutProvider(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Citation,
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "without provider",
+		Markdown:    "See [@knuth1984, p. 97].",
+		Expected:    `<p>See <cite class="citation" data-cites="knuth1984">[@knuth1984, p. 97]</cite>.</p>`,
+	}, t)
+}
@@ -0,0 +1,204 @@
+package extension
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// CrossRefSourcePathKey is a ContextKey for a slash separated path of the
+// Markdown file being parsed like 'docs/guide.md'. Relative links are
+// resolved against this path.
+//
+//	pc := parser.NewContext()
+//	pc.Set(extension.CrossRefSourcePathKey, "docs/guide.md")
+//	markdown.Convert(source, &buf, parser.WithContext(pc))
+var CrossRefSourcePathKey = parser.NewContextKey()
+
+// A CrossRefResolver interface resolves links between Markdown files
+// into URLs of output files.
+type CrossRefResolver interface {
+	// Resolve returns an output URL of the given slash separated path
+	// of a Markdown file and the given fragment like 'heading'.
+	// fragment is empty if a link has no fragments.
+	// Resolve returns an error if the file or the fragment does not exist.
+	Resolve(path, fragment string) (string, error)
+}
+
+// A CrossRefError struct is an error that is added to the parser.Context
+// if a link can not be resolved by the CrossRefResolver and no fallback
+// URL is given. goldmark.Markdown.Convert returns the first error.
+type CrossRefError struct {
+	// Destination is a destination of the link as written.
+	Destination string
+
+	// Err is an error returned by the CrossRefResolver.
+	Err error
+}
+
+// Error implements error.Error.
+func (e *CrossRefError) Error() string {
+	return fmt.Sprintf("goldmark: unresolved link %q: %v", e.Destination, e.Err)
+}
+
+// Unwrap returns an error returned by the CrossRefResolver.
+func (e *CrossRefError) Unwrap() error {
+	return e.Err
+}
+
+// A CrossRefConfig struct is a data structure that holds configuration of the
+// CrossRef extension.
+type CrossRefConfig struct {
+	// Resolver is a CrossRefResolver that resolves links.
+	// Links are not rewritten if Resolver is nil.
+	Resolver CrossRefResolver
+
+	// FallbackURL is an URL that is set to unresolved links.
+	// If FallbackURL is empty, unresolved links are kept as they are and
+	// a CrossRefError is added to the parser.Context for each of them.
+	FallbackURL string
+}
+
+const optCrossRefResolver parser.OptionName = "CrossRefResolver"
+
+const optCrossRefFallbackURL parser.OptionName = "CrossRefFallbackURL"
+
+// SetOption implements SetOptioner.
+func (c *CrossRefConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optCrossRefResolver:
+		c.Resolver = value.(CrossRefResolver)
+	case optCrossRefFallbackURL:
+		c.FallbackURL = value.(string)
+	}
+}
+
+// A CrossRefOption interface sets options for the CrossRef extension.
+type CrossRefOption interface {
+	parser.Option
+	SetCrossRefOption(*CrossRefConfig)
+}
+
+type withCrossRefResolver struct {
+	value CrossRefResolver
+}
+
+func (o *withCrossRefResolver) SetParserOption(c *parser.Config) {
+	c.Options[optCrossRefResolver] = o.value
+}
+
+func (o *withCrossRefResolver) SetCrossRefOption(c *CrossRefConfig) {
+	c.Resolver = o.value
+}
+
+// WithCrossRefResolver is a functional option that specify a
+// CrossRefResolver that resolves links between Markdown files.
+func WithCrossRefResolver(value CrossRefResolver) CrossRefOption {
+	return &withCrossRefResolver{value}
+}
+
+type withCrossRefFallbackURL struct {
+	value string
+}
+
+func (o *withCrossRefFallbackURL) SetParserOption(c *parser.Config) {
+	c.Options[optCrossRefFallbackURL] = o.value
+}
+
+func (o *withCrossRefFallbackURL) SetCrossRefOption(c *CrossRefConfig) {
+	c.FallbackURL = o.value
+}
+
+// WithCrossRefFallbackURL is a functional option that specify an URL
+// that is set to links those can not be resolved like '#'.
+func WithCrossRefFallbackURL(value string) CrossRefOption {
+	return &withCrossRefFallbackURL{value}
+}
+
+type crossRefASTTransformer struct {
+	CrossRefConfig
+}
+
+// NewCrossRefASTTransformer returns a new parser.ASTTransformer that
+// rewrites links to Markdown files into URLs of output files.
+func NewCrossRefASTTransformer(opts ...CrossRefOption) parser.ASTTransformer {
+	t := &crossRefASTTransformer{}
+	for _, o := range opts {
+		o.SetCrossRefOption(&t.CrossRefConfig)
+	}
+	return t
+}
+
+// crossRefTarget returns a path and a fragment of the given destination
+// if it points to a Markdown file.
+func crossRefTarget(destination []byte, from string) (string, string, bool) {
+	u, err := url.Parse(string(destination))
+	if err != nil || len(u.Scheme) != 0 || len(u.Host) != 0 {
+		return "", "", false
+	}
+	if !strings.HasSuffix(strings.ToLower(u.Path), ".md") {
+		return "", "", false
+	}
+	p := u.Path
+	if !strings.HasPrefix(p, "/") {
+		p = path.Join(path.Dir(from), p)
+	}
+	return strings.TrimPrefix(path.Clean(p), "/"), u.Fragment, true
+}
+
+func (a *crossRefASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	if a.Resolver == nil {
+		return
+	}
+	from, _ := pc.Get(CrossRefSourcePathKey).(string)
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering || n.Kind() != gast.KindLink {
+			return gast.WalkContinue, nil
+		}
+		link := n.(*gast.Link)
+		p, fragment, ok := crossRefTarget(link.Destination, from)
+		if !ok {
+			return gast.WalkContinue, nil
+		}
+		resolved, err := a.Resolver.Resolve(p, fragment)
+		if err != nil {
+			if len(a.FallbackURL) != 0 {
+				link.Destination = []byte(a.FallbackURL)
+			} else {
+				parser.AddError(pc, &CrossRefError{Destination: string(link.Destination), Err: err})
+			}
+			return gast.WalkContinue, nil
+		}
+		link.Destination = []byte(resolved)
+		return gast.WalkContinue, nil
+	})
+}
+
+type crossRef struct {
+	options []CrossRefOption
+}
+
+// CrossRef is an extension that rewrites links to other Markdown files
+// like '[See here](../other.md#heading)' into URLs of output files.
+// Use NewCrossRef with WithCrossRefResolver to resolve links.
+var CrossRef = &crossRef{}
+
+// NewCrossRef returns a new extension with given options.
+func NewCrossRef(opts ...CrossRefOption) goldmark.Extender {
+	return &crossRef{
+		options: opts,
+	}
+}
+
+func (e *crossRef) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewCrossRefASTTransformer(e.options...), 999),
+	))
+}
@@ -0,0 +1,90 @@
+package extension
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+type testCrossRefResolver map[string]string
+
+func (r testCrossRefResolver) Resolve(path, fragment string) (string, error) {
+	u, ok := r[path]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	if len(fragment) != 0 {
+		u += "#" + fragment
+	}
+	return u, nil
+}
+
+func TestCrossRef(t *testing.T) {
+	resolver := testCrossRefResolver{
+		"docs/other.md": "/docs/other/",
+		"index.md":      "/",
+	}
+	source := []byte(`[a](../other.md#heading) [b](/index.md) [c](missing.md)
+[d](https://example.com/a.md) [e](#local) [f](image.png)
+`)
+	cases := []struct {
+		options  []CrossRefOption
+		expected string
+		errors   int
+	}{
+		{
+			[]CrossRefOption{WithCrossRefResolver(resolver)},
+			`<p><a href="/docs/other/#heading">a</a> <a href="/">b</a> <a href="missing.md">c</a>
+<a href="https://example.com/a.md">d</a> <a href="#local">e</a> <a href="image.png">f</a></p>
+`,
+			1,
+		},
+		{
+			[]CrossRefOption{WithCrossRefResolver(resolver), WithCrossRefFallbackURL("#")},
+			`<p><a href="/docs/other/#heading">a</a> <a href="/">b</a> <a href="#">c</a>
+<a href="https://example.com/a.md">d</a> <a href="#local">e</a> <a href="image.png">f</a></p>
+`,
+			0,
+		},
+		{
+			nil,
+			`<p><a href="../other.md#heading">a</a> <a href="/index.md">b</a> <a href="missing.md">c</a>
+<a href="https://example.com/a.md">d</a> <a href="#local">e</a> <a href="image.png">f</a></p>
+`,
+			0,
+		},
+	}
+	for i, c := range cases {
+		markdown := goldmark.New(goldmark.WithExtensions(NewCrossRef(c.options...)))
+		pc := parser.NewContext()
+		pc.Set(CrossRefSourcePathKey, "docs/guide/page.md")
+		doc := markdown.Parser().Parse(text.NewReader(source), parser.WithContext(pc))
+		var b bytes.Buffer
+		if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != c.expected {
+			t.Errorf("%d: expected %q, but got %q", i, c.expected, b.String())
+		}
+		if len(parser.Errors(pc)) != c.errors {
+			t.Errorf("%d: expected %d errors, but got %v", i, c.errors, parser.Errors(pc))
+		}
+		for _, err := range parser.Errors(pc) {
+			var crossRefErr *CrossRefError
+			if !errors.As(err, &crossRefErr) || crossRefErr.Destination != "missing.md" {
+				t.Errorf("%d: unexpected error: %v", i, err)
+			}
+		}
+	}
+
+	markdown := goldmark.New(goldmark.WithExtensions(NewCrossRef(WithCrossRefResolver(resolver))))
+	var b bytes.Buffer
+	var crossRefErr *CrossRefError
+	if err := markdown.Convert(source, &b); !errors.As(err, &crossRefErr) {
+		t.Errorf("Convert should return a CrossRefError, but got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package extension
+
+import (
+	"bytes"
+	"encoding/csv"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A CSVTableConfig struct is a data structure that holds configuration of the
+// CSVTable extension.
+type CSVTableConfig struct {
+	// Comma is a field delimiter.
+	Comma rune
+
+	// HasHeader indicates that the first record is a header row.
+	HasHeader bool
+}
+
+const optCSVComma parser.OptionName = "CSVComma"
+
+const optCSVHasHeader parser.OptionName = "CSVHasHeader"
+
+// SetOption implements SetOptioner.
+func (c *CSVTableConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optCSVComma:
+		c.Comma = value.(rune)
+	case optCSVHasHeader:
+		c.HasHeader = value.(bool)
+	}
+}
+
+// A CSVTableOption interface sets options for the CSVTable extension.
+type CSVTableOption interface {
+	parser.Option
+	SetCSVTableOption(*CSVTableConfig)
+}
+
+type withCSVComma struct {
+	value rune
+}
+
+func (o *withCSVComma) SetParserOption(c *parser.Config) {
+	c.Options[optCSVComma] = o.value
+}
+
+func (o *withCSVComma) SetCSVTableOption(c *CSVTableConfig) {
+	c.Comma = o.value
+}
+
+// WithCSVComma is a functional option that specify a field delimiter
+// like ';'. This defaults to ','.
+func WithCSVComma(value rune) CSVTableOption {
+	return &withCSVComma{value}
+}
+
+type withCSVHasHeader struct {
+	value bool
+}
+
+func (o *withCSVHasHeader) SetParserOption(c *parser.Config) {
+	c.Options[optCSVHasHeader] = o.value
+}
+
+func (o *withCSVHasHeader) SetCSVTableOption(c *CSVTableConfig) {
+	c.HasHeader = o.value
+}
+
+// WithCSVHasHeader is a functional option that indicates whether the
+// first record is a header row. This defaults to true.
+func WithCSVHasHeader(value bool) CSVTableOption {
+	return &withCSVHasHeader{value}
+}
+
+type csvTableASTTransformer struct {
+	CSVTableConfig
+}
+
+// NewCSVTableASTTransformer returns a new parser.ASTTransformer that
+// converts fenced code blocks those language is 'csv' into tables.
+func NewCSVTableASTTransformer(opts ...CSVTableOption) parser.ASTTransformer {
+	t := &csvTableASTTransformer{
+		CSVTableConfig: CSVTableConfig{
+			Comma:     ',',
+			HasHeader: true,
+		},
+	}
+	for _, o := range opts {
+		o.SetCSVTableOption(&t.CSVTableConfig)
+	}
+	return t
+}
+
+// fencedCodeBlocks returns fenced code blocks those language is lang.
+func fencedCodeBlocks(root gast.Node, source []byte, lang string) []*gast.FencedCodeBlock {
+	var blocks []*gast.FencedCodeBlock
+	_ = gast.Walk(root, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		if b, ok := n.(*gast.FencedCodeBlock); ok {
+			if string(b.Language(source)) == lang {
+				blocks = append(blocks, b)
+			}
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	return blocks
+}
+
+// linesValue returns contents of lines of the given block.
+func linesValue(n gast.Node, source []byte) []byte {
+	var buf bytes.Buffer
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		buf.Write(segment.Value(source))
+	}
+	return buf.Bytes()
+}
+
+// newTableFromRecords returns a new Table that has given records.
+// Rows are padded with empty cells to have same number of cells.
+// Cells have raw Strings, so they are not parsed as Markdown.
+func newTableFromRecords(records [][]string, hasHeader bool) *ast.Table {
+	columns := 0
+	for _, record := range records {
+		if len(record) > columns {
+			columns = len(record)
+		}
+	}
+	alignments := make([]ast.Alignment, columns)
+	for i := range alignments {
+		alignments[i] = ast.AlignNone
+	}
+	table := ast.NewTable()
+	table.Alignments = alignments
+	for i, record := range records {
+		row := ast.NewTableRow(alignments)
+		for j := 0; j < columns; j++ {
+			cell := ast.NewTableCell()
+			cell.Alignment = ast.AlignNone
+			if j < len(record) && len(record[j]) != 0 {
+				s := gast.NewString([]byte(record[j]))
+				s.SetRaw(true)
+				cell.AppendChild(cell, s)
+			}
+			row.AppendChild(row, cell)
+		}
+		if i == 0 && hasHeader {
+			table.AppendChild(table, ast.NewTableHeader(row))
+		} else {
+			table.AppendChild(table, row)
+		}
+	}
+	return table
+}
+
+func (a *csvTableASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	for _, block := range fencedCodeBlocks(node, source, "csv") {
+		r := csv.NewReader(bytes.NewReader(linesValue(block, source)))
+		r.Comma = a.Comma
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil || len(records) == 0 {
+			continue
+		}
+		block.Parent().ReplaceChild(block.Parent(), block, newTableFromRecords(records, a.HasHeader))
+	}
+}
+
+type csvTable struct {
+	options []CSVTableOption
+}
+
+// CSVTable is an extension that converts fenced code blocks those language
+// is 'csv' into tables. Tables are rendered by a renderer for tables like
+// the one of the Table extension. Blocks those are not valid CSV are
+// rendered as code blocks.
+var CSVTable = &csvTable{}
+
+// NewCSVTable returns a new extension with given options.
+func NewCSVTable(opts ...CSVTableOption) goldmark.Extender {
+	return &csvTable{
+		options: opts,
+	}
+}
+
+func (e *csvTable) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewCSVTableASTTransformer(e.options...), 999),
+	))
+}
@@ -0,0 +1,43 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestCSVTable(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Table,
+			CSVTable,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/csv_table.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestCSVTableOptions(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Table,
+			NewCSVTable(WithCSVComma(';'), WithCSVHasHeader(false)),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:       1,
+		Markdown: "```csv\na;b\nc;d\n```",
+		Expected: `<table>
+<tbody>
+<tr>
+<td>a</td>
+<td>b</td>
+</tr>
+<tr>
+<td>c</td>
+<td>d</td>
+</tr>
+</tbody>
+</table>`,
+	}, t)
+}
@@ -0,0 +1,273 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type detailsBlockParser struct {
+}
+
+var defaultDetailsBlockParser = &detailsBlockParser{}
+
+// NewDetailsBlockParser returns a new parser.BlockParser that can parse
+// details blocks like '??? summary'. A details block is closed by a
+// line that consists of '???' .
+func NewDetailsBlockParser() parser.BlockParser {
+	return defaultDetailsBlockParser
+}
+
+func (b *detailsBlockParser) Trigger() []byte {
+	return []byte{'?'}
+}
+
+// detailsMarker returns a position after the '???' marker in the line.
+// detailsMarker returns -1 if the line does not start with the marker.
+func detailsMarker(line []byte, offset int) int {
+	w, pos := util.IndentWidth(line, offset)
+	if w > 3 || pos+3 > len(line) || line[pos] != '?' || line[pos+1] != '?' || line[pos+2] != '?' {
+		return -1
+	}
+	return pos + 3
+}
+
+func (b *detailsBlockParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := detailsMarker(line, reader.LineOffset())
+	if pos < 0 || pos >= len(line) {
+		return nil, parser.NoChildren
+	}
+	open := false
+	if line[pos] == '+' {
+		open = true
+		pos++
+	}
+	if pos >= len(line) || !util.IsSpace(line[pos]) {
+		return nil, parser.NoChildren
+	}
+	start := pos + util.TrimLeftSpaceLength(line[pos:])
+	stop := len(line) - util.TrimRightSpaceLength(line)
+	if start >= stop {
+		return nil, parser.NoChildren
+	}
+	node := ast.NewDetails(open)
+	summary := ast.NewSummary()
+	summary.Lines().Append(text.NewSegment(segment.Start+start, segment.Start+stop))
+	node.AppendChild(node, summary)
+	reader.Advance(stop)
+	return node, parser.HasChildren
+}
+
+func (b *detailsBlockParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	pos := detailsMarker(line, reader.LineOffset())
+	if pos < 0 || !util.IsBlank(line[pos:]) || !b.isInnermost(node, pc) {
+		return parser.Continue | parser.HasChildren
+	}
+	newline := 1
+	if line[len(line)-1] != '\n' {
+		newline = 0
+	}
+	reader.Advance(segment.Stop - segment.Start - newline + segment.Padding)
+	return parser.Close
+}
+
+// isInnermost returns true if the given node is the innermost opened
+// details block and is not in the middle of a code block.
+func (b *detailsBlockParser) isInnermost(node gast.Node, pc parser.Context) bool {
+	blocks := pc.OpenedBlocks()
+	found := false
+	for _, block := range blocks {
+		if block.Node == node {
+			found = true
+			continue
+		}
+		if found && block.Node.Kind() == ast.KindDetails {
+			return false
+		}
+	}
+	return blocks[len(blocks)-1].Node.Kind() != gast.KindFencedCodeBlock
+}
+
+func (b *detailsBlockParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	// nothing to do
+}
+
+func (b *detailsBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *detailsBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// A DetailsConfig struct has configurations for the HTML based renderers.
+type DetailsConfig struct {
+	html.Config
+
+	// Renderer is a function that renders Details nodes instead of
+	// the default renderer.
+	Renderer renderer.NodeRendererFunc
+}
+
+// DetailsOption interface is a functional option interface for the extension.
+type DetailsOption interface {
+	renderer.Option
+	// SetDetailsOption sets given option to the extension.
+	SetDetailsOption(*DetailsConfig)
+}
+
+// NewDetailsConfig returns a new Config with defaults.
+func NewDetailsConfig() DetailsConfig {
+	return DetailsConfig{
+		Config: html.NewConfig(),
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *DetailsConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optDetailsRenderer:
+		c.Renderer = value.(renderer.NodeRendererFunc)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+type withDetailsHTMLOptions struct {
+	value []html.Option
+}
+
+func (o *withDetailsHTMLOptions) SetConfig(c *renderer.Config) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.(renderer.Option).SetConfig(c)
+		}
+	}
+}
+
+func (o *withDetailsHTMLOptions) SetDetailsOption(c *DetailsConfig) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.SetHTMLOption(&c.Config)
+		}
+	}
+}
+
+// WithDetailsHTMLOptions is functional option that wraps goldmark HTMLRenderer options.
+func WithDetailsHTMLOptions(opts ...html.Option) DetailsOption {
+	return &withDetailsHTMLOptions{opts}
+}
+
+const optDetailsRenderer renderer.OptionName = "DetailsRenderer"
+
+type withDetailsRenderer struct {
+	value renderer.NodeRendererFunc
+}
+
+func (o *withDetailsRenderer) SetConfig(c *renderer.Config) {
+	c.Options[optDetailsRenderer] = o.value
+}
+
+func (o *withDetailsRenderer) SetDetailsOption(c *DetailsConfig) {
+	c.Renderer = o.value
+}
+
+// WithDetailsRenderer is a functional option that specify a function
+// that renders Details nodes instead of the default renderer.
+// Summary nodes are rendered by the default renderer.
+func WithDetailsRenderer(f renderer.NodeRendererFunc) DetailsOption {
+	return &withDetailsRenderer{f}
+}
+
+// DetailsHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Details nodes.
+type DetailsHTMLRenderer struct {
+	DetailsConfig
+}
+
+// NewDetailsHTMLRenderer returns a new DetailsHTMLRenderer.
+func NewDetailsHTMLRenderer(opts ...DetailsOption) renderer.NodeRenderer {
+	r := &DetailsHTMLRenderer{
+		DetailsConfig: NewDetailsConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetDetailsOption(&r.DetailsConfig)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *DetailsHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	if r.Renderer != nil {
+		reg.Register(ast.KindDetails, r.Renderer)
+	} else {
+		reg.Register(ast.KindDetails, r.renderDetails)
+	}
+	reg.Register(ast.KindSummary, r.renderSummary)
+}
+
+// DetailsAttributeFilter defines attribute names which details elements can have.
+var DetailsAttributeFilter = html.GlobalAttributeFilter.Extend(
+	[]byte("open"),
+)
+
+func (r *DetailsHTMLRenderer) renderDetails(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Details)
+		_, _ = w.WriteString("<details")
+		if n.Open {
+			_, _ = w.WriteString(" open")
+		}
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, DetailsAttributeFilter)
+		}
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</details>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *DetailsHTMLRenderer) renderSummary(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<summary>")
+	} else {
+		_, _ = w.WriteString("</summary>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+type details struct {
+	options []DetailsOption
+}
+
+// Details is an extension that allow you to use collapsible details blocks
+// like '??? summary' .
+var Details = &details{
+	options: []DetailsOption{},
+}
+
+// NewDetails returns a new extension with given options.
+func NewDetails(opts ...DetailsOption) goldmark.Extender {
+	return &details{
+		options: opts,
+	}
+}
+
+func (e *details) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithBlockParsers(
+		util.Prioritized(NewDetailsBlockParser(), 800),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewDetailsHTMLRenderer(e.options...), 500),
+	))
+}
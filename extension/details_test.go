@@ -0,0 +1,49 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestDetails(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			Details,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/details.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestDetailsRenderer(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewDetails(
+				WithDetailsRenderer(func(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+					if entering {
+						_, _ = w.WriteString("<details class=\"note\">\n")
+					} else {
+						_, _ = w.WriteString("</details>\n")
+					}
+					return gast.WalkContinue, nil
+				}),
+			),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "custom renderer",
+		Markdown:    "??? Note\nbody\n???",
+		Expected: `<details class="note">
+<summary>Note</summary>
+<p>body</p>
+</details>`,
+	}, t)
+}
@@ -0,0 +1,59 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// DiscordHeadingHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Heading nodes as paragraphs, because Discord messages do not
+// have headings.
+type DiscordHeadingHTMLRenderer struct {
+	html.Config
+}
+
+// NewDiscordHeadingHTMLRenderer returns a new DiscordHeadingHTMLRenderer.
+func NewDiscordHeadingHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &DiscordHeadingHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *DiscordHeadingHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(gast.KindHeading, r.renderHeading)
+}
+
+func (r *DiscordHeadingHTMLRenderer) renderHeading(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<p>")
+	} else {
+		_, _ = w.WriteString("</p>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+type discord struct {
+}
+
+// Discord is an extension that provides Discord flavored markdown
+// functionalities: spoiler texts like '||text||', strikethrough texts and
+// autolinks. Headings are rendered as paragraphs.
+var Discord = &discord{}
+
+func (e *discord) Extend(m goldmark.Markdown) {
+	Linkify.Extend(m)
+	Strikethrough.Extend(m)
+	Spoiler.Extend(m)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewDiscordHeadingHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,247 @@
+package extension
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// DocumentMetaKey is a key of the document metadata that holds
+// a DocumentMetadata.
+const DocumentMetaKey = "DocumentMeta"
+
+// A DocumentMetadata struct holds statistics of the document.
+type DocumentMetadata struct {
+	// WordCount is a number of words in the document.
+	// See WordCount for details.
+	WordCount int
+
+	// ReadingTime is an estimated reading time in minutes.
+	ReadingTime int
+
+	// Excerpt is a plain text excerpt of the document.
+	Excerpt string
+}
+
+// GetDocumentMeta returns a DocumentMetadata of the document that owns
+// the given node. GetDocumentMeta returns false if the DocumentMeta
+// extension is not enabled.
+func GetDocumentMeta(node gast.Node) (DocumentMetadata, bool) {
+	doc := node.OwnerDocument()
+	if doc == nil {
+		return DocumentMetadata{}, false
+	}
+	meta, ok := doc.Meta()[DocumentMetaKey].(DocumentMetadata)
+	return meta, ok
+}
+
+// A DocumentMetaConfig struct is a data structure that holds configuration of the
+// DocumentMeta extension.
+type DocumentMetaConfig struct {
+	// WPM is a number of words that can be read in a minute.
+	WPM int
+
+	// ExcerptWords is a maximum number of words in an excerpt.
+	// If ExcerptWords is 0, an excerpt is the first paragraph.
+	ExcerptWords int
+}
+
+const optDocumentMetaWPM parser.OptionName = "DocumentMetaWPM"
+
+const optExcerptWords parser.OptionName = "ExcerptWords"
+
+// SetOption implements SetOptioner.
+func (c *DocumentMetaConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optDocumentMetaWPM:
+		c.WPM = value.(int)
+	case optExcerptWords:
+		c.ExcerptWords = value.(int)
+	}
+}
+
+// A DocumentMetaOption interface sets options for the DocumentMeta extension.
+type DocumentMetaOption interface {
+	parser.Option
+	SetDocumentMetaOption(*DocumentMetaConfig)
+}
+
+type withDocumentMetaWPM struct {
+	value int
+}
+
+func (o *withDocumentMetaWPM) SetParserOption(c *parser.Config) {
+	c.Options[optDocumentMetaWPM] = o.value
+}
+
+func (o *withDocumentMetaWPM) SetDocumentMetaOption(c *DocumentMetaConfig) {
+	c.WPM = o.value
+}
+
+// WithDocumentMetaWPM is a functional option that specify a number of
+// words that can be read in a minute. This defaults to 200.
+func WithDocumentMetaWPM(value int) DocumentMetaOption {
+	return &withDocumentMetaWPM{value}
+}
+
+type withExcerptWords struct {
+	value int
+}
+
+func (o *withExcerptWords) SetParserOption(c *parser.Config) {
+	c.Options[optExcerptWords] = o.value
+}
+
+func (o *withExcerptWords) SetDocumentMetaOption(c *DocumentMetaConfig) {
+	c.ExcerptWords = o.value
+}
+
+// WithExcerptWords is a functional option that specify a maximum number
+// of words in an excerpt. An excerpt is the first paragraph of
+// the document by default.
+func WithExcerptWords(value int) DocumentMetaOption {
+	return &withExcerptWords{value}
+}
+
+type documentMetaASTTransformer struct {
+	DocumentMetaConfig
+}
+
+// NewDocumentMetaASTTransformer returns a new parser.ASTTransformer that
+// stores a DocumentMetadata as the document metadata.
+func NewDocumentMetaASTTransformer(opts ...DocumentMetaOption) parser.ASTTransformer {
+	t := &documentMetaASTTransformer{
+		DocumentMetaConfig: DocumentMetaConfig{
+			WPM: DefaultReadingTimeWPM,
+		},
+	}
+	for _, o := range opts {
+		o.SetDocumentMetaOption(&t.DocumentMetaConfig)
+	}
+	return t
+}
+
+func (a *documentMetaASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	words := WordCount(node, source)
+	wpm := a.WPM
+	if wpm <= 0 {
+		wpm = DefaultReadingTimeWPM
+	}
+	var excerpt string
+	if a.ExcerptWords > 0 {
+		excerpt = plainTextExcerpt(node, source, a.ExcerptWords)
+	} else if p := firstParagraph(node); p != nil {
+		excerpt = plainTextExcerpt(p, source, -1)
+	}
+	node.AddMeta(DocumentMetaKey, DocumentMetadata{
+		WordCount:   words,
+		ReadingTime: (words + wpm - 1) / wpm,
+		Excerpt:     excerpt,
+	})
+}
+
+func firstParagraph(root gast.Node) gast.Node {
+	var ret gast.Node
+	_ = gast.Walk(root, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if entering && n.Kind() == gast.KindParagraph {
+			ret = n
+			return gast.WalkStop, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	return ret
+}
+
+// plainTextExcerpt returns texts under the given node up to limit words.
+// Words are counted in the same way as WordCount. Line breaks and
+// boundaries of blocks are converted into a space.
+// limit < 0 means no limits.
+func plainTextExcerpt(root gast.Node, source []byte, limit int) string {
+	var buf bytes.Buffer
+	count := 0
+	inWord := false
+	space := false
+	add := func(r rune) bool {
+		if util.IsSpaceRune(r) {
+			inWord = false
+			space = true
+			return true
+		}
+		wide := util.IsEastAsianWideRune(r)
+		if wide || !inWord {
+			if limit >= 0 && count == limit {
+				return false
+			}
+			count++
+		}
+		inWord = !wide
+		if space && buf.Len() != 0 {
+			_ = buf.WriteByte(' ')
+		}
+		space = false
+		_, _ = buf.WriteRune(r)
+		return true
+	}
+	_ = gast.Walk(root, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if n.Type() != gast.TypeInline {
+			inWord = false
+			space = true
+			return gast.WalkContinue, nil
+		}
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		t, ok := n.(*gast.Text)
+		if !ok {
+			return gast.WalkContinue, nil
+		}
+		value := t.Segment.Value(source)
+		if !t.IsRaw() {
+			value = util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(value)))
+		}
+		for i := 0; i < len(value); {
+			r, size := utf8.DecodeRune(value[i:])
+			i += size
+			if !add(r) {
+				return gast.WalkStop, nil
+			}
+		}
+		if t.SoftLineBreak() || t.HardLineBreak() {
+			inWord = false
+			space = true
+		}
+		return gast.WalkContinue, nil
+	})
+	return buf.String()
+}
+
+type documentMeta struct {
+	options []DocumentMetaOption
+}
+
+// DocumentMeta is an extension that computes statistics of the document
+// like a word count, an estimated reading time and an excerpt.
+// Statistics are stored as the document metadata and can be read by
+// GetDocumentMeta.
+var DocumentMeta = &documentMeta{}
+
+// NewDocumentMeta returns a new extension with given options.
+func NewDocumentMeta(opts ...DocumentMetaOption) goldmark.Extender {
+	return &documentMeta{
+		options: opts,
+	}
+}
+
+func (e *documentMeta) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(
+			util.Prioritized(NewDocumentMetaASTTransformer(e.options...), 999),
+		),
+	)
+}
@@ -0,0 +1,53 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestDocumentMeta(t *testing.T) {
+	cases := []struct {
+		source  string
+		options []DocumentMetaOption
+		meta    DocumentMetadata
+	}{
+		{
+			"# Title\n\nHello *wor*ld\nand `code` &amp; \\*more\\*.\n\nsecond paragraph",
+			nil,
+			DocumentMetadata{WordCount: 9, ReadingTime: 1, Excerpt: "Hello world and code & *more*."},
+		},
+		{
+			"# Title\n\none two\n\n- three four",
+			[]DocumentMetaOption{WithExcerptWords(3), WithDocumentMetaWPM(2)},
+			DocumentMetadata{WordCount: 5, ReadingTime: 3, Excerpt: "Title one two"},
+		},
+		{
+			"日本語です。Go言語",
+			[]DocumentMetaOption{WithExcerptWords(4)},
+			DocumentMetadata{WordCount: 9, ReadingTime: 1, Excerpt: "日本語で"},
+		},
+		{
+			"",
+			nil,
+			DocumentMetadata{},
+		},
+	}
+	for _, c := range cases {
+		markdown := goldmark.New(goldmark.WithExtensions(NewDocumentMeta(c.options...)))
+		doc := markdown.Parser().Parse(text.NewReader([]byte(c.source)))
+		meta, ok := GetDocumentMeta(doc)
+		if !ok {
+			t.Fatalf("%q: metadata should be found", c.source)
+		}
+		if meta != c.meta {
+			t.Errorf("%q: expected %#v, but got %#v", c.source, c.meta, meta)
+		}
+	}
+
+	doc := goldmark.New().Parser().Parse(text.NewReader([]byte("text")))
+	if _, ok := GetDocumentMeta(doc); ok {
+		t.Errorf("metadata should not be found without the extension")
+	}
+}
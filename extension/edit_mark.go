@@ -0,0 +1,235 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type editMarkSyntax struct {
+	opener  []byte
+	closer  []byte
+	newNode func() gast.Node
+}
+
+// editMarkSyntaxes are tried in order, so CriticMarkup style doubled
+// markers precede single ones.
+var editMarkSyntaxes = []editMarkSyntax{
+	{[]byte("{++"), []byte("++}"), func() gast.Node { return ast.NewInsert() }},
+	{[]byte("{--"), []byte("--}"), func() gast.Node { return ast.NewDelete() }},
+	{[]byte("{=="), []byte("==}"), func() gast.Node { return ast.NewHighlight() }},
+	{[]byte("{>>"), []byte("<<}"), func() gast.Node { return ast.NewEditComment() }},
+	{[]byte("{+"), []byte("+}"), func() gast.Node { return ast.NewInsert() }},
+	{[]byte("{-"), []byte("-}"), func() gast.Node { return ast.NewDelete() }},
+}
+
+type editMarkParser struct {
+}
+
+var defaultEditMarkParser = &editMarkParser{}
+
+// NewEditMarkParser returns a new InlineParser that parses edit marks
+// like '{+added+}', '{-removed-}', '{==highlight==}' and '{>>comment<<}'.
+func NewEditMarkParser() parser.InlineParser {
+	return defaultEditMarkParser
+}
+
+func (s *editMarkParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+func (s *editMarkParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, segment := block.PeekLine()
+	for _, syntax := range editMarkSyntaxes {
+		if !bytes.HasPrefix(line, syntax.opener) {
+			continue
+		}
+		start := len(syntax.opener)
+		stop := bytes.Index(line[start:], syntax.closer)
+		if stop < 0 {
+			continue
+		}
+		stop += start
+		if util.IsBlank(line[start:stop]) {
+			continue
+		}
+		node := syntax.newNode()
+		node.AppendChild(node, gast.NewTextSegment(
+			text.NewSegment(segment.Start+start, segment.Start+stop)))
+		block.Advance(stop + len(syntax.closer))
+		return node
+	}
+	return nil
+}
+
+// EditsMode is a mode that filters edit marks in the output.
+type EditsMode int
+
+const (
+	// EditsShowAll renders all edit marks as markups like '<ins>'.
+	EditsShowAll EditsMode = iota
+
+	// EditsShowAccepted renders a text that all edits are accepted.
+	// Inserted texts are rendered without markups, deleted texts and
+	// comments are removed.
+	EditsShowAccepted
+
+	// EditsShowRejected renders a text that all edits are rejected.
+	// Deleted texts are rendered without markups, inserted texts and
+	// comments are removed.
+	EditsShowRejected
+)
+
+// A EditMarkConfig struct has configurations for the HTML based renderers.
+type EditMarkConfig struct {
+	html.Config
+
+	// Mode is a mode that filters edit marks.
+	Mode EditsMode
+}
+
+// EditMarkOption interface is a functional option interface for the extension.
+type EditMarkOption interface {
+	renderer.Option
+	// SetEditMarkOption sets given option to the extension.
+	SetEditMarkOption(*EditMarkConfig)
+}
+
+// NewEditMarkConfig returns a new Config with defaults.
+func NewEditMarkConfig() EditMarkConfig {
+	return EditMarkConfig{
+		Config: html.NewConfig(),
+		Mode:   EditsShowAll,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *EditMarkConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optEditsMode:
+		c.Mode = value.(EditsMode)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+const optEditsMode renderer.OptionName = "EditsMode"
+
+type withEditsMode struct {
+	value EditsMode
+}
+
+func (o *withEditsMode) SetConfig(c *renderer.Config) {
+	c.Options[optEditsMode] = o.value
+}
+
+func (o *withEditsMode) SetEditMarkOption(c *EditMarkConfig) {
+	c.Mode = o.value
+}
+
+// WithEditsMode is a functional option that specify which edit marks
+// appear in the output. This defaults to EditsShowAll.
+func WithEditsMode(mode EditsMode) EditMarkOption {
+	return &withEditsMode{mode}
+}
+
+// EditMarkHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders edit mark nodes.
+type EditMarkHTMLRenderer struct {
+	EditMarkConfig
+}
+
+// NewEditMarkHTMLRenderer returns a new EditMarkHTMLRenderer.
+func NewEditMarkHTMLRenderer(opts ...EditMarkOption) renderer.NodeRenderer {
+	r := &EditMarkHTMLRenderer{
+		EditMarkConfig: NewEditMarkConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetEditMarkOption(&r.EditMarkConfig)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *EditMarkHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindInsert, r.renderInsert)
+	reg.Register(ast.KindDelete, r.renderDelete)
+	reg.Register(ast.KindHighlight, r.renderHighlight)
+	reg.Register(ast.KindEditComment, r.renderEditComment)
+}
+
+func (r *EditMarkHTMLRenderer) renderTag(w util.BufWriter, n gast.Node, entering bool,
+	open, close string, show, markup bool) gast.WalkStatus {
+	if !show {
+		return gast.WalkSkipChildren
+	}
+	if !markup {
+		return gast.WalkContinue
+	}
+	if entering {
+		_, _ = w.WriteString(open)
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, html.GlobalAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString(close)
+	}
+	return gast.WalkContinue
+}
+
+func (r *EditMarkHTMLRenderer) renderInsert(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	return r.renderTag(w, n, entering, "<ins", "</ins>",
+		r.Mode != EditsShowRejected, r.Mode == EditsShowAll), nil
+}
+
+func (r *EditMarkHTMLRenderer) renderDelete(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	return r.renderTag(w, n, entering, "<del", "</del>",
+		r.Mode != EditsShowAccepted, r.Mode == EditsShowAll), nil
+}
+
+func (r *EditMarkHTMLRenderer) renderHighlight(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	return r.renderTag(w, n, entering, "<mark", "</mark>",
+		true, r.Mode == EditsShowAll), nil
+}
+
+func (r *EditMarkHTMLRenderer) renderEditComment(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	return r.renderTag(w, n, entering, `<span class="comment"`, "</span>",
+		r.Mode == EditsShowAll, true), nil
+}
+
+type editMark struct {
+	options []EditMarkOption
+}
+
+// EditMark is an extension that allow you to use CriticMarkup style edit
+// marks like '{+added+}', '{-removed-}', '{==highlight==}' and
+// '{>>comment<<}'. Doubled markers like '{++added++}' are also allowed.
+var EditMark = &editMark{}
+
+// NewEditMark returns a new extension with given options.
+func NewEditMark(opts ...EditMarkOption) goldmark.Extender {
+	return &editMark{
+		options: opts,
+	}
+}
+
+func (e *editMark) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewEditMarkParser(), 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewEditMarkHTMLRenderer(e.options...), 500),
+	))
+}
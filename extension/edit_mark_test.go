@@ -0,0 +1,40 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestEditMark(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			EditMark,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/edit_mark.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestEditsMode(t *testing.T) {
+	source := "a {+b+}{-c-} {==d==}{>>e<<}"
+	cases := []struct {
+		mode     EditsMode
+		expected string
+	}{
+		{EditsShowAccepted, "<p>a b d</p>"},
+		{EditsShowRejected, "<p>a c d</p>"},
+	}
+	for i, c := range cases {
+		markdown := goldmark.New(
+			goldmark.WithExtensions(
+				NewEditMark(WithEditsMode(c.mode)),
+			),
+		)
+		testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+			No:       i + 1,
+			Markdown: source,
+			Expected: c.expected,
+		}, t)
+	}
+}
@@ -611,11 +611,9 @@ func (r *FootnoteHTMLRenderer) renderFootnoteList(
 			html.RenderAttributes(w, node, html.GlobalAttributeFilter)
 		}
 		_ = w.WriteByte('>')
-		if r.Config.XHTML {
-			_, _ = w.WriteString("\n<hr />\n")
-		} else {
-			_, _ = w.WriteString("\n<hr>\n")
-		}
+		_, _ = w.WriteString("\n<hr")
+		_, _ = w.WriteString(r.Config.VoidElementEnd())
+		_ = w.WriteByte('\n')
 		_, _ = w.WriteString("<ol>\n")
 	} else {
 		_, _ = w.WriteString("</ol>\n")
@@ -1,6 +1,7 @@
 package extension
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/yuin/goldmark"
@@ -24,6 +25,24 @@ func TestFootnote(t *testing.T) {
 	testutil.DoTestCaseFile(markdown, "_test/footnote.txt", t, testutil.ParseCliCaseArg()...)
 }
 
+const footnoteOptionsSource = `That's some text with a footnote.[^1]
+
+Same footnote.[^1]
+
+Another one.[^2]
+
+[^1]: And that's the footnote.
+[^2]: Another footnote.
+`
+
+func doFootnoteOptionsTest(t *testing.T, markdown goldmark.Markdown) {
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte(footnoteOptionsSource), &b); err != nil {
+		t.Fatal(err)
+	}
+	testutil.GoldenTest(t, goldenDir, "footnote-options", b.String())
+}
+
 type footnoteID struct {
 }
 
@@ -48,37 +67,7 @@ func TestFootnoteOptions(t *testing.T) {
 		),
 	)
 
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          1,
-			Description: "Footnote with options",
-			Markdown: `That's some text with a footnote.[^1]
-
-Same footnote.[^1]
-
-Another one.[^2]
-
-[^1]: And that's the footnote.
-[^2]: Another footnote.
-`,
-			Expected: `<p>That's some text with a footnote.<sup id="article12-fnref:1"><a href="#article12-fn:1" class="link-class" title="link-title-2-1" role="doc-noteref">1</a></sup></p>
-<p>Same footnote.<sup id="article12-fnref1:1"><a href="#article12-fn:1" class="link-class" title="link-title-2-1" role="doc-noteref">1</a></sup></p>
-<p>Another one.<sup id="article12-fnref:2"><a href="#article12-fn:2" class="link-class" title="link-title-1-2" role="doc-noteref">2</a></sup></p>
-<div class="footnotes" role="doc-endnotes">
-<hr>
-<ol>
-<li id="article12-fn:1">
-<p>And that's the footnote.&#160;<a href="#article12-fnref:1" class="backlink-class" title="backlink-title" role="doc-backlink">^</a>&#160;<a href="#article12-fnref1:1" class="backlink-class" title="backlink-title" role="doc-backlink">^</a></p>
-</li>
-<li id="article12-fn:2">
-<p>Another footnote.&#160;<a href="#article12-fnref:2" class="backlink-class" title="backlink-title" role="doc-backlink">^</a></p>
-</li>
-</ol>
-</div>`,
-		},
-		t,
-	)
+	doFootnoteOptionsTest(t, markdown)
 
 	markdown = goldmark.New(
 		goldmark.WithParserOptions(
@@ -107,35 +96,6 @@ Another one.[^2]
 		),
 	)
 
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          2,
-			Description: "Footnote with an id prefix function",
-			Markdown: `That's some text with a footnote.[^1]
-
-Same footnote.[^1]
-
-Another one.[^2]
-
-[^1]: And that's the footnote.
-[^2]: Another footnote.
-`,
-			Expected: `<p>That's some text with a footnote.<sup id="article12-fnref:1"><a href="#article12-fn:1" class="link-class" title="link-title-2-1" role="doc-noteref">1</a></sup></p>
-<p>Same footnote.<sup id="article12-fnref1:1"><a href="#article12-fn:1" class="link-class" title="link-title-2-1" role="doc-noteref">1</a></sup></p>
-<p>Another one.<sup id="article12-fnref:2"><a href="#article12-fn:2" class="link-class" title="link-title-1-2" role="doc-noteref">2</a></sup></p>
-<div class="footnotes" role="doc-endnotes">
-<hr>
-<ol>
-<li id="article12-fn:1">
-<p>And that's the footnote.&#160;<a href="#article12-fnref:1" class="backlink-class" title="backlink-title" role="doc-backlink">^</a>&#160;<a href="#article12-fnref1:1" class="backlink-class" title="backlink-title" role="doc-backlink">^</a></p>
-</li>
-<li id="article12-fn:2">
-<p>Another footnote.&#160;<a href="#article12-fnref:2" class="backlink-class" title="backlink-title" role="doc-backlink">^</a></p>
-</li>
-</ol>
-</div>`,
-		},
-		t,
-	)
+	// an id prefix function should be the same as WithFootnoteIDPrefix
+	doFootnoteOptionsTest(t, markdown)
 }
@@ -0,0 +1,288 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// DefaultGraphvizPath is a default path of the Graphviz dot command.
+const DefaultGraphvizPath = "dot"
+
+// DefaultGraphvizTimeout is a default time limit of the Graphviz dot command.
+const DefaultGraphvizTimeout = 10 * time.Second
+
+// A GraphvizRenderer is a function that renders a DOT source as SVG.
+type GraphvizRenderer func(dot string) ([]byte, error)
+
+// NewDotCommandRenderer returns a new GraphvizRenderer that runs the
+// Graphviz dot command of the given path like 'dot -Tsvg'. The command is
+// killed if it does not finish within the given timeout. Zero or a
+// negative timeout means no limit.
+func NewDotCommandRenderer(path string, timeout time.Duration) GraphvizRenderer {
+	return func(dot string) ([]byte, error) {
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cmd := exec.CommandContext(ctx, path, "-Tsvg")
+		cmd.Stdin = bytes.NewBufferString(dot)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if stderr.Len() != 0 {
+				return nil, &graphvizError{err: err, message: stderr.String()}
+			}
+			return nil, err
+		}
+		return stdout.Bytes(), nil
+	}
+}
+
+type graphvizError struct {
+	err     error
+	message string
+}
+
+func (e *graphvizError) Error() string {
+	return e.err.Error() + ": " + e.message
+}
+
+func (e *graphvizError) Unwrap() error {
+	return e.err
+}
+
+type graphvizASTTransformer struct {
+}
+
+var defaultGraphvizASTTransformer = &graphvizASTTransformer{}
+
+// NewGraphvizASTTransformer returns a new parser.ASTTransformer that
+// converts fenced code blocks those language is 'dot' into Graphviz nodes.
+func NewGraphvizASTTransformer() parser.ASTTransformer {
+	return defaultGraphvizASTTransformer
+}
+
+func (a *graphvizASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	for _, block := range fencedCodeBlocks(node, reader.Source(), "dot") {
+		graphviz := ast.NewGraphviz()
+		graphviz.SetLines(block.Lines())
+		graphviz.SetBlankPreviousLines(block.HasBlankPreviousLines())
+		block.Parent().ReplaceChild(block.Parent(), block, graphviz)
+	}
+}
+
+// A GraphvizConfig struct has configurations for the HTML based renderers.
+type GraphvizConfig struct {
+	html.Config
+
+	// Renderer renders DOT sources as SVG. If Renderer is nil,
+	// the dot command of Path is used.
+	Renderer GraphvizRenderer
+
+	// Path is a path of the Graphviz dot command.
+	Path string
+
+	// Timeout is a time limit of the Graphviz dot command.
+	Timeout time.Duration
+}
+
+// GraphvizOption interface is a functional option interface for the extension.
+type GraphvizOption interface {
+	renderer.Option
+	// SetGraphvizOption sets given option to the extension.
+	SetGraphvizOption(*GraphvizConfig)
+}
+
+// NewGraphvizConfig returns a new Config with defaults.
+func NewGraphvizConfig() GraphvizConfig {
+	return GraphvizConfig{
+		Config:  html.NewConfig(),
+		Path:    DefaultGraphvizPath,
+		Timeout: DefaultGraphvizTimeout,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *GraphvizConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optGraphvizRenderer:
+		c.Renderer = value.(GraphvizRenderer)
+	case optGraphvizPath:
+		c.Path = value.(string)
+	case optGraphvizTimeout:
+		c.Timeout = value.(time.Duration)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+const optGraphvizRenderer renderer.OptionName = "GraphvizRenderer"
+
+const optGraphvizPath renderer.OptionName = "GraphvizPath"
+
+const optGraphvizTimeout renderer.OptionName = "GraphvizTimeout"
+
+type withGraphvizRenderer struct {
+	value GraphvizRenderer
+}
+
+func (o *withGraphvizRenderer) SetConfig(c *renderer.Config) {
+	c.Options[optGraphvizRenderer] = o.value
+}
+
+func (o *withGraphvizRenderer) SetGraphvizOption(c *GraphvizConfig) {
+	c.Renderer = o.value
+}
+
+// WithGraphvizRenderer is a functional option that specify a function
+// that renders DOT sources as SVG like a call of a rendering service.
+func WithGraphvizRenderer(value GraphvizRenderer) GraphvizOption {
+	return &withGraphvizRenderer{value}
+}
+
+type withGraphvizPath struct {
+	value string
+}
+
+func (o *withGraphvizPath) SetConfig(c *renderer.Config) {
+	c.Options[optGraphvizPath] = o.value
+}
+
+func (o *withGraphvizPath) SetGraphvizOption(c *GraphvizConfig) {
+	c.Path = o.value
+}
+
+// WithGraphvizPath is a functional option that specify a path of the
+// Graphviz dot command. This defaults to DefaultGraphvizPath.
+func WithGraphvizPath(value string) GraphvizOption {
+	return &withGraphvizPath{value}
+}
+
+type withGraphvizTimeout struct {
+	value time.Duration
+}
+
+func (o *withGraphvizTimeout) SetConfig(c *renderer.Config) {
+	c.Options[optGraphvizTimeout] = o.value
+}
+
+func (o *withGraphvizTimeout) SetGraphvizOption(c *GraphvizConfig) {
+	c.Timeout = o.value
+}
+
+// WithGraphvizTimeout is a functional option that specify a time limit of
+// the Graphviz dot command. Diagrams those are not rendered in time are
+// rendered as code blocks. This defaults to DefaultGraphvizTimeout.
+func WithGraphvizTimeout(value time.Duration) GraphvizOption {
+	return &withGraphvizTimeout{value}
+}
+
+// GraphvizHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Graphviz nodes as inline SVG.
+type GraphvizHTMLRenderer struct {
+	GraphvizConfig
+	dotRenderer GraphvizRenderer
+}
+
+// NewGraphvizHTMLRenderer returns a new GraphvizHTMLRenderer.
+func NewGraphvizHTMLRenderer(opts ...GraphvizOption) renderer.NodeRenderer {
+	r := &GraphvizHTMLRenderer{
+		GraphvizConfig: NewGraphvizConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetGraphvizOption(&r.GraphvizConfig)
+	}
+	r.dotRenderer = NewDotCommandRenderer(r.Path, r.Timeout)
+	return r
+}
+
+// SetOption implements renderer.SetOptioner.
+func (r *GraphvizHTMLRenderer) SetOption(name renderer.OptionName, value interface{}) {
+	r.GraphvizConfig.SetOption(name, value)
+	switch name {
+	case optGraphvizPath, optGraphvizTimeout:
+		r.dotRenderer = NewDotCommandRenderer(r.Path, r.Timeout)
+	}
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *GraphvizHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindGraphviz, r.renderGraphviz)
+}
+
+var svgStartTag = []byte("<svg")
+
+func (r *GraphvizHTMLRenderer) renderGraphviz(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+	dot := linesValue(node, source)
+	// SVG can have scripts and links like 'javascript:' generated from
+	// DOT attributes, so it is written only in the unsafe mode.
+	if r.Unsafe {
+		render := r.Renderer
+		if render == nil {
+			render = r.dotRenderer
+		}
+		svg, err := render(string(dot))
+		// XML declarations and DOCTYPEs are not allowed in HTML documents
+		if i := bytes.Index(svg, svgStartTag); err == nil && i > -1 {
+			_, _ = w.WriteString(`<div class="graphviz">`)
+			_, _ = w.Write(bytes.TrimSpace(svg[i:]))
+			_, _ = w.WriteString("</div>\n")
+			return gast.WalkContinue, nil
+		}
+	}
+	// diagrams those can not be rendered are rendered as code blocks
+	_, _ = w.WriteString(`<pre><code class="language-dot">`)
+	r.Writer.RawWrite(w, dot)
+	_, _ = w.WriteString("</code></pre>\n")
+	return gast.WalkContinue, nil
+}
+
+type graphviz struct {
+	options []GraphvizOption
+}
+
+// Graphviz is an extension that renders fenced code blocks those language
+// is 'dot' as inline SVG diagrams by the Graphviz dot command. Use
+// WithGraphvizRenderer to render diagrams in other ways. Diagrams those
+// can not be rendered are rendered as code blocks.
+//
+// SVG is written as is, so diagrams are rendered only if html.WithUnsafe
+// is given. Otherwise diagrams are rendered as code blocks.
+var Graphviz = &graphviz{}
+
+// NewGraphviz returns a new extension with given options.
+func NewGraphviz(opts ...GraphvizOption) goldmark.Extender {
+	return &graphviz{
+		options: opts,
+	}
+}
+
+func (e *graphviz) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewGraphvizASTTransformer(), 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewGraphvizHTMLRenderer(e.options...), 500),
+	))
+}
@@ -0,0 +1,109 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestGraphviz(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewGraphviz(WithGraphvizRenderer(func(dot string) ([]byte, error) {
+				if dot == "error\n" {
+					return nil, errors.New("syntax error")
+				}
+				return []byte("<?xml version=\"1.0\"?>\n<svg><text>" + dot + "</text></svg>\n"), nil
+			})),
+		),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "render diagrams",
+		Markdown: "```dot\n" +
+			"a\n" +
+			"```\n" +
+			"```dot\n" +
+			"error\n" +
+			"```\n" +
+			"```go\n" +
+			"a <- b\n" +
+			"```",
+		Expected: `<div class="graphviz"><svg><text>a
+</text></svg></div>
+<pre><code class="language-dot">error
+</code></pre>
+<pre><code class="language-go">a &lt;- b
+</code></pre>`,
+	}, t)
+}
+
+func TestGraphvizCommandNotFound(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewGraphviz(WithGraphvizPath("goldmark-dot-not-found")),
+		),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:       1,
+		Markdown: "```dot\ndigraph { a -> b }\n```",
+		Expected: `<pre><code class="language-dot">digraph { a -&gt; b }
+</code></pre>`,
+	}, t)
+}
+
+func TestGraphvizSafe(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewGraphviz(WithGraphvizRenderer(func(dot string) ([]byte, error) {
+				// like dot renders 'a [URL="javascript:alert(1)"]'
+				return []byte(`<svg><a xlink:href="javascript:alert(1)"><text>a</text></a></svg>`), nil
+			})),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "diagrams are not rendered without WithUnsafe",
+		Markdown:    "```dot\ndigraph { a [URL=\"javascript:alert(1)\"] }\n```",
+		Expected: `<pre><code class="language-dot">digraph { a [URL=&quot;javascript:alert(1)&quot;] }
+</code></pre>`,
+	}, t)
+}
+
+func TestGraphvizTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping a shell script test on windows")
+	}
+	path := filepath.Join(t.TempDir(), "dot")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexec sleep 10\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewDotCommandRenderer(path, 100*time.Millisecond)("digraph { a -> b }")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, but got %v", err)
+	}
+
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewGraphviz(WithGraphvizPath(path)),
+		),
+		goldmark.WithRendererOptions(html.WithUnsafe(), WithGraphvizTimeout(100*time.Millisecond)),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "diagrams those are not rendered in time",
+		Markdown:    "```dot\ndigraph { a -> b }\n```",
+		Expected: `<pre><code class="language-dot">digraph { a -&gt; b }
+</code></pre>`,
+	}, t)
+}
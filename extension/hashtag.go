@@ -0,0 +1,208 @@
+package extension
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A HashtagConfig struct is a data structure that holds configuration of the
+// Hashtag extension.
+type HashtagConfig struct {
+	// BaseURL is a prefix of hashtag links. Hashtags are not rendered
+	// as links if BaseURL is empty.
+	BaseURL []byte
+
+	// Filter reports whether the given tag should be treated as a hashtag.
+	Filter func(tag string) bool
+}
+
+const (
+	optHashtagBaseURL parser.OptionName = "HashtagBaseURL"
+	optHashtagFilter  parser.OptionName = "HashtagFilter"
+)
+
+// SetOption implements SetOptioner.
+func (c *HashtagConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optHashtagBaseURL:
+		c.BaseURL = value.([]byte)
+	case optHashtagFilter:
+		c.Filter = value.(func(string) bool)
+	}
+}
+
+// A HashtagOption interface sets options for the Hashtag extension.
+type HashtagOption interface {
+	parser.Option
+	SetHashtagOption(*HashtagConfig)
+}
+
+type withHashtagBaseURL struct {
+	value []byte
+}
+
+func (o *withHashtagBaseURL) SetParserOption(c *parser.Config) {
+	c.Options[optHashtagBaseURL] = o.value
+}
+
+func (o *withHashtagBaseURL) SetHashtagOption(c *HashtagConfig) {
+	c.BaseURL = o.value
+}
+
+// WithHashtagBaseURL is a functional option that specify a prefix of
+// hashtag links like 'https://example.com/tags/' .
+func WithHashtagBaseURL(base string) HashtagOption {
+	return &withHashtagBaseURL{[]byte(base)}
+}
+
+type withHashtagFilter struct {
+	value func(string) bool
+}
+
+func (o *withHashtagFilter) SetParserOption(c *parser.Config) {
+	c.Options[optHashtagFilter] = o.value
+}
+
+func (o *withHashtagFilter) SetHashtagOption(c *HashtagConfig) {
+	c.Filter = o.value
+}
+
+// WithHashtagFilter is a functional option that specify a function
+// that reports whether the given tag should be treated as a hashtag.
+// Tags are passed without the leading '#'.
+func WithHashtagFilter(f func(tag string) bool) HashtagOption {
+	return &withHashtagFilter{f}
+}
+
+type hashtagParser struct {
+	HashtagConfig
+}
+
+// NewHashtagParser returns a new parser.InlineParser that can parse
+// hashtags like '#topic'.
+func NewHashtagParser(opts ...HashtagOption) parser.InlineParser {
+	p := &hashtagParser{}
+	for _, o := range opts {
+		o.SetHashtagOption(&p.HashtagConfig)
+	}
+	return p
+}
+
+func (s *hashtagParser) Trigger() []byte {
+	return []byte{'#'}
+}
+
+func isHashtagRune(r rune) bool {
+	return r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (s *hashtagParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	if p := block.PrecendingCharacter(); isHashtagRune(p) || p == '&' || p == '/' || p == '#' {
+		return nil
+	}
+	line, segment := block.PeekLine()
+	pos := 1
+	hasLetter := false
+	for pos < len(line) {
+		r, size := utf8.DecodeRune(line[pos:])
+		if !isHashtagRune(r) {
+			break
+		}
+		if !unicode.IsDigit(r) {
+			hasLetter = true
+		}
+		pos += size
+	}
+	// '#123' is not a hashtag but an issue number
+	if !hasLetter {
+		return nil
+	}
+	tag := line[1:pos]
+	if s.Filter != nil && !s.Filter(string(tag)) {
+		return nil
+	}
+	node := ast.NewHashtag(tag)
+	if len(s.BaseURL) != 0 {
+		node.Destination = append(append([]byte{}, s.BaseURL...), util.URLEscape(tag, false)...)
+	}
+	node.AppendChild(node, gast.NewTextSegment(text.NewSegment(segment.Start, segment.Start+pos)))
+	block.Advance(pos)
+	return node
+}
+
+// HashtagHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Hashtag nodes.
+type HashtagHTMLRenderer struct {
+	html.Config
+}
+
+// NewHashtagHTMLRenderer returns a new HashtagHTMLRenderer.
+func NewHashtagHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &HashtagHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *HashtagHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindHashtag, r.renderHashtag)
+}
+
+func (r *HashtagHTMLRenderer) renderHashtag(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*ast.Hashtag)
+	if entering {
+		if len(n.Destination) != 0 {
+			_, _ = w.WriteString(`<a href="`)
+			if r.Unsafe || !html.IsDangerousURL(n.Destination) {
+				_, _ = w.Write(util.EscapeHTML(n.Destination))
+			}
+			_, _ = w.WriteString(`" class="hashtag">`)
+		} else {
+			_, _ = w.WriteString(`<span class="hashtag">`)
+		}
+	} else {
+		if len(n.Destination) != 0 {
+			_, _ = w.WriteString("</a>")
+		} else {
+			_, _ = w.WriteString("</span>")
+		}
+	}
+	return gast.WalkContinue, nil
+}
+
+type hashtag struct {
+	options []HashtagOption
+}
+
+// Hashtag is an extension that allow you to use hashtags like '#topic' .
+var Hashtag = &hashtag{}
+
+// NewHashtag returns a new extension with given options.
+func NewHashtag(opts ...HashtagOption) goldmark.Extender {
+	return &hashtag{
+		options: opts,
+	}
+}
+
+func (e *hashtag) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewHashtagParser(e.options...), 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewHashtagHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,36 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestHashtag(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewHashtag(
+				WithHashtagBaseURL("https://example.com/tags/"),
+				WithHashtagFilter(func(tag string) bool {
+					return tag != "blocked"
+				}),
+			),
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/hashtag.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestHashtagWithoutBaseURL(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Hashtag,
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "without base URL",
+		Markdown:    "#go is fun",
+		Expected:    `<p><span class="hashtag">#go</span> is fun</p>`,
+	}, t)
+}
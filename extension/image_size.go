@@ -0,0 +1,237 @@
+package extension
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	_ "image/png"  // register PNG decoder
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// An ImageSizer is a function that returns dimensions of the image
+// specified by the given URL.
+type ImageSizer func(url string) (width, height int, err error)
+
+// ErrNotLocalImage is returned by LocalFileSizer if the given URL
+// does not point to a local file.
+var ErrNotLocalImage = errors.New("goldmark: image is not a local file")
+
+// imageHeadSize is a maximum number of bytes read to decode dimensions
+// of an image.
+const imageHeadSize = 256 * 1024
+
+// DecodeImageSize returns dimensions of the image read from r.
+// PNG, JPEG, GIF and WebP images are supported.
+func DecodeImageSize(r io.Reader) (width, height int, err error) {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(30); len(head) >= 16 && string(head[0:4]) == "RIFF" && string(head[8:12]) == "WEBP" {
+		return decodeWebPSize(head)
+	}
+	config, _, err := image.DecodeConfig(br)
+	if err != nil {
+		return 0, 0, err
+	}
+	return config.Width, config.Height, nil
+}
+
+func decodeWebPSize(head []byte) (int, int, error) {
+	switch string(head[12:16]) {
+	case "VP8 ":
+		if len(head) < 30 || head[23] != 0x9d || head[24] != 0x01 || head[25] != 0x2a {
+			return 0, 0, image.ErrFormat
+		}
+		return int(binary.LittleEndian.Uint16(head[26:]) & 0x3fff),
+			int(binary.LittleEndian.Uint16(head[28:]) & 0x3fff), nil
+	case "VP8L":
+		if len(head) < 25 || head[20] != 0x2f {
+			return 0, 0, image.ErrFormat
+		}
+		bits := binary.LittleEndian.Uint32(head[21:])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, nil
+	case "VP8X":
+		if len(head) < 30 {
+			return 0, 0, image.ErrFormat
+		}
+		w := uint32(head[24]) | uint32(head[25])<<8 | uint32(head[26])<<16
+		h := uint32(head[27]) | uint32(head[28])<<8 | uint32(head[29])<<16
+		return int(w) + 1, int(h) + 1, nil
+	}
+	return 0, 0, image.ErrFormat
+}
+
+// NewLocalFileSizer returns a new ImageSizer that reads dimensions of
+// images from files under the given directory. URLs are resolved as
+// paths relative to dir and can not point outside of dir.
+// URLs those have a scheme or a host are rejected with ErrNotLocalImage.
+func NewLocalFileSizer(dir string) ImageSizer {
+	return func(v string) (int, int, error) {
+		u, err := url.Parse(v)
+		if err != nil {
+			return 0, 0, err
+		}
+		if len(u.Scheme) != 0 || len(u.Host) != 0 || len(u.Path) == 0 {
+			return 0, 0, ErrNotLocalImage
+		}
+		name := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+u.Path)))
+		f, err := os.Open(name)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer f.Close()
+		return DecodeImageSize(io.LimitReader(f, imageHeadSize))
+	}
+}
+
+// LocalFileSizer is an ImageSizer that reads dimensions of images from
+// files under the current directory.
+var LocalFileSizer = NewLocalFileSizer(".")
+
+// NewHTTPHeadSizer returns a new ImageSizer that reads dimensions of
+// images from the head of images fetched by the given client.
+// Only first bytes of images are requested by a Range header.
+// Relative URLs are rejected.
+//
+// Images are fetched while documents are rendered, so the client should
+// have a short timeout. HTTP sizers must not be used with untrusted
+// documents: authors of documents can make the server send requests to
+// any host including hosts in the internal network, and slow hosts delay
+// rendering.
+func NewHTTPHeadSizer(client *http.Client) ImageSizer {
+	return func(v string) (int, int, error) {
+		if !strings.HasPrefix(v, "http://") && !strings.HasPrefix(v, "https://") {
+			return 0, 0, fmt.Errorf("goldmark: %q is not an HTTP URL", v)
+		}
+		req, err := http.NewRequest(http.MethodGet, v, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		req.Header.Set("Range", "bytes=0-"+strconv.Itoa(imageHeadSize-1))
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+			return 0, 0, fmt.Errorf("goldmark: %s: unexpected status %s", v, resp.Status)
+		}
+		return DecodeImageSize(io.LimitReader(resp.Body, imageHeadSize))
+	}
+}
+
+const (
+	// httpHeadSizerTimeout is a timeout of each request of HTTPHeadSizer.
+	httpHeadSizerTimeout = 5 * time.Second
+
+	// httpHeadSizerMaxRedirects is a maximum number of redirects those
+	// HTTPHeadSizer follows.
+	httpHeadSizerMaxRedirects = 3
+)
+
+// HTTPHeadSizer is an ImageSizer that reads dimensions of remote images.
+// Each image is given up after 5 seconds and 3 redirects.
+// HTTPHeadSizer must not be used with untrusted documents.
+// See NewHTTPHeadSizer.
+var HTTPHeadSizer = NewHTTPHeadSizer(&http.Client{
+	Timeout: httpHeadSizerTimeout,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) > httpHeadSizerMaxRedirects {
+			return fmt.Errorf("goldmark: stopped after %d redirects", httpHeadSizerMaxRedirects)
+		}
+		return nil
+	},
+})
+
+// An ImageSizeConfig struct is a data structure that holds configuration of the
+// ImageSize extension.
+type ImageSizeConfig struct {
+	// Sizer is an ImageSizer that returns dimensions of images.
+	Sizer ImageSizer
+}
+
+// An ImageSizeOption interface sets options for the ImageSize extension.
+type ImageSizeOption interface {
+	SetImageSizeOption(*ImageSizeConfig)
+}
+
+type withImageSizer struct {
+	value ImageSizer
+}
+
+func (o *withImageSizer) SetImageSizeOption(c *ImageSizeConfig) {
+	c.Sizer = o.value
+}
+
+// WithImageSizer is a functional option that specify an ImageSizer.
+// This defaults to LocalFileSizer.
+func WithImageSizer(value ImageSizer) ImageSizeOption {
+	return &withImageSizer{value}
+}
+
+// NewImageSizeMiddleware returns a new renderer.Middleware that adds
+// width and height attributes to images before they are rendered.
+// Images those already have a width or a height attribute and images
+// the ImageSizer returns an error for are rendered as they are.
+func NewImageSizeMiddleware(opts ...ImageSizeOption) renderer.Middleware {
+	config := ImageSizeConfig{
+		Sizer: LocalFileSizer,
+	}
+	for _, o := range opts {
+		o.SetImageSizeOption(&config)
+	}
+	sizer := config.Sizer
+	return func(w util.BufWriter, source []byte, n gast.Node, entering bool,
+		next renderer.NodeRendererFunc) (gast.WalkStatus, error) {
+		if !entering || n.Kind() != gast.KindImage {
+			return next(w, source, n, entering)
+		}
+		if _, ok := n.AttributeString("width"); ok {
+			return next(w, source, n, entering)
+		}
+		if _, ok := n.AttributeString("height"); ok {
+			return next(w, source, n, entering)
+		}
+		width, height, err := sizer(string(n.(*gast.Image).Destination))
+		if err == nil && width > 0 && height > 0 {
+			n.SetAttributeString("width", []byte(strconv.Itoa(width)))
+			n.SetAttributeString("height", []byte(strconv.Itoa(height)))
+		}
+		return next(w, source, n, entering)
+	}
+}
+
+type imageSize struct {
+	options []ImageSizeOption
+}
+
+// ImageSize is an extension that adds width and height attributes to
+// images to prevent layout shifts. Dimensions are read from local files
+// by default.
+var ImageSize = &imageSize{}
+
+// NewImageSize returns a new extension with given options.
+func NewImageSize(opts ...ImageSizeOption) goldmark.Extender {
+	return &imageSize{
+		options: opts,
+	}
+}
+
+func (e *imageSize) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(renderer.WithMiddleware(NewImageSizeMiddleware(e.options...)))
+}
@@ -0,0 +1,116 @@
+package extension
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuin/goldmark"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	var b bytes.Buffer
+	if err := png.Encode(&b, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestImageSize(t *testing.T) {
+	markdown := goldmark.New(goldmark.WithExtensions(NewImageSize(
+		WithImageSizer(func(url string) (int, int, error) {
+			if url == "a.png" {
+				return 30, 20, nil
+			}
+			return 0, 0, errors.New("not found")
+		}),
+	)))
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte("![a](a.png) ![b](b.png)\n"), &b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "<p><img src=\"a.png\" alt=\"a\" width=\"30\" height=\"20\"> <img src=\"b.png\" alt=\"b\"></p>\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, but got %q", expected, b.String())
+	}
+}
+
+func TestLocalFileSizer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.png"), encodePNG(t, 3, 2), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// a lossless WebP header of a 5x4 image
+	webp := []byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00\x2f\x04\xc0\x00\x00\x00\x00\x00\x00")
+	if err := os.WriteFile(filepath.Join(dir, "b.webp"), webp, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sizer := NewLocalFileSizer(dir)
+	cases := []struct {
+		url    string
+		width  int
+		height int
+		err    bool
+	}{
+		{"a.png", 3, 2, false},
+		{"/a.png?v=1", 3, 2, false},
+		{"../a.png", 3, 2, false},
+		{"b.webp", 5, 4, false},
+		{"https://example.com/a.png", 0, 0, true},
+		{"c.png", 0, 0, true},
+	}
+	for _, c := range cases {
+		width, height, err := sizer(c.url)
+		if (err != nil) != c.err || width != c.width || height != c.height {
+			t.Errorf("%q: expected (%d, %d, %v), but got (%d, %d, %v)",
+				c.url, c.width, c.height, c.err, width, height, err)
+		}
+	}
+}
+
+func TestHTTPHeadSizer(t *testing.T) {
+	data := encodePNG(t, 7, 6)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "" {
+			t.Errorf("range header should be set")
+		}
+		if r.URL.Path != "/a.png" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+	sizer := NewHTTPHeadSizer(server.Client())
+	width, height, err := sizer(server.URL + "/a.png")
+	if err != nil || width != 7 || height != 6 {
+		t.Errorf("expected (7, 6, nil), but got (%d, %d, %v)", width, height, err)
+	}
+	if _, _, err := sizer(server.URL + "/b.png"); err == nil {
+		t.Errorf("an error should be returned for missing images")
+	}
+	if _, _, err := sizer("a.png"); err == nil {
+		t.Errorf("an error should be returned for relative URLs")
+	}
+}
+
+func TestHTTPHeadSizerRedirects(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Redirect(w, r, "/loop.png", http.StatusFound)
+	}))
+	defer server.Close()
+	if _, _, err := HTTPHeadSizer(server.URL + "/loop.png"); err == nil {
+		t.Errorf("an error should be returned for redirect loops")
+	}
+	if requests != httpHeadSizerMaxRedirects+1 {
+		t.Errorf("expected %d requests, but got %d", httpHeadSizerMaxRedirects+1, requests)
+	}
+}
@@ -0,0 +1,95 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type inlineCodeLangASTTransformer struct {
+}
+
+var defaultInlineCodeLangASTTransformer = &inlineCodeLangASTTransformer{}
+
+// NewInlineCodeLangASTTransformer returns a new parser.ASTTransformer that
+// sets attributes like '{.go}' those follow code spans to the code spans.
+func NewInlineCodeLangASTTransformer() parser.ASTTransformer {
+	return defaultInlineCodeLangASTTransformer
+}
+
+// inlineCodeAttributes parses attributes like '{.go}' at the start of the
+// given Text node and removes them from the node.
+func inlineCodeAttributes(t *gast.Text, source []byte) (parser.Attributes, bool) {
+	if t.Segment.Padding != 0 || t.Segment.Start == 0 || source[t.Segment.Start-1] != '`' {
+		return nil, false
+	}
+	value := t.Segment.Value(source)
+	if len(value) == 0 || value[0] != '{' {
+		return nil, false
+	}
+	reader := text.NewReader(value)
+	attrs, ok := parser.ParseAttributes(reader)
+	if !ok {
+		return nil, false
+	}
+	_, pos := reader.Position()
+	t.Segment = t.Segment.WithStart(t.Segment.Start + pos.Start)
+	return attrs, true
+}
+
+func (a *inlineCodeLangASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var spans []*gast.CodeSpan
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		if span, ok := n.(*gast.CodeSpan); ok {
+			spans = append(spans, span)
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, span := range spans {
+		t, ok := span.NextSibling().(*gast.Text)
+		if !ok {
+			continue
+		}
+		mergeNextTexts(t, source)
+		attrs, ok := inlineCodeAttributes(t, source)
+		if !ok {
+			continue
+		}
+		if t.Segment.IsEmpty() && !t.SoftLineBreak() && !t.HardLineBreak() {
+			t.Parent().RemoveChild(t.Parent(), t)
+		}
+		for _, attr := range attrs {
+			if bytes.Equal(attr.Name, []byte("class")) {
+				// the first class is a language like Pandoc
+				class := attr.Value.([]byte)
+				attr.Value = append([]byte(html.DefaultCodeLanguagePrefix), class...)
+			}
+			span.SetAttribute(attr.Name, attr.Value)
+		}
+	}
+}
+
+type inlineCodeLang struct {
+}
+
+// InlineCodeLang is an extension that allow you to specify languages of
+// code spans like Pandoc '`code`{.go}' . Code spans with languages are
+// rendered like '<code class="language-go">'. Other attributes like
+// '{.go #id}' are also set to the code spans.
+var InlineCodeLang = &inlineCodeLang{}
+
+func (e *inlineCodeLang) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewInlineCodeLangASTTransformer(), 100),
+	))
+}
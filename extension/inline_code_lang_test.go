@@ -0,0 +1,17 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestInlineCodeLang(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			InlineCodeLang,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/inline_code_lang.txt", t, testutil.ParseCliCaseArg()...)
+}
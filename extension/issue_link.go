@@ -0,0 +1,311 @@
+package extension
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// An IssueLinkConfig struct is a data structure that holds configuration of the
+// IssueLink extension.
+type IssueLinkConfig struct {
+	// IssueBaseURL is a prefix of issue links like
+	// 'https://github.com/owner/repo/issues/'.
+	IssueBaseURL string
+
+	// MergeRequestBaseURL is a prefix of merge request links like
+	// 'https://gitlab.com/owner/repo/-/merge_requests/'.
+	MergeRequestBaseURL string
+}
+
+const (
+	optIssueBaseURL        parser.OptionName = "IssueBaseURL"
+	optMergeRequestBaseURL parser.OptionName = "MergeRequestBaseURL"
+)
+
+// SetOption implements SetOptioner.
+func (c *IssueLinkConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optIssueBaseURL:
+		c.IssueBaseURL = value.(string)
+	case optMergeRequestBaseURL:
+		c.MergeRequestBaseURL = value.(string)
+	}
+}
+
+// An IssueLinkOption interface sets options for the IssueLink extension.
+type IssueLinkOption interface {
+	parser.Option
+	SetIssueLinkOption(*IssueLinkConfig)
+}
+
+type withIssueBaseURL struct {
+	value string
+}
+
+func (o *withIssueBaseURL) SetParserOption(c *parser.Config) {
+	c.Options[optIssueBaseURL] = o.value
+}
+
+func (o *withIssueBaseURL) SetIssueLinkOption(c *IssueLinkConfig) {
+	c.IssueBaseURL = o.value
+}
+
+// WithIssueBaseURL is a functional option that specify a prefix of
+// issue links like 'https://github.com/owner/repo/issues/' .
+// Cross repository references like 'other/repo#123' are linked by
+// replacing the repository in this URL.
+func WithIssueBaseURL(url string) IssueLinkOption {
+	return &withIssueBaseURL{url}
+}
+
+type withMergeRequestBaseURL struct {
+	value string
+}
+
+func (o *withMergeRequestBaseURL) SetParserOption(c *parser.Config) {
+	c.Options[optMergeRequestBaseURL] = o.value
+}
+
+func (o *withMergeRequestBaseURL) SetIssueLinkOption(c *IssueLinkConfig) {
+	c.MergeRequestBaseURL = o.value
+}
+
+// WithMergeRequestBaseURL is a functional option that specify a prefix of
+// GitLab merge request links like
+// 'https://gitlab.com/owner/repo/-/merge_requests/' .
+// References like '!123' are not linked if this option is not set.
+func WithMergeRequestBaseURL(url string) IssueLinkOption {
+	return &withMergeRequestBaseURL{url}
+}
+
+type issueLinkASTTransformer struct {
+	IssueLinkConfig
+}
+
+// NewIssueLinkASTTransformer returns a new parser.ASTTransformer that
+// converts issue references like '#123' in text nodes into IssueLink nodes.
+func NewIssueLinkASTTransformer(opts ...IssueLinkOption) parser.ASTTransformer {
+	t := &issueLinkASTTransformer{}
+	for _, o := range opts {
+		o.SetIssueLinkOption(&t.IssueLinkConfig)
+	}
+	return t
+}
+
+func (a *issueLinkASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	if len(a.IssueBaseURL) == 0 && len(a.MergeRequestBaseURL) == 0 {
+		return
+	}
+	var texts []*gast.Text
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case gast.KindCodeSpan, gast.KindLink, gast.KindAutoLink, gast.KindImage, gast.KindRawHTML:
+			return gast.WalkSkipChildren, nil
+		}
+		if t, ok := n.(*gast.Text); ok && !t.IsRaw() && t.Segment.Padding == 0 {
+			texts = append(texts, t)
+		}
+		return gast.WalkContinue, nil
+	})
+	source := reader.Source()
+	merged := texts[:0]
+	for _, t := range texts {
+		// text may be split by inline parser triggers like '!'
+		if prev, ok := t.PreviousSibling().(*gast.Text); ok && !prev.IsRaw() &&
+			!prev.SoftLineBreak() && !prev.HardLineBreak() && prev.Segment.Stop == t.Segment.Start {
+			prev.Segment = prev.Segment.WithStop(t.Segment.Stop)
+			prev.SetSoftLineBreak(t.SoftLineBreak())
+			prev.SetHardLineBreak(t.HardLineBreak())
+			t.Parent().RemoveChild(t.Parent(), t)
+			continue
+		}
+		merged = append(merged, t)
+	}
+	for _, t := range merged {
+		a.transformText(t, source)
+	}
+}
+
+func (a *issueLinkASTTransformer) transformText(t *gast.Text, source []byte) {
+	value := t.Segment.Value(source)
+	offset := t.Segment.Start
+	parent := t.Parent()
+	last := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] != '#' && value[i] != '!' {
+			continue
+		}
+		start, stop, repository := matchIssueReference(value, i)
+		if stop < 0 {
+			continue
+		}
+		mergeRequest := value[i] == '!'
+		destination := a.destination(repository, value[i+1:stop], mergeRequest)
+		if len(destination) == 0 {
+			continue
+		}
+		if last < start {
+			parent.InsertBefore(parent, t, gast.NewTextSegment(text.NewSegment(offset+last, offset+start)))
+		}
+		link := ast.NewIssueLink(repository, value[i+1:stop], mergeRequest)
+		link.Destination = []byte(destination)
+		link.AppendChild(link, gast.NewTextSegment(text.NewSegment(offset+start, offset+stop)))
+		parent.InsertBefore(parent, t, link)
+		last = stop
+		i = stop - 1
+	}
+	if last == 0 {
+		return
+	}
+	t.Segment = t.Segment.WithStart(offset + last)
+	if t.Segment.IsEmpty() && !t.SoftLineBreak() && !t.HardLineBreak() {
+		parent.RemoveChild(parent, t)
+	}
+}
+
+func isRepositoryChar(c byte) bool {
+	return util.IsAlphaNumeric(c) || c == '-' || c == '_' || c == '.'
+}
+
+// matchIssueReference matches an issue reference around the marker at
+// the given position. matchIssueReference returns start and stop
+// positions of the reference and a repository name if exists. stop is
+// -1 if there is no issue reference.
+func matchIssueReference(value []byte, marker int) (int, int, []byte) {
+	stop := marker + 1
+	for ; stop < len(value) && util.IsNumeric(value[stop]); stop++ {
+	}
+	if stop == marker+1 || (stop < len(value) && (util.IsAlphaNumeric(value[stop]) || value[stop] == '_')) {
+		return 0, -1, nil
+	}
+	if marker == 0 {
+		return marker, stop, nil
+	}
+	prev := value[marker-1]
+	if prev == '\\' || prev == '&' {
+		return 0, -1, nil
+	}
+	if !isRepositoryChar(prev) {
+		return marker, stop, nil
+	}
+
+	// owner/repo#123
+	i := marker - 1
+	for ; i >= 0 && isRepositoryChar(value[i]); i-- {
+	}
+	if i < 1 || value[i] != '/' || i == marker-1 {
+		return 0, -1, nil
+	}
+	slash := i
+	for i--; i >= 0 && (util.IsAlphaNumeric(value[i]) || value[i] == '-'); i-- {
+	}
+	if i == slash-1 || (i >= 0 && (isRepositoryChar(value[i]) || value[i] == '/')) {
+		return 0, -1, nil
+	}
+	return i + 1, stop, value[i+1 : marker]
+}
+
+func (a *issueLinkASTTransformer) destination(repository, number []byte, mergeRequest bool) string {
+	base := a.IssueBaseURL
+	if mergeRequest {
+		base = a.MergeRequestBaseURL
+	}
+	if len(base) == 0 {
+		return ""
+	}
+	if repository != nil {
+		base = replaceIssueRepository(base, string(repository))
+		if len(base) == 0 {
+			return ""
+		}
+	}
+	return base + string(number)
+}
+
+// replaceIssueRepository replaces 'owner/repo' in the given URL like
+// 'https://github.com/owner/repo/issues/' with the given repository.
+func replaceIssueRepository(base, repository string) string {
+	i := strings.Index(base, "://")
+	if i < 0 {
+		return ""
+	}
+	hostEnd := strings.IndexByte(base[i+3:], '/')
+	if hostEnd < 0 {
+		return ""
+	}
+	hostEnd += i + 3
+	parts := strings.SplitN(base[hostEnd+1:], "/", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return base[:hostEnd+1] + repository + "/" + parts[2]
+}
+
+// IssueLinkHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders IssueLink nodes.
+type IssueLinkHTMLRenderer struct {
+	html.Config
+}
+
+// NewIssueLinkHTMLRenderer returns a new IssueLinkHTMLRenderer.
+func NewIssueLinkHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &IssueLinkHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *IssueLinkHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindIssueLink, r.renderIssueLink)
+}
+
+func (r *IssueLinkHTMLRenderer) renderIssueLink(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*ast.IssueLink)
+	if entering {
+		_, _ = w.WriteString(`<a href="`)
+		if r.Unsafe || !html.IsDangerousURL(n.Destination) {
+			_, _ = w.Write(util.EscapeHTML(util.URLEscape(n.Destination, false)))
+		}
+		_, _ = w.WriteString(`" class="issue-link">`)
+	} else {
+		_, _ = w.WriteString("</a>")
+	}
+	return gast.WalkContinue, nil
+}
+
+type issueLink struct {
+	options []IssueLinkOption
+}
+
+// NewIssueLink returns a new extension that converts issue references
+// like '#123', 'owner/repo#123' and '!123' into links.
+func NewIssueLink(opts ...IssueLinkOption) goldmark.Extender {
+	return &issueLink{
+		options: opts,
+	}
+}
+
+func (e *issueLink) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewIssueLinkASTTransformer(e.options...), 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewIssueLinkHTMLRenderer(), 500),
+	))
+}
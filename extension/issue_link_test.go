@@ -0,0 +1,20 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestIssueLink(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewIssueLink(
+				WithIssueBaseURL("https://gitlab.com/owner/repo/-/issues/"),
+				WithMergeRequestBaseURL("https://gitlab.com/owner/repo/-/merge_requests/"),
+			),
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/issue_link.txt", t, testutil.ParseCliCaseArg()...)
+}
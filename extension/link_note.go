@@ -0,0 +1,166 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A LinkNoteRenderer is a function that returns HTML written after a link
+// that has the note.
+type LinkNoteRenderer func(url, note string) []byte
+
+// LinkNoteSidenoteRenderer is a LinkNoteRenderer that renders notes like
+// '<span class="link-note">note</span>' so that notes can be styled as
+// sidenotes.
+func LinkNoteSidenoteRenderer(url, note string) []byte {
+	b := []byte(`<span class="link-note">`)
+	b = append(b, util.EscapeHTML([]byte(note))...)
+	return append(b, "</span>"...)
+}
+
+type linkNoteASTTransformer struct {
+}
+
+var defaultLinkNoteASTTransformer = &linkNoteASTTransformer{}
+
+// NewLinkNoteASTTransformer returns a new parser.ASTTransformer that
+// moves titles of links to 'data-note' attributes and LinkNote nodes.
+func NewLinkNoteASTTransformer() parser.ASTTransformer {
+	return defaultLinkNoteASTTransformer
+}
+
+func (a *linkNoteASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	var links []*gast.Link
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if link, ok := n.(*gast.Link); ok && entering && len(link.Title) != 0 {
+			links = append(links, link)
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, link := range links {
+		note := util.UnescapePunctuations(link.Title)
+		note = util.ResolveNumericReferences(note)
+		note = util.ResolveEntityNames(note)
+		link.SetAttributeString("data-note", note)
+		link.Title = nil
+		link.Parent().InsertAfter(link.Parent(), link, ast.NewLinkNote(link.Destination, note))
+	}
+}
+
+// A LinkNoteConfig struct has configurations for the HTML based renderers.
+type LinkNoteConfig struct {
+	html.Config
+
+	// Renderer returns HTML written after links those have notes.
+	// If Renderer is nil, nothing is written.
+	Renderer LinkNoteRenderer
+}
+
+// LinkNoteOption interface is a functional option interface for the extension.
+type LinkNoteOption interface {
+	renderer.Option
+	// SetLinkNoteOption sets given option to the extension.
+	SetLinkNoteOption(*LinkNoteConfig)
+}
+
+// NewLinkNoteConfig returns a new Config with defaults.
+func NewLinkNoteConfig() LinkNoteConfig {
+	return LinkNoteConfig{
+		Config: html.NewConfig(),
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *LinkNoteConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optLinkNoteRenderer:
+		c.Renderer = value.(LinkNoteRenderer)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+const optLinkNoteRenderer renderer.OptionName = "LinkNoteRenderer"
+
+type withLinkNoteRenderer struct {
+	value LinkNoteRenderer
+}
+
+func (o *withLinkNoteRenderer) SetConfig(c *renderer.Config) {
+	c.Options[optLinkNoteRenderer] = o.value
+}
+
+func (o *withLinkNoteRenderer) SetLinkNoteOption(c *LinkNoteConfig) {
+	c.Renderer = o.value
+}
+
+// WithLinkNoteRenderer is a functional option that specify a function
+// that returns HTML written after links those have notes like
+// LinkNoteSidenoteRenderer. Returned HTML is written as is.
+func WithLinkNoteRenderer(value LinkNoteRenderer) LinkNoteOption {
+	return &withLinkNoteRenderer{value}
+}
+
+// LinkNoteHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders LinkNote nodes.
+type LinkNoteHTMLRenderer struct {
+	LinkNoteConfig
+}
+
+// NewLinkNoteHTMLRenderer returns a new LinkNoteHTMLRenderer.
+func NewLinkNoteHTMLRenderer(opts ...LinkNoteOption) renderer.NodeRenderer {
+	r := &LinkNoteHTMLRenderer{
+		LinkNoteConfig: NewLinkNoteConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetLinkNoteOption(&r.LinkNoteConfig)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *LinkNoteHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindLinkNote, r.renderLinkNote)
+}
+
+func (r *LinkNoteHTMLRenderer) renderLinkNote(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering || r.Renderer == nil {
+		return gast.WalkContinue, nil
+	}
+	n := node.(*ast.LinkNote)
+	_, _ = w.Write(r.Renderer(string(n.Destination), string(n.Note)))
+	return gast.WalkContinue, nil
+}
+
+type linkNote struct {
+	options []LinkNoteOption
+}
+
+// LinkNote is an extension that uses titles of links like
+// '[link](url "note")' as notes. Notes are rendered as 'data-note'
+// attributes instead of 'title' attributes so that they can be styled as
+// tooltips. Use WithLinkNoteRenderer to render notes after links.
+var LinkNote = &linkNote{}
+
+// NewLinkNote returns a new extension with given options.
+func NewLinkNote(opts ...LinkNoteOption) goldmark.Extender {
+	return &linkNote{
+		options: opts,
+	}
+}
+
+func (e *linkNote) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewLinkNoteASTTransformer(), 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewLinkNoteHTMLRenderer(e.options...), 500),
+	))
+}
@@ -0,0 +1,34 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestLinkNote(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			LinkNote,
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:       1,
+		Markdown: `[a](/a "a \"note\" &amp; more") [b](/b) ![c](/c.png "image")`,
+		Expected: `<p><a href="/a" data-note="a &quot;note&quot; &amp; more">a</a> <a href="/b">b</a> <img src="/c.png" alt="c" title="image"></p>`,
+	}, t)
+}
+
+func TestLinkNoteRenderer(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewLinkNote(WithLinkNoteRenderer(LinkNoteSidenoteRenderer)),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:       1,
+		Markdown: `[a](/a "<note>")`,
+		Expected: `<p><a href="/a" data-note="&lt;note&gt;">a</a><span class="link-note">&lt;note&gt;</span></p>`,
+	}, t)
+}
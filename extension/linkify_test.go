@@ -1,7 +1,9 @@
 package extension
 
 import (
+	"bytes"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/yuin/goldmark"
@@ -98,3 +100,56 @@ func TestLinkifyWithEmailRegexp(t *testing.T) {
 		t,
 	)
 }
+
+// linkifyAdversarialCases returns sources those may take quadratic time
+// with their expected results. n must be larger than 128.
+func linkifyAdversarialCases(n int) [][2]string {
+	email := strings.Repeat("a@a.", n)
+	www := "www." + strings.Repeat("a.", n) + "_"
+	// domains of www links have 256 characters at most
+	link := "www." + strings.Repeat("a.", 128) + "a"
+	return [][2]string{
+		{"a@" + strings.Repeat("a-", n), "<p>a@" + strings.Repeat("a-", n) + "</p>\n"},
+		{strings.Repeat("a.", n) + "@", "<p>" + strings.Repeat("a.", n) + "@</p>\n"},
+		{email, `<p><a href="mailto:a@a.a">a@a.a</a>` + email[5:] + "</p>\n"},
+		{www, `<p><a href="http://` + link + `">` + link + "</a>" + www[len(link):] + "</p>\n"},
+	}
+}
+
+func TestLinkifyAdversarialEmail(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping a test with large inputs in short mode")
+	}
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Linkify,
+		),
+	)
+	for _, c := range linkifyAdversarialCases(50000) {
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte(c[0]), &b); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != c[1] {
+			t.Errorf("unexpected result for %q...: %q...", c[0][:10], b.String()[:40])
+		}
+	}
+}
+
+func BenchmarkLinkifyAdversarialEmail(b *testing.B) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Linkify,
+		),
+	)
+	for _, c := range linkifyAdversarialCases(50000) {
+		source := []byte(c[0])
+		b.Run(c[0][:10], func(b *testing.B) {
+			var out bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				out.Reset()
+				_ = markdown.Convert(source, &out)
+			}
+		})
+	}
+}
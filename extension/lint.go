@@ -0,0 +1,365 @@
+package extension
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// LintMetaKey is a key of the document metadata that holds
+// LintWarnings.
+const LintMetaKey = "Lint"
+
+// A LintRule is a name of a check of the Linter extension.
+type LintRule string
+
+const (
+	// LintBareURL reports URLs those are not written as autolinks
+	// like '<https://example.com>'.
+	LintBareURL LintRule = "bare-url"
+
+	// LintUnusedReference reports link reference definitions those
+	// are never used.
+	LintUnusedReference LintRule = "unused-reference"
+
+	// LintBlankLines reports consecutive blank lines outside of
+	// code blocks and HTML blocks.
+	LintBlankLines LintRule = "blank-lines"
+
+	// LintHeadingLevel reports headings those skip levels like
+	// an h4 after an h2.
+	LintHeadingLevel LintRule = "heading-level"
+
+	// LintImageAlt reports images without alt texts.
+	LintImageAlt LintRule = "image-alt"
+
+	// LintEmptyLink reports links with empty destinations.
+	LintEmptyLink LintRule = "empty-link"
+)
+
+// DefaultLintRules is a list of rules checked by default.
+var DefaultLintRules = []LintRule{
+	LintBareURL,
+	LintUnusedReference,
+	LintBlankLines,
+	LintHeadingLevel,
+	LintImageAlt,
+	LintEmptyLink,
+}
+
+// A LintWarning struct represents a problem found by the Linter extension.
+type LintWarning struct {
+	// Line is a 1-based line number of the problem.
+	Line int
+
+	// Rule is a rule that reported the problem.
+	Rule LintRule
+
+	// Message is a human readable description of the problem.
+	Message string
+}
+
+// String implements fmt.Stringer.
+func (w LintWarning) String() string {
+	return fmt.Sprintf("%d: %s (%s)", w.Line, w.Message, w.Rule)
+}
+
+// GetLintWarnings returns LintWarnings of the document that owns the
+// given node in order of lines.
+func GetLintWarnings(node gast.Node) []LintWarning {
+	doc := node.OwnerDocument()
+	if doc == nil {
+		return nil
+	}
+	warnings, _ := doc.Meta()[LintMetaKey].([]LintWarning)
+	return warnings
+}
+
+// Lint parses the given source and returns problems found in it.
+func Lint(source []byte, opts ...LintOption) []LintWarning {
+	markdown := goldmark.New(goldmark.WithExtensions(NewLinter(opts...)))
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	return GetLintWarnings(doc)
+}
+
+// A LintConfig struct is a data structure that holds configuration of the
+// Linter extension.
+type LintConfig struct {
+	// Rules is a list of rules to be checked.
+	Rules []LintRule
+}
+
+const optLintRules parser.OptionName = "LintRules"
+
+// SetOption implements SetOptioner.
+func (c *LintConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optLintRules:
+		c.Rules = value.([]LintRule)
+	}
+}
+
+// A LintOption interface sets options for the Linter extension.
+type LintOption interface {
+	parser.Option
+	SetLintOption(*LintConfig)
+}
+
+type withLintRules struct {
+	value []LintRule
+}
+
+func (o *withLintRules) SetParserOption(c *parser.Config) {
+	c.Options[optLintRules] = o.value
+}
+
+func (o *withLintRules) SetLintOption(c *LintConfig) {
+	c.Rules = o.value
+}
+
+// WithLintRules is a functional option that specify rules to be checked.
+// This defaults to DefaultLintRules.
+func WithLintRules(rules ...LintRule) LintOption {
+	return &withLintRules{rules}
+}
+
+type lintASTTransformer struct {
+	LintConfig
+}
+
+// NewLintASTTransformer returns a new parser.ASTTransformer that checks
+// the document and stores LintWarnings as the document metadata.
+func NewLintASTTransformer(opts ...LintOption) parser.ASTTransformer {
+	t := &lintASTTransformer{
+		LintConfig: LintConfig{
+			Rules: DefaultLintRules,
+		},
+	}
+	for _, o := range opts {
+		o.SetLintOption(&t.LintConfig)
+	}
+	return t
+}
+
+type linter struct {
+	source   []byte
+	rules    map[LintRule]bool
+	warnings []LintWarning
+}
+
+func (l *linter) warn(rule LintRule, offset int, format string, args ...interface{}) {
+	if !l.rules[rule] {
+		return
+	}
+	l.warnings = append(l.warnings, LintWarning{
+		Line:    bytes.Count(l.source[:offset], []byte{'\n'}) + 1,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// lintOffset returns a position of the given node in the source.
+func lintOffset(n gast.Node) int {
+	for c := n; c != nil; c = c.Parent() {
+		if t, ok := c.(*gast.Text); ok {
+			return t.Segment.Start
+		}
+		if c.Type() == gast.TypeBlock && c.Lines().Len() != 0 {
+			return c.Lines().At(0).Start
+		}
+		if c != n {
+			continue
+		}
+		var offset = -1
+		_ = gast.Walk(c, func(d gast.Node, entering bool) (gast.WalkStatus, error) {
+			if t, ok := d.(*gast.Text); ok && entering {
+				offset = t.Segment.Start
+				return gast.WalkStop, nil
+			}
+			return gast.WalkContinue, nil
+		})
+		if offset >= 0 {
+			return offset
+		}
+	}
+	return 0
+}
+
+func inLink(n gast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		switch p.Kind() {
+		case gast.KindLink, gast.KindImage, gast.KindAutoLink, gast.KindCodeSpan:
+			return true
+		}
+	}
+	return false
+}
+
+var lintURLPrefixes = [][]byte{[]byte("http://"), []byte("https://")}
+
+func (l *linter) checkNodes(doc gast.Node) {
+	level := 0
+	_ = gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch v := n.(type) {
+		case *gast.Heading:
+			if level > 0 && v.Level > level+1 {
+				l.warn(LintHeadingLevel, lintOffset(v), "heading level jumps from h%d to h%d", level, v.Level)
+			}
+			level = v.Level
+		case *gast.Image:
+			if len(bytes.TrimSpace(v.Text(l.source))) == 0 {
+				l.warn(LintImageAlt, lintOffset(v), "image %q has no alt text", v.Destination)
+			}
+			return gast.WalkSkipChildren, nil
+		case *gast.Link:
+			if len(v.Destination) == 0 {
+				l.warn(LintEmptyLink, lintOffset(v), "link %q has an empty destination", v.Text(l.source))
+			}
+		case *gast.Text:
+			if inLink(v) {
+				return gast.WalkContinue, nil
+			}
+			value := v.Segment.Value(l.source)
+			for _, prefix := range lintURLPrefixes {
+				if i := bytes.Index(value, prefix); i >= 0 {
+					l.warn(LintBareURL, v.Segment.Start+i, "bare URL should be written as an autolink like <%s...>", prefix)
+					break
+				}
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+func (l *linter) checkReferences(doc *gast.Document) {
+	refs := doc.LinkReferenceMap()
+	if len(refs) == 0 {
+		return
+	}
+	used := map[string]bool{}
+	_ = gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering || n.Type() != gast.TypeBlock {
+			return gast.WalkContinue, nil
+		}
+		lines := n.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			segment := lines.At(i)
+			line := segment.Value(l.source)
+			for {
+				start := bytes.IndexByte(line, '[')
+				if start < 0 {
+					break
+				}
+				line = line[start+1:]
+				end := bytes.IndexAny(line, "[]")
+				if end < 0 {
+					break
+				}
+				if line[end] == ']' {
+					used[util.ToLinkReference(line[:end])] = true
+				}
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+	for key, ref := range refs {
+		if used[key] {
+			continue
+		}
+		offset := bytes.Index(l.source, append([]byte{'['}, ref.Label...))
+		if offset < 0 {
+			offset = 0
+		}
+		l.warn(LintUnusedReference, offset, "link reference definition %q is never used", ref.Label)
+	}
+}
+
+func (l *linter) checkBlankLines(doc gast.Node) {
+	var verbatim [][2]int
+	_ = gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case gast.KindFencedCodeBlock, gast.KindCodeBlock, gast.KindHTMLBlock:
+			if lines := n.Lines(); lines.Len() != 0 {
+				verbatim = append(verbatim, [2]int{lines.At(0).Start, lines.At(lines.Len() - 1).Stop})
+			}
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	blanks := 0
+	for offset := 0; offset < len(l.source); {
+		end := bytes.IndexByte(l.source[offset:], '\n')
+		if end < 0 {
+			end = len(l.source)
+		} else {
+			end += offset + 1
+		}
+		inVerbatim := false
+		for _, r := range verbatim {
+			if offset >= r[0] && offset < r[1] {
+				inVerbatim = true
+				break
+			}
+		}
+		if !inVerbatim && util.IsBlank(l.source[offset:end]) {
+			blanks++
+			if blanks == 2 {
+				l.warn(LintBlankLines, offset, "consecutive blank lines")
+			}
+		} else {
+			blanks = 0
+		}
+		offset = end
+	}
+}
+
+func (a *lintASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	l := &linter{
+		source: reader.Source(),
+		rules:  map[LintRule]bool{},
+	}
+	for _, rule := range a.Rules {
+		l.rules[rule] = true
+	}
+	l.checkNodes(node)
+	l.checkReferences(node)
+	l.checkBlankLines(node)
+	sort.SliceStable(l.warnings, func(i, j int) bool {
+		return l.warnings[i].Line < l.warnings[j].Line
+	})
+	node.AddMeta(LintMetaKey, l.warnings)
+}
+
+type lint struct {
+	options []LintOption
+}
+
+// Linter is an extension that checks common problems of Markdown
+// documents like headings those skip levels and images without alt texts.
+// Problems can be read by GetLintWarnings.
+var Linter = &lint{}
+
+// NewLinter returns a new extension with given options.
+func NewLinter(opts ...LintOption) goldmark.Extender {
+	return &lint{
+		options: opts,
+	}
+}
+
+func (e *lint) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewLintASTTransformer(e.options...), 999),
+	))
+}
@@ -0,0 +1,53 @@
+package extension
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	source := []byte(`# Title
+
+See https://example.com and <https://example.com>.
+
+
+### Skipped
+
+![](a.png) ![alt](b.png) [empty]() [used][ref] [Other]
+
+` + "```" + `
+code
+
+
+code
+` + "```" + `
+
+## Level 2
+
+[ref]: /a
+[unused]: /b
+[other]: /c
+`)
+	expected := []LintWarning{
+		{3, LintBareURL, "bare URL should be written as an autolink like <https://...>"},
+		{5, LintBlankLines, "consecutive blank lines"},
+		{6, LintHeadingLevel, "heading level jumps from h1 to h3"},
+		{8, LintImageAlt, `image "a.png" has no alt text`},
+		{8, LintEmptyLink, `link "empty" has an empty destination`},
+		{20, LintUnusedReference, `link reference definition "unused" is never used`},
+	}
+	if actual := Lint(source); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, but got %v", expected, actual)
+	}
+
+	expected = []LintWarning{
+		{6, LintHeadingLevel, "heading level jumps from h1 to h3"},
+	}
+	if actual := Lint(source, WithLintRules(LintHeadingLevel)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, but got %v", expected, actual)
+	}
+
+	if actual := Lint([]byte("# Title\n\n## Section\n")); len(actual) != 0 {
+		t.Errorf("expected no warnings, but got %v", actual)
+	}
+}
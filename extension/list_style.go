@@ -0,0 +1,573 @@
+package extension
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	// ListStyleDecimal is a style of lists like '1.' .
+	ListStyleDecimal = "1"
+
+	// ListStyleLowerAlpha is a style of lists like 'a.' .
+	ListStyleLowerAlpha = "a"
+
+	// ListStyleUpperAlpha is a style of lists like 'A.' .
+	ListStyleUpperAlpha = "A"
+
+	// ListStyleLowerRoman is a style of lists like 'i.' .
+	ListStyleLowerRoman = "i"
+
+	// ListStyleUpperRoman is a style of lists like 'I.' .
+	ListStyleUpperRoman = "I"
+
+	// ListStyleLowerGreek is a style of lists like 'α.' .
+	ListStyleLowerGreek = "α"
+)
+
+// DefaultListStyles is a default list of styles those are used in order
+// of nesting depth of ordered lists.
+var DefaultListStyles = []string{ListStyleDecimal, ListStyleLowerAlpha, ListStyleLowerRoman}
+
+var listStyleGreekLetters = []rune("αβγδεζηθικλμνξοπρστυφχψω")
+
+var (
+	listStyleTypeAttr  = []byte("type")
+	listStyleStyleAttr = []byte("style")
+	listStyleGreekCSS  = []byte("list-style-type:lower-greek")
+)
+
+// setListStyle sets the given style to the list.
+func setListStyle(list *gast.List, style string) {
+	switch style {
+	case ListStyleDecimal:
+	case ListStyleLowerGreek:
+		list.SetAttribute(listStyleStyleAttr, listStyleGreekCSS)
+	default:
+		list.SetAttribute(listStyleTypeAttr, []byte(style))
+	}
+}
+
+// listStyleOf returns a style set by setListStyle.
+func listStyleOf(list *gast.List) (string, bool) {
+	if v, ok := list.Attribute(listStyleTypeAttr); ok {
+		if b, ok := v.([]byte); ok {
+			return string(b), true
+		}
+	}
+	if v, ok := list.Attribute(listStyleStyleAttr); ok {
+		if b, ok := v.([]byte); ok && bytes.Equal(b, listStyleGreekCSS) {
+			return ListStyleLowerGreek, true
+		}
+	}
+	return "", false
+}
+
+var romanNumerals = []struct {
+	value   int
+	numeral string
+}{
+	{1000, "m"}, {900, "cm"}, {500, "d"}, {400, "cd"},
+	{100, "c"}, {90, "xc"}, {50, "l"}, {40, "xl"},
+	{10, "x"}, {9, "ix"}, {5, "v"}, {4, "iv"}, {1, "i"},
+}
+
+// romanValue returns a value of the given lower case roman numeral.
+// Numerals those are not in the canonical form like 'iiii' are not
+// allowed.
+func romanValue(s []byte) (int, bool) {
+	n := 0
+	rest := string(s)
+	for _, r := range romanNumerals {
+		for strings.HasPrefix(rest, r.numeral) {
+			n += r.value
+			rest = rest[len(r.numeral):]
+		}
+	}
+	if len(rest) != 0 || n == 0 || n > 3999 {
+		return 0, false
+	}
+	var b strings.Builder
+	for v, i := n, 0; v > 0; {
+		if v >= romanNumerals[i].value {
+			b.WriteString(romanNumerals[i].numeral)
+			v -= romanNumerals[i].value
+		} else {
+			i++
+		}
+	}
+	return n, b.String() == string(s)
+}
+
+// listStyleValue returns a value of the marker in the given style.
+func listStyleValue(marker []byte, style string) (int, bool) {
+	switch style {
+	case ListStyleLowerAlpha, ListStyleUpperAlpha:
+		if len(marker) != 1 {
+			return 0, false
+		}
+		c := marker[0]
+		if style == ListStyleLowerAlpha && c >= 'a' && c <= 'z' {
+			return int(c-'a') + 1, true
+		}
+		if style == ListStyleUpperAlpha && c >= 'A' && c <= 'Z' {
+			return int(c-'A') + 1, true
+		}
+	case ListStyleLowerRoman:
+		if bytes.Equal(marker, bytes.ToLower(marker)) {
+			return romanValue(marker)
+		}
+	case ListStyleUpperRoman:
+		if bytes.Equal(marker, bytes.ToUpper(marker)) {
+			return romanValue(bytes.ToLower(marker))
+		}
+	case ListStyleLowerGreek:
+		r, size := utf8.DecodeRune(marker)
+		if size != len(marker) {
+			return 0, false
+		}
+		for i, g := range listStyleGreekLetters {
+			if r == g {
+				return i + 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// detectListStyle returns a style of the first item of a list.
+// Single letters like 'v.' are alphabets except 'i.' and 'I.' .
+func detectListStyle(marker []byte) (string, int, bool) {
+	candidates := []string{ListStyleLowerAlpha, ListStyleUpperAlpha,
+		ListStyleLowerRoman, ListStyleUpperRoman, ListStyleLowerGreek}
+	if len(marker) == 1 && (marker[0] == 'i' || marker[0] == 'I') || len(marker) > 1 {
+		candidates = []string{ListStyleLowerRoman, ListStyleUpperRoman, ListStyleLowerGreek}
+	}
+	for _, style := range candidates {
+		if v, ok := listStyleValue(marker, style); ok {
+			return style, v, true
+		}
+	}
+	return "", 0, false
+}
+
+// parseStyledListItem parses a list item like 'a. text' .
+// Returned indices are same as ones of list items of the parser package
+// and the marker does not contain a delimiter.
+func parseStyledListItem(line []byte) ([6]int, []byte, bool) {
+	ret := [6]int{}
+	i := 0
+	l := len(line)
+	for ; i < l && line[i] == ' '; i++ {
+	}
+	if i > 3 {
+		return ret, nil, false
+	}
+	ret[1] = i
+	ret[2] = i
+	for i < l && i-ret[2] < 16 {
+		r, size := utf8.DecodeRune(line[i:])
+		if !unicode.IsLetter(r) {
+			break
+		}
+		i += size
+	}
+	if i == ret[2] || i >= l || (line[i] != '.' && line[i] != ')') {
+		return ret, nil, false
+	}
+	marker := line[ret[2]:i]
+	i++
+	ret[3] = i
+	if i < l && line[i] != '\n' {
+		w, _ := util.IndentWidth(line[i:], 0)
+		if w == 0 {
+			return ret, nil, false
+		}
+		// 'A. Lincoln' is not a list item like Pandoc
+		if w < 2 && line[i-1] == '.' && len(marker) == 1 && marker[0] >= 'A' && marker[0] <= 'Z' {
+			return ret, nil, false
+		}
+	}
+	if i >= l {
+		ret[4] = -1
+		ret[5] = -1
+		return ret, marker, true
+	}
+	ret[4] = i
+	ret[5] = len(line)
+	if line[ret[5]-1] == '\n' && line[i] != '\n' {
+		ret[5]--
+	}
+	return ret, marker, true
+}
+
+var skipStyledListParserKey = parser.NewContextKey()
+var emptyStyledListItemWithBlankLines = parser.NewContextKey()
+
+func lastListItemOffset(node gast.Node) int {
+	if last, ok := node.LastChild().(*gast.ListItem); ok {
+		return last.Offset
+	}
+	return 0
+}
+
+func listItemOffset(source []byte, match [6]int) int {
+	if match[4] < 0 || util.IsBlank(source[match[4]:]) {
+		return 1
+	}
+	offset, _ := util.IndentWidth(source[match[4]:], match[4])
+	if offset > 4 {
+		offset = 1
+	}
+	return offset
+}
+
+var listStyleTrigger = func() []byte {
+	var trigger []byte
+	for c := byte('a'); c <= 'z'; c++ {
+		trigger = append(trigger, c, c-'a'+'A')
+	}
+	// first bytes of greek letters in UTF-8
+	return append(trigger, 0xce, 0xcf)
+}()
+
+type styledListParser struct {
+}
+
+var defaultStyledListParser = &styledListParser{}
+
+// NewStyledListParser returns a new parser.BlockParser that can parse
+// ordered lists like 'a.', 'i.', 'A.', 'I.' and 'α.' .
+func NewStyledListParser() parser.BlockParser {
+	return defaultStyledListParser
+}
+
+func (b *styledListParser) Trigger() []byte {
+	return listStyleTrigger
+}
+
+func (b *styledListParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	last := pc.LastOpenedBlock().Node
+	if _, ok := last.(*gast.List); ok || pc.Get(skipStyledListParserKey) != nil {
+		pc.Set(skipStyledListParserKey, nil)
+		return nil, parser.NoChildren
+	}
+	line, _ := reader.PeekLine()
+	match, marker, ok := parseStyledListItem(line)
+	if !ok {
+		return nil, parser.NoChildren
+	}
+	style, start, ok := detectListStyle(marker)
+	if !ok {
+		return nil, parser.NoChildren
+	}
+	if gast.IsParagraph(last) && last.Parent() == parent {
+		if start != 1 || match[4] < 0 || util.IsBlank(line[match[4]:match[5]]) {
+			return nil, parser.NoChildren
+		}
+	}
+	node := gast.NewList(line[match[3]-1])
+	node.Start = start
+	setListStyle(node, style)
+	pc.Set(emptyStyledListItemWithBlankLines, nil)
+	return node, parser.HasChildren
+}
+
+func (b *styledListParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	list := node.(*gast.List)
+	line, _ := reader.PeekLine()
+	if util.IsBlank(line) {
+		if node.LastChild().ChildCount() == 0 {
+			pc.Set(emptyStyledListItemWithBlankLines, true)
+		}
+		return parser.Continue | parser.HasChildren
+	}
+	offset := lastListItemOffset(node)
+	lastIsEmpty := node.LastChild().ChildCount() == 0
+	indent, _ := util.IndentWidth(line, reader.LineOffset())
+	if indent < offset || lastIsEmpty {
+		if indent < 4 {
+			match, marker, ok := parseStyledListItem(line)
+			if ok && match[1]-offset < 4 {
+				style, _ := listStyleOf(list)
+				if _, ok := listStyleValue(marker, style); !ok || line[match[3]-1] != list.Marker {
+					return parser.Close
+				}
+				return parser.Continue | parser.HasChildren
+			}
+		}
+		if !lastIsEmpty {
+			return parser.Close
+		}
+	}
+	if lastIsEmpty && indent < offset {
+		return parser.Close
+	}
+	if pc.Get(emptyStyledListItemWithBlankLines) != nil {
+		return parser.Close
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+func (b *styledListParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	list := node.(*gast.List)
+	for c := node.FirstChild(); c != nil && list.IsTight; c = c.NextSibling() {
+		if c.FirstChild() != nil && c.FirstChild() != c.LastChild() {
+			for c1 := c.FirstChild().NextSibling(); c1 != nil; c1 = c1.NextSibling() {
+				if c1.HasBlankPreviousLines() {
+					list.IsTight = false
+					break
+				}
+			}
+		}
+		if c != node.FirstChild() && c.HasBlankPreviousLines() {
+			list.IsTight = false
+		}
+	}
+	if !list.IsTight {
+		return
+	}
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		for gc := child.FirstChild(); gc != nil; {
+			paragraph, ok := gc.(*gast.Paragraph)
+			gc = gc.NextSibling()
+			if ok {
+				textBlock := gast.NewTextBlock()
+				textBlock.SetLines(paragraph.Lines())
+				child.ReplaceChild(child, paragraph, textBlock)
+			}
+		}
+	}
+}
+
+func (b *styledListParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *styledListParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type styledListItemParser struct {
+}
+
+var defaultStyledListItemParser = &styledListItemParser{}
+
+// NewStyledListItemParser returns a new parser.BlockParser that can parse
+// items of lists parsed by the parser returned by NewStyledListParser.
+func NewStyledListItemParser() parser.BlockParser {
+	return defaultStyledListItemParser
+}
+
+func (b *styledListItemParser) Trigger() []byte {
+	return listStyleTrigger
+}
+
+func (b *styledListItemParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	list, ok := parent.(*gast.List)
+	if !ok {
+		return nil, parser.NoChildren
+	}
+	style, ok := listStyleOf(list)
+	if !ok {
+		return nil, parser.NoChildren
+	}
+	offset := lastListItemOffset(list)
+	line, _ := reader.PeekLine()
+	match, marker, ok := parseStyledListItem(line)
+	if !ok || match[1]-offset > 3 {
+		return nil, parser.NoChildren
+	}
+	if _, ok := listStyleValue(marker, style); !ok {
+		return nil, parser.NoChildren
+	}
+	pc.Set(emptyStyledListItemWithBlankLines, nil)
+	itemOffset := listItemOffset(line, match)
+	node := gast.NewListItem(match[3] + itemOffset)
+	if match[4] < 0 || util.IsBlank(line[match[4]:match[5]]) {
+		return node, parser.NoChildren
+	}
+	pos, padding := util.IndentPosition(line[match[4]:], match[4], itemOffset)
+	reader.AdvanceAndSetPadding(match[3]+pos, padding)
+	return node, parser.HasChildren
+}
+
+func (b *styledListItemParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, _ := reader.PeekLine()
+	if util.IsBlank(line) {
+		reader.Advance(len(line) - 1)
+		return parser.Continue | parser.HasChildren
+	}
+	offset := lastListItemOffset(node.Parent())
+	isEmpty := node.ChildCount() == 0
+	indent, _ := util.IndentWidth(line, reader.LineOffset())
+	if (isEmpty || indent < offset) && indent < 4 {
+		if _, _, ok := parseStyledListItem(line); ok {
+			pc.Set(skipStyledListParserKey, true)
+			return parser.Close
+		}
+		if !isEmpty {
+			return parser.Close
+		}
+	}
+	pos, padding := util.IndentPosition(line, reader.LineOffset(), offset)
+	reader.AdvanceAndSetPadding(pos, padding)
+	return parser.Continue | parser.HasChildren
+}
+
+func (b *styledListItemParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	// nothing to do
+}
+
+func (b *styledListItemParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *styledListItemParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// A ListStyleConfig struct is a data structure that holds configuration of the
+// ListStyle extension.
+type ListStyleConfig struct {
+	// AutoListStyle indicates that styles of ordered lists those markers
+	// are numbers are selected by their nesting depth.
+	AutoListStyle bool
+
+	// Styles is a list of styles used in order of nesting depth.
+	Styles []string
+}
+
+const optAutoListStyle parser.OptionName = "AutoListStyle"
+
+const optListStyles parser.OptionName = "ListStyles"
+
+// SetOption implements SetOptioner.
+func (c *ListStyleConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optAutoListStyle:
+		c.AutoListStyle = value.(bool)
+	case optListStyles:
+		c.Styles = value.([]string)
+	}
+}
+
+// A ListStyleOption interface sets options for the ListStyle extension.
+type ListStyleOption interface {
+	parser.Option
+	SetListStyleOption(*ListStyleConfig)
+}
+
+type withAutoListStyle struct {
+	value bool
+}
+
+func (o *withAutoListStyle) SetParserOption(c *parser.Config) {
+	c.Options[optAutoListStyle] = o.value
+}
+
+func (o *withAutoListStyle) SetListStyleOption(c *ListStyleConfig) {
+	c.AutoListStyle = o.value
+}
+
+// WithAutoListStyle is a functional option that selects styles of ordered
+// lists those markers are numbers by their nesting depth.
+func WithAutoListStyle(value bool) ListStyleOption {
+	return &withAutoListStyle{value}
+}
+
+type withListStyles struct {
+	value []string
+}
+
+func (o *withListStyles) SetParserOption(c *parser.Config) {
+	c.Options[optListStyles] = o.value
+}
+
+func (o *withListStyles) SetListStyleOption(c *ListStyleConfig) {
+	c.Styles = o.value
+}
+
+// WithListStyles is a functional option that specify styles like
+// ListStyleLowerAlpha used by WithAutoListStyle. Styles are cycled
+// through by nesting depth. This defaults to DefaultListStyles.
+func WithListStyles(styles ...string) ListStyleOption {
+	return &withListStyles{styles}
+}
+
+type listStyleASTTransformer struct {
+	ListStyleConfig
+}
+
+// NewListStyleASTTransformer returns a new parser.ASTTransformer that
+// sets styles of ordered lists by their nesting depth.
+func NewListStyleASTTransformer(opts ...ListStyleOption) parser.ASTTransformer {
+	t := &listStyleASTTransformer{
+		ListStyleConfig: ListStyleConfig{
+			Styles: DefaultListStyles,
+		},
+	}
+	for _, o := range opts {
+		o.SetListStyleOption(&t.ListStyleConfig)
+	}
+	return t
+}
+
+func (a *listStyleASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	if !a.AutoListStyle || len(a.Styles) == 0 {
+		return
+	}
+	depth := 0
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		list, ok := n.(*gast.List)
+		if !ok || !list.IsOrdered() {
+			return gast.WalkContinue, nil
+		}
+		if !entering {
+			depth--
+			return gast.WalkContinue, nil
+		}
+		if _, ok := listStyleOf(list); !ok {
+			setListStyle(list, a.Styles[depth%len(a.Styles)])
+		}
+		depth++
+		return gast.WalkContinue, nil
+	})
+}
+
+type listStyle struct {
+	options []ListStyleOption
+}
+
+// ListStyle is an extension that allow you to use ordered lists like
+// 'a.', 'i.', 'A.', 'I.' and 'α.' . Lists are rendered like
+// '<ol type="a">'. Single letters are alphabets except 'i' and 'I', and
+// upper case letters followed by '.' need two spaces like Pandoc.
+var ListStyle = &listStyle{}
+
+// NewListStyle returns a new extension with given options.
+func NewListStyle(opts ...ListStyleOption) goldmark.Extender {
+	return &listStyle{
+		options: opts,
+	}
+}
+
+func (e *listStyle) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			util.Prioritized(NewStyledListParser(), 300),
+			util.Prioritized(NewStyledListItemParser(), 400),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(NewListStyleASTTransformer(e.options...), 999),
+		),
+	)
+}
@@ -0,0 +1,51 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestListStyle(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			ListStyle,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/list_style.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestAutoListStyle(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewListStyle(WithAutoListStyle(true), WithListStyles(ListStyleDecimal, ListStyleUpperRoman)),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No: 1,
+		Markdown: `1. one
+   1. two
+      1. three
+         a. four
+- five`,
+		Expected: `<ol>
+<li>one
+<ol type="I">
+<li>two
+<ol>
+<li>three
+<ol type="a">
+<li>four</li>
+</ol>
+</li>
+</ol>
+</li>
+</ol>
+</li>
+</ol>
+<ul>
+<li>five</li>
+</ul>`,
+	}, t)
+}
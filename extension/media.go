@@ -0,0 +1,231 @@
+package extension
+
+import (
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// mediaTypes is a map of file extensions and types of media.
+var mediaTypes = map[string]ast.MediaType{
+	".mp4":  ast.MediaVideo,
+	".webm": ast.MediaVideo,
+	".ogv":  ast.MediaVideo,
+	".ogg":  ast.MediaAudio,
+	".mp3":  ast.MediaAudio,
+	".wav":  ast.MediaAudio,
+}
+
+// mediaTypeOf returns a type of the media specified by the given URL.
+// mediaTypeOf returns 0 if the URL is not a video or an audio.
+func mediaTypeOf(url []byte) ast.MediaType {
+	s := string(url)
+	if i := strings.IndexAny(s, "?#"); i > -1 {
+		s = s[:i]
+	}
+	return mediaTypes[strings.ToLower(path.Ext(s))]
+}
+
+// parseMediaAttributes parses attributes like '{autoplay width=800}'.
+// Attributes without values like 'autoplay' are boolean attributes.
+// parseMediaAttributes returns attributes and a length of them.
+func parseMediaAttributes(value []byte) (parser.Attributes, int, bool) {
+	if len(value) == 0 || value[0] != '{' {
+		return nil, 0, false
+	}
+	attrs := parser.Attributes{}
+	i := 1
+	for {
+		i += util.TrimLeftSpaceLength(value[i:])
+		if i >= len(value) {
+			return nil, 0, false
+		}
+		if value[i] == '}' {
+			return attrs, i + 1, true
+		}
+		start := i
+		for ; i < len(value) && (util.IsAlphaNumeric(value[i]) || value[i] == '-' || value[i] == '_'); i++ {
+		}
+		if i == start {
+			return nil, 0, false
+		}
+		attr := parser.Attribute{Name: value[start:i], Value: []byte{}}
+		if i < len(value) && value[i] == '=' {
+			i++
+			if i >= len(value) {
+				return nil, 0, false
+			}
+			if q := value[i]; q == '"' || q == '\'' {
+				stop := bytes.IndexByte(value[i+1:], q)
+				if stop < 0 {
+					return nil, 0, false
+				}
+				attr.Value = value[i+1 : i+1+stop]
+				i += stop + 2
+			} else {
+				start = i
+				for ; i < len(value) && !util.IsSpace(value[i]) && value[i] != '}'; i++ {
+				}
+				attr.Value = value[start:i]
+			}
+		}
+		attrs = append(attrs, attr)
+	}
+}
+
+type mediaASTTransformer struct {
+}
+
+var defaultMediaASTTransformer = &mediaASTTransformer{}
+
+// NewMediaASTTransformer returns a new parser.ASTTransformer that
+// converts images those URL is a video or an audio into Media nodes.
+func NewMediaASTTransformer() parser.ASTTransformer {
+	return defaultMediaASTTransformer
+}
+
+func (a *mediaASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var images []*gast.Image
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if image, ok := n.(*gast.Image); ok && entering && mediaTypeOf(image.Destination) != 0 {
+			images = append(images, image)
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, image := range images {
+		media := ast.NewMedia(mediaTypeOf(image.Destination), image.Destination, image.Title)
+		for c := image.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			media.AppendChild(media, c)
+			c = next
+		}
+		media.SetAttributeString("controls", []byte{})
+		image.Parent().ReplaceChild(image.Parent(), image, media)
+		if t, ok := media.NextSibling().(*gast.Text); ok && t.Segment.Padding == 0 {
+			mergeNextTexts(t, source)
+			attrs, length, ok := parseMediaAttributes(t.Segment.Value(source))
+			if !ok {
+				continue
+			}
+			t.Segment = t.Segment.WithStart(t.Segment.Start + length)
+			if t.Segment.IsEmpty() && !t.SoftLineBreak() && !t.HardLineBreak() {
+				t.Parent().RemoveChild(t.Parent(), t)
+			}
+			for _, attr := range attrs {
+				media.SetAttribute(attr.Name, attr.Value)
+			}
+		}
+	}
+}
+
+// MediaAttributeFilter defines attribute names which video and audio
+// elements can have.
+var MediaAttributeFilter = html.GlobalAttributeFilter.Extend(
+	[]byte("autoplay"),
+	[]byte("controls"),
+	[]byte("crossorigin"),
+	[]byte("height"),
+	[]byte("loop"),
+	[]byte("muted"),
+	[]byte("playsinline"),
+	[]byte("poster"),
+	[]byte("preload"),
+	[]byte("width"),
+)
+
+// MediaHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Media nodes.
+type MediaHTMLRenderer struct {
+	html.Config
+}
+
+// NewMediaHTMLRenderer returns a new MediaHTMLRenderer.
+func NewMediaHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &MediaHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *MediaHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindMedia, r.renderMedia)
+}
+
+func (r *MediaHTMLRenderer) renderMedia(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*ast.Media)
+	var url []byte
+	if r.Unsafe || !html.IsDangerousURL(n.Destination) {
+		url = util.EscapeHTML(util.URLEscape(n.Destination, true))
+	}
+	if !entering {
+		if !n.HasChildren() {
+			_, _ = w.Write(url)
+		}
+		_, _ = w.WriteString("</a></")
+		_, _ = w.WriteString(n.MediaType.String())
+		_ = w.WriteByte('>')
+		return gast.WalkContinue, nil
+	}
+	_ = w.WriteByte('<')
+	_, _ = w.WriteString(n.MediaType.String())
+	_, _ = w.WriteString(` src="`)
+	_, _ = w.Write(url)
+	_ = w.WriteByte('"')
+	if n.Title != nil {
+		_, _ = w.WriteString(` title="`)
+		r.Writer.Write(w, n.Title)
+		_ = w.WriteByte('"')
+	}
+	for _, attr := range n.Attributes() {
+		if !MediaAttributeFilter.Contains(attr.Name) && !bytes.HasPrefix(attr.Name, []byte("data-")) {
+			continue
+		}
+		_ = w.WriteByte(' ')
+		_, _ = w.Write(attr.Name)
+		// boolean attributes are written without values
+		if value := attr.Value.([]byte); len(value) != 0 {
+			_, _ = w.WriteString(`="`)
+			_, _ = w.Write(util.EscapeHTML(value))
+			_ = w.WriteByte('"')
+		}
+	}
+	// browsers those do not support the element show a link to the media
+	_, _ = w.WriteString(`><a href="`)
+	_, _ = w.Write(url)
+	_, _ = w.WriteString(`">`)
+	return gast.WalkContinue, nil
+}
+
+type media struct {
+}
+
+// Media is an extension that renders images those URL ends with '.mp4',
+// '.webm', '.ogv', '.ogg', '.mp3' and '.wav' as '<video>' or '<audio>'
+// elements. Attributes like '![alt](video.mp4){autoplay muted width=800}'
+// are set to the elements. Alternative texts are rendered as links to the
+// media for browsers those do not support the elements.
+var Media = &media{}
+
+func (e *media) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewMediaASTTransformer(), 100),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewMediaHTMLRenderer(), 500),
+	))
+}
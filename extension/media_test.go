@@ -0,0 +1,17 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestMedia(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Media,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/media.txt", t, testutil.ParseCliCaseArg()...)
+}
@@ -0,0 +1,220 @@
+package extension
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// DefaultCalloutColor is a color of callouts whose emojis are not
+// configured.
+const DefaultCalloutColor = "gray_background"
+
+// DefaultCalloutEmojis is a default mapping from emojis to Notion
+// callout colors.
+var DefaultCalloutEmojis = map[rune]string{
+	'💡': "yellow_background",
+	'⚠': "orange_background",
+	'🔥': "orange_background",
+	'❗': "red_background",
+	'🚨': "red_background",
+	'✅': "green_background",
+	'ℹ': "blue_background",
+	'📝': "gray_background",
+	'❓': "purple_background",
+}
+
+// A NotionConfig struct is a data structure that holds configuration of the
+// Notion extension.
+type NotionConfig struct {
+	// CalloutEmojis maps emojis to Notion callout colors.
+	CalloutEmojis map[rune]string
+}
+
+const optCalloutEmojis parser.OptionName = "CalloutEmojis"
+
+// SetOption implements SetOptioner.
+func (c *NotionConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optCalloutEmojis:
+		c.CalloutEmojis = value.(map[rune]string)
+	}
+}
+
+// A NotionOption interface sets options for the Notion extension.
+type NotionOption interface {
+	parser.Option
+	SetNotionOption(*NotionConfig)
+}
+
+type withCalloutEmojis struct {
+	value map[rune]string
+}
+
+func (o *withCalloutEmojis) SetParserOption(c *parser.Config) {
+	c.Options[optCalloutEmojis] = o.value
+}
+
+func (o *withCalloutEmojis) SetNotionOption(c *NotionConfig) {
+	c.CalloutEmojis = o.value
+}
+
+// WithCalloutEmojis is a functional option that specify a mapping from
+// emojis to Notion callout colors like 'yellow_background'.
+// Blockquotes starting with other emojis are callouts with
+// DefaultCalloutColor.
+func WithCalloutEmojis(emojis map[rune]string) NotionOption {
+	return &withCalloutEmojis{emojis}
+}
+
+type calloutASTTransformer struct {
+	NotionConfig
+}
+
+// NewCalloutASTTransformer returns a new parser.ASTTransformer that
+// converts blockquotes starting with an emoji like '> 💡 Text' into
+// Callout nodes.
+func NewCalloutASTTransformer(opts ...NotionOption) parser.ASTTransformer {
+	t := &calloutASTTransformer{
+		NotionConfig: NotionConfig{
+			CalloutEmojis: DefaultCalloutEmojis,
+		},
+	}
+	for _, o := range opts {
+		o.SetNotionOption(&t.NotionConfig)
+	}
+	return t
+}
+
+// calloutIcon returns a length of the emoji at the beginning of the given
+// bytes and a color of the callout.
+// calloutIcon returns 0 if the bytes do not start with an emoji.
+func (a *calloutASTTransformer) calloutIcon(v []byte) (int, string) {
+	r, n := utf8.DecodeRune(v)
+	color, ok := a.CalloutEmojis[r]
+	if !ok {
+		if r < 0x2000 || !unicode.Is(unicode.So, r) {
+			return 0, ""
+		}
+		color = DefaultCalloutColor
+	}
+	// emojis are often followed by a variation selector like '⚠️'
+	if r, l := utf8.DecodeRune(v[n:]); r == 0xFE0F {
+		n += l
+	}
+	return n, color
+}
+
+func (a *calloutASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	var blockquotes []gast.Node
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if entering && n.Kind() == gast.KindBlockquote {
+			blockquotes = append(blockquotes, n)
+		}
+		return gast.WalkContinue, nil
+	})
+	source := reader.Source()
+	for _, blockquote := range blockquotes {
+		paragraph, ok := blockquote.FirstChild().(*gast.Paragraph)
+		if !ok {
+			continue
+		}
+		first, ok := paragraph.FirstChild().(*gast.Text)
+		if !ok {
+			continue
+		}
+		length, color := a.calloutIcon(first.Segment.Value(source))
+		if length == 0 {
+			continue
+		}
+		icon := source[first.Segment.Start : first.Segment.Start+length]
+		callout := ast.NewCallout(icon, color)
+		first.Segment = first.Segment.WithStart(first.Segment.Start + length)
+		first.Segment = first.Segment.TrimLeftSpace(source)
+		if first.Segment.IsEmpty() {
+			paragraph.RemoveChild(paragraph, first)
+		}
+		if !paragraph.HasChildren() {
+			blockquote.RemoveChild(blockquote, paragraph)
+		}
+		for c := blockquote.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			callout.AppendChild(callout, c)
+			c = next
+		}
+		blockquote.Parent().ReplaceChild(blockquote.Parent(), blockquote, callout)
+	}
+}
+
+// CalloutHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Callout nodes.
+type CalloutHTMLRenderer struct {
+	html.Config
+}
+
+// NewCalloutHTMLRenderer returns a new CalloutHTMLRenderer.
+func NewCalloutHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &CalloutHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *CalloutHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindCallout, r.renderCallout)
+}
+
+func (r *CalloutHTMLRenderer) renderCallout(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Callout)
+		_, _ = w.WriteString(`<div class="callout" data-icon="`)
+		_, _ = w.Write(util.EscapeHTML(n.Icon))
+		_, _ = w.WriteString(`" data-color="`)
+		_, _ = w.Write(util.EscapeHTML([]byte(n.Color)))
+		_ = w.WriteByte('"')
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, html.GlobalAttributeFilter)
+		}
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</div>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+type notion struct {
+	options []NotionOption
+}
+
+// Notion is an extension that allow you to use Notion style callouts
+// like '> 💡 Text' .
+var Notion = &notion{}
+
+// NewNotion returns a new extension with given options.
+func NewNotion(opts ...NotionOption) goldmark.Extender {
+	return &notion{
+		options: opts,
+	}
+}
+
+func (e *notion) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewCalloutASTTransformer(e.options...), 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewCalloutHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,34 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestNotion(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Notion,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/notion.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestNotionCalloutEmojis(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewNotion(
+				WithCalloutEmojis(map[rune]string{'🦄': "pink_background"}),
+			),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:       1,
+		Markdown: "> 🦄 Unicorns",
+		Expected: `<div class="callout" data-icon="🦄" data-color="pink_background">
+<p>Unicorns</p>
+</div>`,
+	}, t)
+}
@@ -0,0 +1,256 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Locale struct represents separators of numbers.
+type Locale struct {
+	// GroupSeparator is a separator of digit groups like ','.
+	GroupSeparator string
+
+	// DecimalSeparator is a separator of decimals like '.'.
+	DecimalSeparator string
+
+	// MinimumGroupingDigits is a minimum number of digits of the first
+	// group like CLDR. Numbers like '1000' are not grouped if this is 2.
+	// Values less than 1 are treated as 1.
+	MinimumGroupingDigits int
+}
+
+var (
+	// LocaleEnUS is a locale that formats numbers like '1,000,000.5'.
+	LocaleEnUS = Locale{GroupSeparator: ",", DecimalSeparator: "."}
+
+	// LocaleDeDE is a locale that formats numbers like '1.000.000,5'.
+	LocaleDeDE = Locale{GroupSeparator: ".", DecimalSeparator: ","}
+
+	// LocaleFrFR is a locale that formats numbers like '1 000 000,5'.
+	// Digit groups are separated by narrow no-break spaces.
+	LocaleFrFR = Locale{GroupSeparator: "\u202f", DecimalSeparator: ","}
+)
+
+// format returns a formatted number of the given integer and decimal
+// digits. format returns nil if the number does not need to be formatted.
+func (l Locale) format(integer, decimal []byte) []byte {
+	if len(integer) > 1 && integer[0] == '0' {
+		// numbers like '007' are codes rather than numbers
+		return nil
+	}
+	min := l.MinimumGroupingDigits
+	if min < 1 {
+		min = 1
+	}
+	group := len(integer) >= 3+min
+	if !group && (len(decimal) == 0 || l.DecimalSeparator == ".") {
+		return nil
+	}
+	var b []byte
+	for i, c := range integer {
+		if group && i != 0 && (len(integer)-i)%3 == 0 {
+			b = append(b, l.GroupSeparator...)
+		}
+		b = append(b, c)
+	}
+	if len(decimal) != 0 {
+		b = append(b, l.DecimalSeparator...)
+		b = append(b, decimal...)
+	}
+	return b
+}
+
+// A NumberFormatConfig struct is a data structure that holds configuration of the
+// NumberFormat extension.
+type NumberFormatConfig struct {
+	// Locale is a locale of formatted numbers.
+	Locale Locale
+}
+
+const optLocale parser.OptionName = "Locale"
+
+// SetOption implements SetOptioner.
+func (c *NumberFormatConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optLocale:
+		c.Locale = value.(Locale)
+	}
+}
+
+// A NumberFormatOption interface sets options for the NumberFormat extension.
+type NumberFormatOption interface {
+	parser.Option
+	SetNumberFormatOption(*NumberFormatConfig)
+}
+
+type withLocale struct {
+	value Locale
+}
+
+func (o *withLocale) SetParserOption(c *parser.Config) {
+	c.Options[optLocale] = o.value
+}
+
+func (o *withLocale) SetNumberFormatOption(c *NumberFormatConfig) {
+	c.Locale = o.value
+}
+
+// WithLocale is a functional option that specify a locale of formatted
+// numbers. This defaults to LocaleEnUS.
+func WithLocale(value Locale) NumberFormatOption {
+	return &withLocale{value}
+}
+
+type numberFormatASTTransformer struct {
+	NumberFormatConfig
+}
+
+// NewNumberFormatASTTransformer returns a new parser.ASTTransformer that
+// formats numbers in texts.
+func NewNumberFormatASTTransformer(opts ...NumberFormatOption) parser.ASTTransformer {
+	t := &numberFormatASTTransformer{
+		NumberFormatConfig: NumberFormatConfig{
+			Locale: LocaleEnUS,
+		},
+	}
+	for _, o := range opts {
+		o.SetNumberFormatOption(&t.NumberFormatConfig)
+	}
+	return t
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isNumberJoiner returns true if the given character joins numbers like
+// dates, times, versions and formatted numbers.
+func isNumberJoiner(c byte) bool {
+	return c == '.' || c == ',' || c == ':' || c == '/' || c == '-'
+}
+
+// isStandaloneNumber returns true if value[start:stop] is a number that is
+// not a part of identifiers like 'x1000', dates like '2024-01-01',
+// versions like '1.2.3' and numbers followed by letters like '1000px'.
+func isStandaloneNumber(value []byte, start, stop int) bool {
+	if start > 0 {
+		c := value[start-1]
+		if util.IsAlphaNumeric(c) || c == '_' || c == '#' {
+			return false
+		}
+		if start > 1 && isNumberJoiner(c) && isDigit(value[start-2]) {
+			return false
+		}
+	}
+	if stop < len(value) {
+		c := value[stop]
+		if util.IsAlphaNumeric(c) || c == '_' {
+			return false
+		}
+		if stop+1 < len(value) && isNumberJoiner(c) && isDigit(value[stop+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatNumbers replaces numbers in the given Text node with formatted
+// numbers. Formatted numbers are inserted as String nodes.
+func (a *numberFormatASTTransformer) formatNumbers(n *gast.Text, source []byte) {
+	parent := n.Parent()
+	segment := n.Segment
+	value := source[segment.Start:segment.Stop]
+	last := 0
+	for i := 0; i < len(value); {
+		if !isDigit(value[i]) {
+			i++
+			continue
+		}
+		start := i
+		for ; i < len(value) && isDigit(value[i]); i++ {
+		}
+		integer := value[start:i]
+		var decimal []byte
+		if i+1 < len(value) && value[i] == '.' && isDigit(value[i+1]) {
+			j := i + 1
+			for ; j < len(value) && isDigit(value[j]); j++ {
+			}
+			decimal = value[i+1 : j]
+			i = j
+		}
+		if !isStandaloneNumber(value, start, i) {
+			continue
+		}
+		formatted := a.Locale.format(integer, decimal)
+		if formatted == nil {
+			continue
+		}
+		if last == 0 {
+			if start != 0 || segment.Padding != 0 {
+				parent.InsertBefore(parent, n, gast.NewTextSegment(segment.WithStop(segment.Start+start)))
+			}
+		} else if start != last {
+			parent.InsertBefore(parent, n, gast.NewTextSegment(
+				text.NewSegment(segment.Start+last, segment.Start+start)))
+		}
+		parent.InsertBefore(parent, n, gast.NewString(formatted))
+		last = i
+	}
+	if last != 0 {
+		n.Segment = text.NewSegment(segment.Start+last, segment.Stop)
+	}
+}
+
+func (a *numberFormatASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var texts []*gast.Text
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch v := n.(type) {
+		case *gast.CodeSpan:
+			return gast.WalkSkipChildren, nil
+		case *gast.Text:
+			if !v.IsRaw() {
+				texts = append(texts, v)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, t := range texts {
+		if t.Parent() == nil {
+			continue
+		}
+		mergeNextTexts(t, source)
+		a.formatNumbers(t, source)
+	}
+}
+
+type numberFormat struct {
+	options []NumberFormatOption
+}
+
+// NumberFormat is an extension that formats numbers in texts like
+// '1000000' as '1,000,000'. Use WithLocale to format numbers like
+// '1.000.000' or '1 000 000'. Numbers in code spans, numbers followed by
+// letters like '1000px', parts of identifiers like 'x1000', dates like
+// '2024-01-01', times, versions like '1.2.3' and numbers those start with
+// '0' like '0123' are not formatted.
+var NumberFormat = &numberFormat{}
+
+// NewNumberFormat returns a new extension with given options.
+func NewNumberFormat(opts ...NumberFormatOption) goldmark.Extender {
+	return &numberFormat{
+		options: opts,
+	}
+}
+
+func (e *numberFormat) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewNumberFormatASTTransformer(e.options...), 999),
+	))
+}
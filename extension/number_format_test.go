@@ -0,0 +1,42 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestNumberFormat(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NumberFormat,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/number_format.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestNumberFormatLocale(t *testing.T) {
+	source := "1000000 and 1234.5 in 2024"
+	cases := []struct {
+		locale   Locale
+		expected string
+	}{
+		{LocaleDeDE, "<p>1.000.000 and 1.234,5 in 2.024</p>"},
+		{LocaleFrFR, "<p>1\u202f000\u202f000 and 1\u202f234,5 in 2\u202f024</p>"},
+		{Locale{GroupSeparator: ",", DecimalSeparator: ".", MinimumGroupingDigits: 2},
+			"<p>1,000,000 and 1234.5 in 2024</p>"},
+	}
+	for i, c := range cases {
+		markdown := goldmark.New(
+			goldmark.WithExtensions(
+				NewNumberFormat(WithLocale(c.locale)),
+			),
+		)
+		testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+			No:       i + 1,
+			Markdown: source,
+			Expected: c.expected,
+		}, t)
+	}
+}
@@ -0,0 +1,282 @@
+package extension
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// NumberingMetaKey is a key of the document metadata that holds
+// a map of labels and numbers assigned by the Numbering extension.
+const NumberingMetaKey = "Numbering"
+
+// UndefinedNumber is a text that replaces references to undefined labels.
+const UndefinedNumber = "??"
+
+// GetNumberingLabels returns a map of labels like 'fig:label' and numbers
+// of the document that owns the given node.
+func GetNumberingLabels(node gast.Node) map[string]int {
+	doc := node.OwnerDocument()
+	if doc == nil {
+		return nil
+	}
+	labels, _ := doc.Meta()[NumberingMetaKey].(map[string]int)
+	return labels
+}
+
+// An UndefinedLabelHandler is a function that is called with a label of
+// a reference like '[ref:fig:label]' those target is not defined.
+type UndefinedLabelHandler func(label string)
+
+// A NumberingConfig struct is a data structure that holds configuration of the
+// Numbering extension.
+type NumberingConfig struct {
+	// UndefinedLabelHandler is called when a reference to an undefined
+	// label is found.
+	UndefinedLabelHandler UndefinedLabelHandler
+}
+
+const optUndefinedLabelHandler parser.OptionName = "UndefinedLabelHandler"
+
+// SetOption implements SetOptioner.
+func (c *NumberingConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optUndefinedLabelHandler:
+		c.UndefinedLabelHandler = value.(UndefinedLabelHandler)
+	}
+}
+
+// A NumberingOption interface sets options for the Numbering extension.
+type NumberingOption interface {
+	parser.Option
+	SetNumberingOption(*NumberingConfig)
+}
+
+type withUndefinedLabelHandler struct {
+	value UndefinedLabelHandler
+}
+
+func (o *withUndefinedLabelHandler) SetParserOption(c *parser.Config) {
+	c.Options[optUndefinedLabelHandler] = o.value
+}
+
+func (o *withUndefinedLabelHandler) SetNumberingOption(c *NumberingConfig) {
+	c.UndefinedLabelHandler = o.value
+}
+
+// WithUndefinedLabelHandler is a functional option that specify a
+// function called when a reference to an undefined label is found.
+// References to undefined labels are replaced with UndefinedNumber
+// regardless of this option.
+func WithUndefinedLabelHandler(value UndefinedLabelHandler) NumberingOption {
+	return &withUndefinedLabelHandler{value}
+}
+
+type numberingASTTransformer struct {
+	NumberingConfig
+}
+
+// NewNumberingASTTransformer returns a new parser.ASTTransformer that
+// numbers figures and tables and replaces references to their labels.
+func NewNumberingASTTransformer(opts ...NumberingOption) parser.ASTTransformer {
+	t := &numberingASTTransformer{}
+	for _, o := range opts {
+		o.SetNumberingOption(&t.NumberingConfig)
+	}
+	return t
+}
+
+var (
+	numberingRefOpener = []byte("[ref:")
+	numberingFigPrefix = []byte("fig:")
+	numberingTblPrefix = []byte("tbl:")
+)
+
+// numberingLabel returns a label of a value like '{#fig:label}'.
+// numberingLabel returns nil if the value is not a label with the prefix.
+func numberingLabel(value, prefix []byte) []byte {
+	if len(value) < 3 || value[0] != '{' || value[1] != '#' || value[len(value)-1] != '}' {
+		return nil
+	}
+	label := value[2 : len(value)-1]
+	if !bytes.HasPrefix(label, prefix) || len(label) == len(prefix) || !isNumberingLabel(label) {
+		return nil
+	}
+	return label
+}
+
+// isNumberingLabel returns true if the given label consists of
+// alphanumerics, '-', '_', ':' and '.' .
+func isNumberingLabel(label []byte) bool {
+	if len(label) == 0 {
+		return false
+	}
+	for _, c := range label {
+		if !util.IsAlphaNumeric(c) && c != '-' && c != '_' && c != ':' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// figureLabel returns true if the given paragraph consists of an image
+// and an optional label like '{#fig:label}'. The label is removed from
+// the paragraph.
+func figureLabel(p *gast.Paragraph, source []byte) ([]byte, bool) {
+	image, ok := p.FirstChild().(*gast.Image)
+	if !ok {
+		return nil, false
+	}
+	next := image.NextSibling()
+	if next == nil {
+		return nil, true
+	}
+	t, ok := next.(*gast.Text)
+	if !ok {
+		return nil, false
+	}
+	mergeNextTexts(t, source)
+	if t.NextSibling() != nil {
+		return nil, false
+	}
+	value := util.TrimRightSpace(util.TrimLeftSpace(t.Segment.Value(source)))
+	if len(value) == 0 {
+		return nil, true
+	}
+	label := numberingLabel(value, numberingFigPrefix)
+	if label == nil {
+		return nil, false
+	}
+	p.RemoveChild(p, t)
+	return label, true
+}
+
+func (a *numberingASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	labels := map[string]int{}
+	figures, tables := 0, 0
+	var texts []*gast.Text
+	var markers []gast.Node
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		var label []byte
+		number := 0
+		switch v := n.(type) {
+		case *gast.Paragraph:
+			l, ok := figureLabel(v, source)
+			if !ok {
+				return gast.WalkContinue, nil
+			}
+			figures++
+			label, number = l, figures
+		case *ast.Table:
+			if marker, value := tableMarker(v, source); marker != nil {
+				if label = numberingLabel(value, numberingTblPrefix); label != nil {
+					markers = append(markers, marker)
+				}
+			}
+			tables++
+			number = tables
+		case *gast.CodeSpan, *gast.Link, *gast.Image:
+			return gast.WalkSkipChildren, nil
+		case *gast.Text:
+			if !v.IsRaw() {
+				texts = append(texts, v)
+			}
+			return gast.WalkContinue, nil
+		default:
+			return gast.WalkContinue, nil
+		}
+		if label != nil {
+			n.SetAttributeString("id", label)
+			labels[string(label)] = number
+		}
+		n.SetAttributeString("data-number", []byte(strconv.Itoa(number)))
+		return gast.WalkContinue, nil
+	})
+	for _, marker := range markers {
+		marker.Parent().RemoveChild(marker.Parent(), marker)
+	}
+	for _, t := range texts {
+		if t.Parent() == nil {
+			continue
+		}
+		mergeNextTexts(t, source)
+		a.replaceReferences(t, source, labels)
+	}
+	node.AddMeta(NumberingMetaKey, labels)
+}
+
+// replaceReferences replaces references like '[ref:fig:label]' in the
+// given Text node with links to the labels.
+func (a *numberingASTTransformer) replaceReferences(n *gast.Text, source []byte, labels map[string]int) {
+	parent := n.Parent()
+	for {
+		value := source[n.Segment.Start:n.Segment.Stop]
+		start := bytes.Index(value, numberingRefOpener)
+		if start < 0 {
+			return
+		}
+		stop := bytes.IndexByte(value[start:], ']')
+		if stop < 0 {
+			return
+		}
+		stop += start
+		label := value[start+len(numberingRefOpener) : stop]
+		if !isNumberingLabel(label) {
+			parent.InsertBefore(parent, n, gast.NewTextSegment(n.Segment.WithStop(n.Segment.Start+start+1)))
+			n.Segment = text.NewSegment(n.Segment.Start+start+1, n.Segment.Stop)
+			continue
+		}
+		if start != 0 || n.Segment.Padding != 0 {
+			parent.InsertBefore(parent, n, gast.NewTextSegment(n.Segment.WithStop(n.Segment.Start+start)))
+		}
+		n.Segment = text.NewSegment(n.Segment.Start+stop+1, n.Segment.Stop)
+		number, ok := labels[string(label)]
+		if !ok {
+			if a.UndefinedLabelHandler != nil {
+				a.UndefinedLabelHandler(string(label))
+			}
+			parent.InsertBefore(parent, n, gast.NewString([]byte(UndefinedNumber)))
+			continue
+		}
+		link := gast.NewLink()
+		link.Destination = append([]byte{'#'}, label...)
+		link.AppendChild(link, gast.NewString([]byte(strconv.Itoa(number))))
+		parent.InsertBefore(parent, n, link)
+	}
+}
+
+type numbering struct {
+	options []NumberingOption
+}
+
+// Numbering is an extension that numbers figures and tables.
+// A paragraph that consists of an image is a figure and can have a label
+// like '![alt](image.png) {#fig:label}'. A table can have a label like
+// '{#tbl:label}' in the line just before the table. Numbers are rendered
+// as 'data-number' attributes and labels are rendered as ids.
+// References like '[ref:fig:label]' are replaced with links to the labels
+// those texts are numbers.
+var Numbering = &numbering{}
+
+// NewNumbering returns a new extension with given options.
+func NewNumbering(opts ...NumberingOption) goldmark.Extender {
+	return &numbering{
+		options: opts,
+	}
+}
+
+func (e *numbering) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewNumberingASTTransformer(e.options...), 1000),
+	))
+}
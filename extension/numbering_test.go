@@ -0,0 +1,49 @@
+package extension
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestNumbering(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Table,
+			Numbering,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/numbering.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestNumberingStable(t *testing.T) {
+	var undefined []string
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewNumbering(WithUndefinedLabelHandler(func(label string) {
+				undefined = append(undefined, label)
+			})),
+		),
+	)
+	source := []byte("![a](a.png)\n\n![b](b.png) {#fig:b}\n\n[ref:fig:b] [ref:fig:c]\n")
+	for i := 0; i < 2; i++ {
+		doc := markdown.Parser().Parse(text.NewReader(source))
+		if labels, expected := GetNumberingLabels(doc), map[string]int{"fig:b": 2}; !reflect.DeepEqual(labels, expected) {
+			t.Errorf("expected %v, but got %v", expected, labels)
+		}
+		var b bytes.Buffer
+		if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(b.Bytes(), []byte(`<a href="#fig:b">2</a> ??`)) {
+			t.Errorf("unexpected output: %s", b.String())
+		}
+	}
+	if expected := []string{"fig:c", "fig:c"}; !reflect.DeepEqual(undefined, expected) {
+		t.Errorf("expected %v, but got %v", expected, undefined)
+	}
+}
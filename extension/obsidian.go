@@ -0,0 +1,344 @@
+package extension
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// ObsidianPropertiesMetaKey is a key of the document metadata that holds
+// Obsidian properties as a map[string]interface{}.
+const ObsidianPropertiesMetaKey = "ObsidianProperties"
+
+// An ObsidianLink struct represents a wikilink like '[[Note|Alias]]' in
+// a property value.
+type ObsidianLink struct {
+	// Target is a name of the linked note.
+	Target string
+
+	// Alias is a displayed text of the link. Alias is empty if the link
+	// has no alias.
+	Alias string
+}
+
+// String implements fmt.Stringer.
+func (l ObsidianLink) String() string {
+	if len(l.Alias) != 0 {
+		return "[[" + l.Target + "|" + l.Alias + "]]"
+	}
+	return "[[" + l.Target + "]]"
+}
+
+// GetObsidianProperties returns Obsidian properties of the document that
+// owns the given node. GetObsidianProperties returns nil if the document
+// has no properties block.
+func GetObsidianProperties(node gast.Node) map[string]interface{} {
+	doc := node.OwnerDocument()
+	if doc == nil {
+		return nil
+	}
+	properties, _ := doc.Meta()[ObsidianPropertiesMetaKey].(map[string]interface{})
+	return properties
+}
+
+// obsidianListProperties are properties those values are always lists
+// of strings in Obsidian.
+var obsidianListProperties = map[string]bool{
+	"tags":       true,
+	"aliases":    true,
+	"cssclasses": true,
+}
+
+// ParseObsidianProperties parses a YAML subset used by Obsidian properties.
+// Values are converted as follows:
+//
+//   - Texts are strings. Wikilinks like "[[Note]]" are ObsidianLinks.
+//   - Lists like '[a, b]' or lines starting with '- ' are []interface{}.
+//   - Numbers are int64 or float64 and checkboxes are bool.
+//   - Dates like 2006-01-02 and 2006-01-02T15:04 are time.Time.
+//   - tags, aliases and cssclasses are []string. A leading '#' of tags
+//     is removed.
+//
+// Lines that are not properties are ignored.
+func ParseObsidianProperties(source []byte) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var key string
+	for _, line := range strings.Split(string(source), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 || trimmed[0] == '#' {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if len(key) == 0 {
+				continue
+			}
+			list, _ := properties[key].([]interface{})
+			properties[key] = append(list, parseObsidianValue(strings.TrimSpace(trimmed[1:])))
+			continue
+		}
+		i := strings.Index(trimmed, ":")
+		if i < 1 || line[0] == ' ' || line[0] == '\t' {
+			key = ""
+			continue
+		}
+		key = strings.TrimSpace(trimmed[:i])
+		if value := strings.TrimSpace(trimmed[i+1:]); len(value) != 0 {
+			properties[key] = parseObsidianValue(value)
+		} else {
+			properties[key] = nil
+		}
+	}
+	for key, value := range properties {
+		if obsidianListProperties[key] {
+			properties[key] = obsidianStringList(key, value)
+		}
+	}
+	return properties
+}
+
+func parseObsidianValue(value string) interface{} {
+	if len(value) > 1 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		if value[0] == '"' {
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return obsidianText(unquoted)
+			}
+		}
+		return obsidianText(strings.ReplaceAll(value[1:len(value)-1], "''", "'"))
+	}
+	if strings.HasPrefix(value, "[[") {
+		return obsidianText(value)
+	}
+	if value[0] == '[' && value[len(value)-1] == ']' {
+		list := []interface{}{}
+		for _, item := range splitObsidianList(value[1 : len(value)-1]) {
+			list = append(list, parseObsidianValue(item))
+		}
+		return list
+	}
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return obsidianText(value)
+}
+
+// splitObsidianList splits items of a flow sequence by ',' outside quotes.
+func splitObsidianList(value string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+	items = append(items, value[start:])
+	result := items[:0]
+	for _, item := range items {
+		if item = strings.TrimSpace(item); len(item) != 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func obsidianText(value string) interface{} {
+	if strings.HasPrefix(value, "[[") && strings.HasSuffix(value, "]]") {
+		link := value[2 : len(value)-2]
+		if !strings.Contains(link, "[[") && !strings.Contains(link, "]]") {
+			target, alias := link, ""
+			if i := strings.Index(link, "|"); i > -1 {
+				target, alias = link[:i], link[i+1:]
+			}
+			return ObsidianLink{Target: strings.TrimSpace(target), Alias: strings.TrimSpace(alias)}
+		}
+	}
+	return value
+}
+
+func obsidianStringList(key string, value interface{}) []string {
+	var values []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		values = v
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			values = append(values, strings.TrimSpace(item))
+		}
+	case nil:
+	default:
+		values = []interface{}{v}
+	}
+	result := []string{}
+	for _, v := range values {
+		var s string
+		switch x := v.(type) {
+		case string:
+			s = x
+		case ObsidianLink:
+			s = x.String()
+		case time.Time:
+			s = x.Format("2006-01-02")
+		default:
+			s = fmtObsidianValue(x)
+		}
+		if key == "tags" {
+			s = strings.TrimPrefix(s, "#")
+		}
+		if len(s) != 0 {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func fmtObsidianValue(v interface{}) string {
+	switch x := v.(type) {
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	}
+	return ""
+}
+
+type obsidianPropertiesParser struct {
+}
+
+var defaultObsidianPropertiesParser = &obsidianPropertiesParser{}
+
+// NewObsidianPropertiesParser returns a new parser.BlockParser that can
+// parse an Obsidian properties block between '---' lines at the start of
+// the document.
+func NewObsidianPropertiesParser() parser.BlockParser {
+	return defaultObsidianPropertiesParser
+}
+
+func isObsidianPropertiesDelimiter(line []byte) bool {
+	return string(util.TrimRightSpace(line)) == "---"
+}
+
+func (b *obsidianPropertiesParser) Trigger() []byte {
+	return []byte{'-'}
+}
+
+func (b *obsidianPropertiesParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	if segment.Start != 0 || parent.Kind() != gast.KindDocument || !isObsidianPropertiesDelimiter(line) {
+		return nil, parser.NoChildren
+	}
+	reader.Advance(segment.Len() - 1)
+	return ast.NewObsidianProperties(), parser.NoChildren
+}
+
+func (b *obsidianPropertiesParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if isObsidianPropertiesDelimiter(line) {
+		reader.Advance(segment.Len())
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	reader.Advance(segment.Len() - 1)
+	return parser.Continue | parser.NoChildren
+}
+
+func (b *obsidianPropertiesParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	n := node.(*ast.ObsidianProperties)
+	var source []byte
+	for i := 0; i < n.Lines().Len(); i++ {
+		segment := n.Lines().At(i)
+		source = append(source, segment.Value(reader.Source())...)
+	}
+	n.Properties = ParseObsidianProperties(source)
+	if doc := node.OwnerDocument(); doc != nil {
+		doc.AddMeta(ObsidianPropertiesMetaKey, n.Properties)
+	}
+}
+
+func (b *obsidianPropertiesParser) CanInterruptParagraph() bool {
+	return false
+}
+
+func (b *obsidianPropertiesParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// ObsidianPropertiesHTMLRenderer is a renderer.NodeRenderer implementation
+// that renders ObsidianProperties nodes. Properties are not rendered.
+type ObsidianPropertiesHTMLRenderer struct {
+	html.Config
+}
+
+// NewObsidianPropertiesHTMLRenderer returns a new
+// ObsidianPropertiesHTMLRenderer.
+func NewObsidianPropertiesHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &ObsidianPropertiesHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *ObsidianPropertiesHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindObsidianProperties, r.renderObsidianProperties)
+}
+
+func (r *ObsidianPropertiesHTMLRenderer) renderObsidianProperties(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	return gast.WalkSkipChildren, nil
+}
+
+type obsidianProperties struct {
+}
+
+// ObsidianProperties is an extension that parses an Obsidian properties
+// block between '---' lines at the start of the document. The properties
+// block is not rendered and its properties are stored as the document
+// metadata with the ObsidianPropertiesMetaKey. Use GetObsidianProperties
+// to get them.
+var ObsidianProperties = &obsidianProperties{}
+
+func (e *obsidianProperties) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithBlockParsers(
+		util.Prioritized(NewObsidianPropertiesParser(), 0),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewObsidianPropertiesHTMLRenderer(), 500),
+	))
+}
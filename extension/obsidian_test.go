@@ -0,0 +1,95 @@
+package extension
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestParseObsidianProperties(t *testing.T) {
+	source := []byte(`title: "My: Note"
+count: 3
+ratio: 0.5
+done: true
+created: 2024-01-02
+updated: 2024-01-02T10:30
+related: "[[Other Note|Other]]"
+links:
+  - "[[A]]"
+  - plain
+inline: [1, "a, b", 'it''s']
+tags: [#project, idea]
+aliases: First, Second
+cssclasses:
+empty:
+# comment
+  nested: ignored
+`)
+	expected := map[string]interface{}{
+		"title":      "My: Note",
+		"count":      int64(3),
+		"ratio":      0.5,
+		"done":       true,
+		"created":    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		"updated":    time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC),
+		"related":    ObsidianLink{Target: "Other Note", Alias: "Other"},
+		"links":      []interface{}{ObsidianLink{Target: "A"}, "plain"},
+		"inline":     []interface{}{int64(1), "a, b", "it's"},
+		"tags":       []string{"project", "idea"},
+		"aliases":    []string{"First", "Second"},
+		"cssclasses": []string{},
+		"empty":      nil,
+	}
+	actual := ParseObsidianProperties(source)
+	for key, value := range expected {
+		if !reflect.DeepEqual(actual[key], value) {
+			t.Errorf("%s: expected %#v, but got %#v", key, value, actual[key])
+		}
+	}
+	if len(actual) != len(expected) {
+		t.Errorf("expected %d properties, but got %v", len(expected), actual)
+	}
+}
+
+func TestObsidianProperties(t *testing.T) {
+	markdown := goldmark.New(goldmark.WithExtensions(ObsidianProperties))
+	cases := []struct {
+		source     string
+		properties map[string]interface{}
+		expected   string
+	}{
+		{
+			"---\ntags: [a]\n---\n# Title\n",
+			map[string]interface{}{"tags": []string{"a"}},
+			"<h1>Title</h1>\n",
+		},
+		{
+			"---\n---\ntext\n",
+			map[string]interface{}{},
+			"<p>text</p>\n",
+		},
+		{
+			"text\n\n---\ntags: [a]\n---\n",
+			nil,
+			"<p>text</p>\n<hr>\n<h2>tags: [a]</h2>\n",
+		},
+	}
+	for i, c := range cases {
+		source := []byte(c.source)
+		doc := markdown.Parser().Parse(text.NewReader(source))
+		if properties := GetObsidianProperties(doc); !reflect.DeepEqual(properties, c.properties) {
+			t.Errorf("case %d: expected %#v, but got %#v", i, c.properties, properties)
+		}
+		var b bytes.Buffer
+		if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != c.expected {
+			t.Errorf("case %d: expected %q, but got %q", i, c.expected, b.String())
+		}
+	}
+}
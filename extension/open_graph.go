@@ -0,0 +1,220 @@
+package extension
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// OpenGraphMetaKey is a key of the document metadata that holds
+// a FrontMatter read by the OpenGraph extension.
+const OpenGraphMetaKey = "OpenGraph"
+
+// A FrontMatter struct holds fields of front matters those are
+// reflected in Open Graph meta tags.
+type FrontMatter struct {
+	// Title is a title of the document.
+	Title string
+
+	// Description is a description of the document.
+	Description string
+
+	// Image is an URL of an image of the document.
+	Image string
+}
+
+// IsEmpty returns true if all fields are empty.
+func (f FrontMatter) IsEmpty() bool {
+	return len(f.Title) == 0 && len(f.Description) == 0 && len(f.Image) == 0
+}
+
+// A HeadWriter is a function that writes contents of an HTML head
+// element like meta tags from the given FrontMatter.
+type HeadWriter func(w io.Writer, meta FrontMatter)
+
+// DefaultHeadWriter is a HeadWriter that writes meta tags like
+// '<meta property="og:title" content="Title">'. Empty fields are omitted.
+func DefaultHeadWriter(w io.Writer, meta FrontMatter) {
+	for _, p := range [][2]string{
+		{"og:title", meta.Title},
+		{"og:description", meta.Description},
+		{"og:image", meta.Image},
+	} {
+		if len(p[1]) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "<meta property=\"%s\" content=\"%s\">\n",
+			p[0], util.EscapeHTML([]byte(p[1])))
+	}
+}
+
+// GetFrontMatter returns a FrontMatter of the document that owns the
+// given node. GetFrontMatter returns false if the OpenGraph extension is
+// not enabled or the document has no front matters.
+func GetFrontMatter(node gast.Node) (FrontMatter, bool) {
+	head, ok := getOpenGraphHead(node)
+	return head.meta, ok
+}
+
+// WriteOpenGraphHead writes Open Graph meta tags of the document that
+// owns the given node by the HeadWriter of the OpenGraph extension.
+// WriteOpenGraphHead writes nothing and returns false if the document
+// has no front matters.
+func WriteOpenGraphHead(w io.Writer, node gast.Node) bool {
+	head, ok := getOpenGraphHead(node)
+	if !ok {
+		return false
+	}
+	head.writer(w, head.meta)
+	return true
+}
+
+type openGraphHead struct {
+	meta   FrontMatter
+	writer HeadWriter
+}
+
+func getOpenGraphHead(node gast.Node) (openGraphHead, bool) {
+	doc := node.OwnerDocument()
+	if doc == nil {
+		return openGraphHead{}, false
+	}
+	head, ok := doc.Meta()[OpenGraphMetaKey].(openGraphHead)
+	return head, ok
+}
+
+// An OpenGraphConfig struct is a data structure that holds configuration of the
+// OpenGraph extension.
+type OpenGraphConfig struct {
+	// HeadWriter writes contents of an HTML head element.
+	HeadWriter HeadWriter
+}
+
+const optHeadWriter parser.OptionName = "HeadWriter"
+
+// SetOption implements SetOptioner.
+func (c *OpenGraphConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optHeadWriter:
+		c.HeadWriter = value.(HeadWriter)
+	}
+}
+
+// An OpenGraphOption interface sets options for the OpenGraph extension.
+type OpenGraphOption interface {
+	parser.Option
+	SetOpenGraphOption(*OpenGraphConfig)
+}
+
+type withHeadWriter struct {
+	value HeadWriter
+}
+
+func (o *withHeadWriter) SetParserOption(c *parser.Config) {
+	c.Options[optHeadWriter] = o.value
+}
+
+func (o *withHeadWriter) SetOpenGraphOption(c *OpenGraphConfig) {
+	c.HeadWriter = o.value
+}
+
+// WithHeadWriter is a functional option that specify a HeadWriter.
+// This defaults to DefaultHeadWriter.
+func WithHeadWriter(value HeadWriter) OpenGraphOption {
+	return &withHeadWriter{value}
+}
+
+type openGraphASTTransformer struct {
+	OpenGraphConfig
+}
+
+// NewOpenGraphASTTransformer returns a new parser.ASTTransformer that
+// reads a FrontMatter from the document metadata.
+func NewOpenGraphASTTransformer(opts ...OpenGraphOption) parser.ASTTransformer {
+	t := &openGraphASTTransformer{
+		OpenGraphConfig: OpenGraphConfig{
+			HeadWriter: DefaultHeadWriter,
+		},
+	}
+	for _, o := range opts {
+		o.SetOpenGraphOption(&t.OpenGraphConfig)
+	}
+	return t
+}
+
+func frontMatterString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case fmt.Stringer:
+		return s.String()
+	}
+	return ""
+}
+
+// readFrontMatter reads a FrontMatter from the given metadata.
+// Top level values like ones stored by front matter extensions take
+// precedence over Obsidian properties and Pandoc title blocks.
+func readFrontMatter(meta map[string]interface{}) FrontMatter {
+	var f FrontMatter
+	sources := []map[string]interface{}{meta}
+	if properties, ok := meta[ObsidianPropertiesMetaKey].(map[string]interface{}); ok {
+		sources = append(sources, properties)
+	}
+	for _, source := range sources {
+		if len(f.Title) == 0 {
+			f.Title = frontMatterString(source["title"])
+		}
+		if len(f.Description) == 0 {
+			f.Description = frontMatterString(source["description"])
+		}
+		if len(f.Image) == 0 {
+			f.Image = frontMatterString(source["image"])
+		}
+	}
+	if pandoc, ok := meta[PandocMetaKey].(PandocMetadata); ok && len(f.Title) == 0 {
+		f.Title = pandoc.Title
+	}
+	return f
+}
+
+func (a *openGraphASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	meta := readFrontMatter(node.Meta())
+	if meta.IsEmpty() {
+		return
+	}
+	writer := a.HeadWriter
+	if writer == nil {
+		writer = DefaultHeadWriter
+	}
+	node.AddMeta(OpenGraphMetaKey, openGraphHead{meta: meta, writer: writer})
+}
+
+type openGraph struct {
+	options []OpenGraphOption
+}
+
+// OpenGraph is an extension that reads 'title', 'description' and 'image'
+// fields of front matters for Open Graph meta tags. Front matters are read
+// from the document metadata, Obsidian properties and Pandoc title blocks.
+// goldmark does not render HTML head elements, so call WriteOpenGraphHead
+// when you write a head element of the page.
+var OpenGraph = &openGraph{}
+
+// NewOpenGraph returns a new extension with given options.
+func NewOpenGraph(opts ...OpenGraphOption) goldmark.Extender {
+	return &openGraph{
+		options: opts,
+	}
+}
+
+func (e *openGraph) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewOpenGraphASTTransformer(e.options...), 999),
+	))
+}
@@ -0,0 +1,62 @@
+package extension
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestOpenGraph(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		{
+			"---\ntitle: Hello <World>\ndescription: A & B\nimage: /a.png\n---\n\ntext\n",
+			"<meta property=\"og:title\" content=\"Hello &lt;World&gt;\">\n" +
+				"<meta property=\"og:description\" content=\"A &amp; B\">\n" +
+				"<meta property=\"og:image\" content=\"/a.png\">\n",
+		},
+		{
+			"% Pandoc Title\n\ntext\n",
+			"<meta property=\"og:title\" content=\"Pandoc Title\">\n",
+		},
+		{
+			"text\n",
+			"",
+		},
+	}
+	markdown := goldmark.New(goldmark.WithExtensions(ObsidianProperties, PandocMeta, OpenGraph))
+	for _, c := range cases {
+		doc := markdown.Parser().Parse(text.NewReader([]byte(c.source)))
+		var b bytes.Buffer
+		ok := WriteOpenGraphHead(&b, doc)
+		if ok != (len(c.expected) != 0) {
+			t.Errorf("%q: unexpected result: %v", c.source, ok)
+		}
+		if b.String() != c.expected {
+			t.Errorf("%q: expected %q, but got %q", c.source, c.expected, b.String())
+		}
+	}
+}
+
+func TestOpenGraphHeadWriter(t *testing.T) {
+	markdown := goldmark.New(goldmark.WithExtensions(
+		ObsidianProperties,
+		NewOpenGraph(WithHeadWriter(func(w io.Writer, meta FrontMatter) {
+			_, _ = io.WriteString(w, "<title>"+meta.Title+"</title>")
+		})),
+	))
+	doc := markdown.Parser().Parse(text.NewReader([]byte("---\ntitle: Page\n---\n")))
+	if meta, ok := GetFrontMatter(doc); !ok || meta.Title != "Page" {
+		t.Errorf("unexpected front matter: %v, %v", meta, ok)
+	}
+	var b bytes.Buffer
+	WriteOpenGraphHead(&b, doc)
+	if b.String() != "<title>Page</title>" {
+		t.Errorf("unexpected head: %q", b.String())
+	}
+}
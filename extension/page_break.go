@@ -0,0 +1,113 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var pageBreakMarkers = [][]byte{
+	[]byte("---pagebreak---"),
+	[]byte(`\pagebreak`),
+}
+
+type pageBreakParser struct {
+}
+
+var defaultPageBreakParser = &pageBreakParser{}
+
+// NewPageBreakParser returns a new parser.BlockParser that can parse
+// page breaks like '---pagebreak---' and '\pagebreak' .
+func NewPageBreakParser() parser.BlockParser {
+	return defaultPageBreakParser
+}
+
+func (b *pageBreakParser) Trigger() []byte {
+	return []byte{'-', '\\'}
+}
+
+func (b *pageBreakParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	w, pos := util.IndentWidth(line, reader.LineOffset())
+	if w > 3 {
+		return nil, parser.NoChildren
+	}
+	marker := util.TrimRightSpace(line[pos:])
+	for _, m := range pageBreakMarkers {
+		if bytes.Equal(marker, m) {
+			reader.Advance(segment.Len() - 1)
+			return ast.NewPageBreak(), parser.NoChildren
+		}
+	}
+	return nil, parser.NoChildren
+}
+
+func (b *pageBreakParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	return parser.Close
+}
+
+func (b *pageBreakParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	// nothing to do
+}
+
+func (b *pageBreakParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *pageBreakParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// PageBreakHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders PageBreak nodes.
+type PageBreakHTMLRenderer struct {
+	html.Config
+}
+
+// NewPageBreakHTMLRenderer returns a new PageBreakHTMLRenderer.
+func NewPageBreakHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &PageBreakHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *PageBreakHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindPageBreak, r.renderPageBreak)
+}
+
+func (r *PageBreakHTMLRenderer) renderPageBreak(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<div class="page-break" style="page-break-after:always"></div>` + "\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+type pageBreak struct {
+}
+
+// PageBreak is an extension that allow you to use page break hints for
+// printed outputs like PDF. A line that consists of '---pagebreak---' or
+// '\pagebreak' is rendered as '<div class="page-break" style="page-break-after:always"></div>' .
+var PageBreak = &pageBreak{}
+
+func (e *pageBreak) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithBlockParsers(
+		util.Prioritized(NewPageBreakParser(), 150),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewPageBreakHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,17 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestPageBreak(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			PageBreak,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/page_break.txt", t, testutil.ParseCliCaseArg()...)
+}
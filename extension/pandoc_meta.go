@@ -0,0 +1,116 @@
+package extension
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// PandocMetaKey is a key of the document metadata that holds
+// a PandocMetadata.
+const PandocMetaKey = "PandocMeta"
+
+// A PandocMetadata struct holds values of a Pandoc title block like
+// '% Title', '% Author' and '% Date'.
+type PandocMetadata struct {
+	// Title is a title of the document.
+	Title string
+
+	// Authors are authors of the document. Authors are separated by ';'
+	// or continuation lines in the title block.
+	Authors []string
+
+	// Date is a date of the document as written in the title block.
+	Date string
+}
+
+// GetPandocMeta returns a PandocMetadata of the document that owns
+// the given node. GetPandocMeta returns false if the document has no
+// title block.
+func GetPandocMeta(node gast.Node) (PandocMetadata, bool) {
+	doc := node.OwnerDocument()
+	if doc == nil {
+		return PandocMetadata{}, false
+	}
+	meta, ok := doc.Meta()[PandocMetaKey].(PandocMetadata)
+	return meta, ok
+}
+
+type pandocMetaASTTransformer struct {
+}
+
+var defaultPandocMetaASTTransformer = &pandocMetaASTTransformer{}
+
+// NewPandocMetaASTTransformer returns a new parser.ASTTransformer that
+// extracts a Pandoc title block at the start of the document into
+// the document metadata and removes the title block from the document.
+func NewPandocMetaASTTransformer() parser.ASTTransformer {
+	return defaultPandocMetaASTTransformer
+}
+
+func (a *pandocMetaASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	paragraph, ok := node.FirstChild().(*gast.Paragraph)
+	if !ok || paragraph.Lines().Len() == 0 {
+		return
+	}
+	source := reader.Source()
+	if paragraph.Lines().At(0).Start != 0 {
+		return
+	}
+	var fields [][]string
+	for i := 0; i < paragraph.Lines().Len(); i++ {
+		segment := paragraph.Lines().At(i)
+		value := string(util.TrimRightSpace(segment.Value(source)))
+		indented := segment.Padding != 0 || (segment.Start > 0 && source[segment.Start-1] != '\n')
+		switch {
+		case !indented && strings.HasPrefix(value, "%"):
+			if len(fields) == 3 {
+				return
+			}
+			fields = append(fields, []string{strings.TrimSpace(value[1:])})
+		case indented && len(fields) != 0:
+			last := len(fields) - 1
+			fields[last] = append(fields[last], strings.TrimSpace(value))
+		default:
+			return
+		}
+	}
+	meta := PandocMetadata{}
+	meta.Title = strings.TrimSpace(strings.Join(fields[0], " "))
+	if len(fields) > 1 {
+		for _, line := range fields[1] {
+			for _, author := range strings.Split(line, ";") {
+				if author = strings.TrimSpace(author); len(author) != 0 {
+					meta.Authors = append(meta.Authors, author)
+				}
+			}
+		}
+	}
+	if len(fields) > 2 {
+		meta.Date = strings.TrimSpace(strings.Join(fields[2], " "))
+	}
+	node.AddMeta(PandocMetaKey, meta)
+	node.RemoveChild(node, paragraph)
+}
+
+type pandocMeta struct {
+}
+
+// PandocMeta is an extension that extracts a Pandoc title block like
+// '% Title', '% Author' and '% Date' at the start of the document.
+// The title block is removed from the document and stored as
+// the document metadata with the PandocMetaKey. Use GetPandocMeta to
+// get it.
+var PandocMeta = &pandocMeta{}
+
+func (e *pandocMeta) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(
+			util.Prioritized(NewPandocMetaASTTransformer(), 100),
+		),
+	)
+}
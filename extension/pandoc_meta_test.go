@@ -0,0 +1,67 @@
+package extension
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestPandocMeta(t *testing.T) {
+	cases := []struct {
+		source   string
+		meta     *PandocMetadata
+		expected string
+	}{
+		{
+			"% My Title\n% Author One; Author Two\n% 2024-01-02\n\n# Heading\n",
+			&PandocMetadata{Title: "My Title", Authors: []string{"Author One", "Author Two"}, Date: "2024-01-02"},
+			"<h1>Heading</h1>\n",
+		},
+		{
+			"% A long\n  title\n%\n% June 2024\n\ntext\n",
+			&PandocMetadata{Title: "A long title", Date: "June 2024"},
+			"<p>text</p>\n",
+		},
+		{
+			"% Title\n% Author One\n  Author Two\n",
+			&PandocMetadata{Title: "Title", Authors: []string{"Author One", "Author Two"}},
+			"",
+		},
+		{
+			"% Title\nnot a title block\n",
+			nil,
+			"<p>% Title\nnot a title block</p>\n",
+		},
+		{
+			"text\n\n% Title\n",
+			nil,
+			"<p>text</p>\n<p>% Title</p>\n",
+		},
+		{
+			"% 1\n% 2\n% 3\n% 4\n",
+			nil,
+			"<p>% 1\n% 2\n% 3\n% 4</p>\n",
+		},
+	}
+	markdown := goldmark.New(goldmark.WithExtensions(PandocMeta))
+	for i, c := range cases {
+		source := []byte(c.source)
+		doc := markdown.Parser().Parse(text.NewReader(source))
+		meta, ok := GetPandocMeta(doc)
+		if c.meta == nil && ok {
+			t.Errorf("case %d: unexpected metadata: %#v", i, meta)
+		} else if c.meta != nil && (!ok || !reflect.DeepEqual(meta, *c.meta)) {
+			t.Errorf("case %d: expected %#v, but got %#v", i, *c.meta, meta)
+		}
+		var b bytes.Buffer
+		if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != c.expected {
+			t.Errorf("case %d: expected %q, but got %q", i, c.expected, b.String())
+		}
+	}
+}
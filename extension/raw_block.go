@@ -0,0 +1,173 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// rawBlockFormat returns a format name of the info string like '{=html}'.
+// rawBlockFormat returns nil if the info string is not a raw attribute.
+func rawBlockFormat(info []byte) []byte {
+	if len(info) < 4 || info[0] != '{' || info[1] != '=' || info[len(info)-1] != '}' {
+		return nil
+	}
+	format := info[2 : len(info)-1]
+	for _, c := range format {
+		if !util.IsAlphaNumeric(c) && c != '-' && c != '_' {
+			return nil
+		}
+	}
+	return format
+}
+
+type rawBlockASTTransformer struct {
+}
+
+var defaultRawBlockASTTransformer = &rawBlockASTTransformer{}
+
+// NewRawBlockASTTransformer returns a new parser.ASTTransformer that
+// converts fenced code blocks with an info string like '{=html}' into
+// RawBlock nodes.
+func NewRawBlockASTTransformer() parser.ASTTransformer {
+	return defaultRawBlockASTTransformer
+}
+
+func (a *rawBlockASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var blocks []*gast.FencedCodeBlock
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		if n.Type() == gast.TypeInline {
+			return gast.WalkSkipChildren, nil
+		}
+		if fcb, ok := n.(*gast.FencedCodeBlock); ok && fcb.Info != nil {
+			if rawBlockFormat(util.TrimRightSpace(fcb.Info.Segment.Value(source))) != nil {
+				blocks = append(blocks, fcb)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, fcb := range blocks {
+		format := rawBlockFormat(util.TrimRightSpace(fcb.Info.Segment.Value(source)))
+		raw := ast.NewRawBlock(format)
+		raw.SetLines(fcb.Lines())
+		raw.SetBlankPreviousLines(fcb.HasBlankPreviousLines())
+		parent := fcb.Parent()
+		parent.ReplaceChild(parent, fcb, raw)
+	}
+}
+
+// RawBlockHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders RawBlock nodes. RawBlockHTMLRenderer writes contents of
+// RawBlock nodes those format is 'html' and omits others.
+type RawBlockHTMLRenderer struct {
+	html.Config
+}
+
+// NewRawBlockHTMLRenderer returns a new RawBlockHTMLRenderer.
+func NewRawBlockHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &RawBlockHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *RawBlockHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindRawBlock, r.renderRawBlock)
+}
+
+var rawBlockHTMLFormat = []byte("html")
+
+func (r *RawBlockHTMLRenderer) renderRawBlock(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*ast.RawBlock)
+	if !entering || !bytes.Equal(n.Format, rawBlockHTMLFormat) {
+		return gast.WalkContinue, nil
+	}
+	if !r.Unsafe {
+		if !r.SafeMode {
+			_, _ = w.WriteString("<!-- raw HTML omitted -->\n")
+		}
+		return gast.WalkContinue, nil
+	}
+	l := n.Lines().Len()
+	for i := 0; i < l; i++ {
+		line := n.Lines().At(i)
+		r.Writer.SecureWrite(w, line.Value(source))
+	}
+	return gast.WalkContinue, nil
+}
+
+// RawBlockRenderer is a renderer.NodeRenderer implementation that
+// renders RawBlock nodes for formats other than HTML. RawBlockRenderer
+// writes contents of RawBlock nodes those format is the given format as
+// is and omits others.
+type RawBlockRenderer struct {
+	format []byte
+}
+
+// NewRawBlockRenderer returns a new RawBlockRenderer for the given format
+// like 'latex'.
+func NewRawBlockRenderer(format string) renderer.NodeRenderer {
+	return &RawBlockRenderer{
+		format: []byte(format),
+	}
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *RawBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindRawBlock, r.renderRawBlock)
+}
+
+func (r *RawBlockRenderer) renderRawBlock(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*ast.RawBlock)
+	if !entering || !bytes.Equal(n.Format, r.format) {
+		return gast.WalkContinue, nil
+	}
+	l := n.Lines().Len()
+	for i := 0; i < l; i++ {
+		line := n.Lines().At(i)
+		_, _ = w.Write(line.Value(source))
+	}
+	return gast.WalkContinue, nil
+}
+
+type rawBlock struct {
+}
+
+// RawBlock is an extension that allow you to use Pandoc style raw blocks
+// like "```{=html}" . Contents of raw blocks are written as is only when
+// the format matches the renderer's output format given by
+// renderer.WithFormat. The format is decided when the extension extends
+// the Markdown.
+var RawBlock = &rawBlock{}
+
+func (e *rawBlock) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewRawBlockASTTransformer(), 100),
+	))
+	var r renderer.NodeRenderer
+	if format := renderer.FormatOf(m.Renderer()); format == string(rawBlockHTMLFormat) {
+		r = NewRawBlockHTMLRenderer()
+	} else {
+		r = NewRawBlockRenderer(format)
+	}
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(r, 500),
+	))
+}
@@ -0,0 +1,78 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestRawBlock(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			RawBlock,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/raw_block.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestRawBlockSafeMode(t *testing.T) {
+	source := "```{=html}\n<script>alert(1)</script>\n```\n\na"
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			RawBlock,
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "raw HTML blocks are omitted with comments by default",
+		Markdown:    source,
+		Expected:    "<!-- raw HTML omitted -->\n<p>a</p>",
+	}, t)
+	markdown = goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithSafeMode(),
+		),
+		goldmark.WithExtensions(
+			RawBlock,
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          2,
+		Description: "raw HTML blocks are removed without comments in the safe mode",
+		Markdown:    source,
+		Expected:    "<p>a</p>",
+	}, t)
+}
+
+func TestRawBlockFormat(t *testing.T) {
+	if format := renderer.FormatOf(goldmark.New().Renderer()); format != renderer.DefaultFormat {
+		t.Errorf("expected %s, but got %s", renderer.DefaultFormat, format)
+	}
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			renderer.WithFormat("latex"),
+		),
+		goldmark.WithExtensions(
+			RawBlock,
+		),
+	)
+	if format := renderer.FormatOf(markdown.Renderer()); format != "latex" {
+		t.Errorf("expected latex, but got %s", format)
+	}
+	// renderers those do not implement FormatProvider render HTML
+	if format := renderer.FormatOf(struct{ renderer.Renderer }{markdown.Renderer()}); format != renderer.DefaultFormat {
+		t.Errorf("expected %s, but got %s", renderer.DefaultFormat, format)
+	}
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "raw blocks of other formats are omitted",
+		Markdown:    "```{=html}\n<hr>\n```\n\n```{=latex}\n\\newpage\n```",
+		Expected:    "\\newpage",
+	}, t)
+}
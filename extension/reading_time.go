@@ -0,0 +1,158 @@
+package extension
+
+import (
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	// ReadingTimeMetaKey is a key of the document metadata that holds
+	// an estimated reading time in minutes.
+	ReadingTimeMetaKey = "ReadingTime"
+
+	// WordCountMetaKey is a key of the document metadata that holds
+	// a number of words in the document.
+	WordCountMetaKey = "WordCount"
+)
+
+// DefaultReadingTimeWPM is a default words per minute value of the
+// ReadingTime extension.
+const DefaultReadingTimeWPM = 200
+
+// WordCount returns a number of words in Text nodes under the given node.
+// Each east asian wide character is counted as a word.
+func WordCount(root gast.Node, source []byte) int {
+	count := 0
+	inWord := false
+	_ = gast.Walk(root, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if n.Type() != gast.TypeInline {
+			inWord = false
+			return gast.WalkContinue, nil
+		}
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		t, ok := n.(*gast.Text)
+		if !ok {
+			return gast.WalkContinue, nil
+		}
+		value := t.Segment.Value(source)
+		for i := 0; i < len(value); {
+			r, size := utf8.DecodeRune(value[i:])
+			i += size
+			if util.IsSpaceRune(r) {
+				inWord = false
+			} else if util.IsEastAsianWideRune(r) {
+				count++
+				inWord = false
+			} else if !inWord {
+				count++
+				inWord = true
+			}
+		}
+		if t.SoftLineBreak() || t.HardLineBreak() {
+			inWord = false
+		}
+		return gast.WalkContinue, nil
+	})
+	return count
+}
+
+// A ReadingTimeConfig struct is a data structure that holds configuration of the
+// ReadingTime extension.
+type ReadingTimeConfig struct {
+	// WPM is a number of words that can be read in a minute.
+	WPM int
+}
+
+const optReadingTimeWPM parser.OptionName = "ReadingTimeWPM"
+
+// SetOption implements SetOptioner.
+func (c *ReadingTimeConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optReadingTimeWPM:
+		c.WPM = value.(int)
+	}
+}
+
+// A ReadingTimeOption interface sets options for the ReadingTime extension.
+type ReadingTimeOption interface {
+	parser.Option
+	SetReadingTimeOption(*ReadingTimeConfig)
+}
+
+type withReadingTimeWPM struct {
+	value int
+}
+
+func (o *withReadingTimeWPM) SetParserOption(c *parser.Config) {
+	c.Options[optReadingTimeWPM] = o.value
+}
+
+func (o *withReadingTimeWPM) SetReadingTimeOption(c *ReadingTimeConfig) {
+	c.WPM = o.value
+}
+
+// WithReadingTimeWPM is a functional option that specify a number of
+// words that can be read in a minute. This defaults to 200.
+func WithReadingTimeWPM(value int) ReadingTimeOption {
+	return &withReadingTimeWPM{value}
+}
+
+type readingTimeASTTransformer struct {
+	ReadingTimeConfig
+}
+
+// NewReadingTimeASTTransformer returns a new parser.ASTTransformer that
+// estimates a reading time of the document and stores it as
+// the document metadata.
+func NewReadingTimeASTTransformer(opts ...ReadingTimeOption) parser.ASTTransformer {
+	t := &readingTimeASTTransformer{
+		ReadingTimeConfig: ReadingTimeConfig{
+			WPM: DefaultReadingTimeWPM,
+		},
+	}
+	for _, o := range opts {
+		o.SetReadingTimeOption(&t.ReadingTimeConfig)
+	}
+	return t
+}
+
+func (a *readingTimeASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	words := WordCount(node, reader.Source())
+	wpm := a.WPM
+	if wpm <= 0 {
+		wpm = DefaultReadingTimeWPM
+	}
+	node.AddMeta(WordCountMetaKey, words)
+	node.AddMeta(ReadingTimeMetaKey, (words+wpm-1)/wpm)
+}
+
+type readingTime struct {
+	options []ReadingTimeOption
+}
+
+// ReadingTime is an extension that estimates a reading time of the document.
+// An estimated reading time in minutes is stored as the document metadata
+// with the ReadingTimeMetaKey.
+var ReadingTime = &readingTime{}
+
+// NewReadingTime returns a new extension with given options.
+func NewReadingTime(opts ...ReadingTimeOption) goldmark.Extender {
+	return &readingTime{
+		options: opts,
+	}
+}
+
+func (e *readingTime) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(
+			util.Prioritized(NewReadingTimeASTTransformer(e.options...), 999),
+		),
+	)
+}
@@ -0,0 +1,55 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestWordCount(t *testing.T) {
+	cases := []struct {
+		source string
+		count  int
+	}{
+		{"", 0},
+		{"Hello world", 2},
+		{"Hello *wor*ld", 2},
+		{"Hello\nworld", 2},
+		{"# Title\n\nparagraph text", 3},
+		{"日本語です", 5},
+		{"Go言語", 3},
+	}
+	markdown := goldmark.New()
+	for _, c := range cases {
+		source := []byte(c.source)
+		doc := markdown.Parser().Parse(text.NewReader(source))
+		if got := WordCount(doc, source); got != c.count {
+			t.Errorf("%q: expected %d, but got %d", c.source, c.count, got)
+		}
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewReadingTime(WithReadingTimeWPM(2)),
+		),
+	)
+	source := []byte("one two three\n\nfour *five*")
+	doc := markdown.Parser().Parse(text.NewReader(source)).(*gast.Document)
+	meta := doc.Meta()
+	if meta[WordCountMetaKey] != 5 {
+		t.Errorf("expected word count 5, but got %v", meta[WordCountMetaKey])
+	}
+	if meta[ReadingTimeMetaKey] != 3 {
+		t.Errorf("expected reading time 3, but got %v", meta[ReadingTimeMetaKey])
+	}
+
+	markdown = goldmark.New(goldmark.WithExtensions(ReadingTime))
+	doc = markdown.Parser().Parse(text.NewReader([]byte(""))).(*gast.Document)
+	if doc.Meta()[ReadingTimeMetaKey] != 0 {
+		t.Errorf("expected reading time 0, but got %v", doc.Meta()[ReadingTimeMetaKey])
+	}
+}
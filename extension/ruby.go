@@ -0,0 +1,149 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type rubyParser struct {
+}
+
+var defaultRubyParser = &rubyParser{}
+
+// NewRubyParser returns a new InlineParser that parses
+// ruby annotations like '{漢字}(かんじ)'.
+func NewRubyParser() parser.InlineParser {
+	return defaultRubyParser
+}
+
+func (s *rubyParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+// findRubyClosure returns an index of the given closer in the line.
+// Backslash escaped characters are skipped. findRubyClosure returns -1
+// if the closer is not found or the opener appears before the closer.
+func findRubyClosure(line []byte, start int, opener, closer byte) int {
+	for i := start; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' && i < len(line)-1 && util.IsPunct(line[i+1]) {
+			i++
+			continue
+		}
+		if c == '\n' || c == opener {
+			return -1
+		}
+		if c == closer {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *rubyParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, segment := block.PeekLine()
+	baseStop := findRubyClosure(line, 1, '{', '}')
+	if baseStop < 0 || util.IsBlank(line[1:baseStop]) {
+		return nil
+	}
+	textStart := baseStop + 2
+	if textStart > len(line) || line[baseStop+1] != '(' {
+		return nil
+	}
+	textStop := findRubyClosure(line, textStart, '(', ')')
+	if textStop < 0 || util.IsBlank(line[textStart:textStop]) {
+		return nil
+	}
+
+	node := ast.NewRuby()
+	base := ast.NewRubyBase()
+	base.AppendChild(base, gast.NewTextSegment(
+		text.NewSegment(segment.Start+1, segment.Start+baseStop)))
+	node.AppendChild(node, base)
+	rt := ast.NewRubyText()
+	rt.AppendChild(rt, gast.NewTextSegment(
+		text.NewSegment(segment.Start+textStart, segment.Start+textStop)))
+	node.AppendChild(node, rt)
+	block.Advance(textStop + 1)
+	return node
+}
+
+// RubyHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Ruby nodes.
+type RubyHTMLRenderer struct {
+	html.Config
+}
+
+// NewRubyHTMLRenderer returns a new RubyHTMLRenderer.
+func NewRubyHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &RubyHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *RubyHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindRuby, r.renderRuby)
+	reg.Register(ast.KindRubyBase, r.renderRubyBase)
+	reg.Register(ast.KindRubyText, r.renderRubyText)
+}
+
+// RubyAttributeFilter defines attribute names which ruby elements can have.
+var RubyAttributeFilter = html.GlobalAttributeFilter
+
+func (r *RubyHTMLRenderer) renderRuby(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		if n.Attributes() != nil {
+			_, _ = w.WriteString("<ruby")
+			html.RenderAttributes(w, n, RubyAttributeFilter)
+			_ = w.WriteByte('>')
+		} else {
+			_, _ = w.WriteString("<ruby>")
+		}
+	} else {
+		_, _ = w.WriteString("</ruby>")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *RubyHTMLRenderer) renderRubyBase(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	// nothing to do
+	return gast.WalkContinue, nil
+}
+
+func (r *RubyHTMLRenderer) renderRubyText(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<rt>")
+	} else {
+		_, _ = w.WriteString("</rt>")
+	}
+	return gast.WalkContinue, nil
+}
+
+type ruby struct {
+}
+
+// Ruby is an extension that allow you to use ruby annotations like '{漢字}(かんじ)' .
+var Ruby = &ruby{}
+
+func (e *ruby) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewRubyParser(), 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewRubyHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,21 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestRuby(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			Ruby,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/ruby.txt", t, testutil.ParseCliCaseArg()...)
+}
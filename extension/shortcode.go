@@ -0,0 +1,150 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type shortcodeParser struct {
+}
+
+var defaultShortcodeParser = &shortcodeParser{}
+
+// NewShortcodeParser returns a new parser.InlineParser that can parse
+// Hugo shortcodes like '{{< name >}}' and '{{% name %}}'.
+func NewShortcodeParser() parser.InlineParser {
+	return defaultShortcodeParser
+}
+
+func (s *shortcodeParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+var (
+	shortcodeCloserAngle   = []byte(">}}")
+	shortcodeCloserPercent = []byte("%}}")
+)
+
+func (s *shortcodeParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, _ := block.PeekLine()
+	if len(line) < 3 || line[1] != '{' || (line[2] != '<' && line[2] != '%') {
+		return nil
+	}
+	isMarkdown := line[2] == '%'
+	closer := shortcodeCloserAngle
+	if isMarkdown {
+		closer = shortcodeCloserPercent
+	}
+	name := shortcodeName(line[3:])
+	if name == nil {
+		return nil
+	}
+	l, pos := block.Position()
+	node := ast.NewShortcode(name, isMarkdown)
+	offset := 3
+	for {
+		line, segment := block.PeekLine()
+		if line == nil {
+			block.SetPosition(l, pos)
+			return nil
+		}
+		if i := bytes.Index(line[offset:], closer); i > -1 {
+			stop := offset + i + len(closer)
+			node.Segments.Append(segment.WithStop(segment.Start + stop))
+			block.Advance(stop)
+			return node
+		}
+		node.Segments.Append(segment)
+		block.AdvanceLine()
+		offset = 0
+	}
+}
+
+// shortcodeName returns a name of the shortcode that follows an opener.
+// A Hugo comment like '{{</* name */>}}' is also a shortcode.
+func shortcodeName(line []byte) []byte {
+	line = util.TrimLeftSpace(line)
+	if len(line) > 1 && line[0] == '/' && line[1] == '*' {
+		line = util.TrimLeftSpace(line[2:])
+	}
+	i := 0
+	if i < len(line) && line[i] == '/' {
+		i++
+	}
+	start := i
+	for ; i < len(line); i++ {
+		c := line[i]
+		if !util.IsAlphaNumeric(c) && c != '-' && c != '_' && c != '.' && c != '/' {
+			break
+		}
+	}
+	if i == start {
+		return nil
+	}
+	return line[:i]
+}
+
+// ShortcodeHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Shortcode nodes. Shortcodes are written as is so that Hugo can
+// process them after the conversion. Shortcodes are escaped in the safe
+// mode.
+type ShortcodeHTMLRenderer struct {
+	html.Config
+}
+
+// NewShortcodeHTMLRenderer returns a new ShortcodeHTMLRenderer.
+func NewShortcodeHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &ShortcodeHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *ShortcodeHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindShortcode, r.renderShortcode)
+}
+
+func (r *ShortcodeHTMLRenderer) renderShortcode(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkSkipChildren, nil
+	}
+	n := node.(*ast.Shortcode)
+	for i := 0; i < n.Segments.Len(); i++ {
+		segment := n.Segments.At(i)
+		if r.SafeMode {
+			_, _ = w.Write(util.EscapeHTML(segment.Value(source)))
+		} else {
+			_, _ = w.Write(segment.Value(source))
+		}
+	}
+	return gast.WalkSkipChildren, nil
+}
+
+type shortcode struct {
+}
+
+// Shortcode is an extension that passes Hugo shortcodes like
+// '{{< name >}}' and '{{% name %}}' through to the output as is.
+var Shortcode = &shortcode{}
+
+func (e *shortcode) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewShortcodeParser(), 100),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewShortcodeHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,37 @@
+package extension
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestShortcode(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Shortcode,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/shortcode.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestShortcodeSafeMode(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithSafeMode(),
+		),
+		goldmark.WithExtensions(
+			Shortcode,
+		),
+	)
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte(`{{< ref "<script>" >}}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<p>{{&lt; ref &quot;&lt;script&gt;&quot; &gt;}}</p>\n"; b.String() != expected {
+		t.Errorf("shortcodes should be escaped in the safe mode: %q", b.String())
+	}
+}
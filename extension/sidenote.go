@@ -0,0 +1,242 @@
+package extension
+
+import (
+	"strconv"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// SidenoteMode is a layout of sidenotes.
+type SidenoteMode int
+
+const (
+	// SidenoteInline renders notes just after their references so that
+	// they can be placed in the margin by SidenoteStylesheet.
+	SidenoteInline SidenoteMode = iota
+
+	// SidenoteBottom renders notes at the bottom of the document like
+	// the Footnote extension.
+	SidenoteBottom
+)
+
+// SidenoteStylesheet is a stylesheet that places sidenotes in the right
+// margin of the text.
+const SidenoteStylesheet = ".sidenote-ref{vertical-align:super;font-size:smaller}" +
+	".sidenote{float:right;clear:right;width:40%;margin-right:-45%;font-size:smaller}"
+
+// A SidenoteConfig struct is a data structure that holds configuration of the
+// Sidenote extension.
+type SidenoteConfig struct {
+	// Mode is a layout of sidenotes.
+	Mode SidenoteMode
+}
+
+const optSidenoteMode parser.OptionName = "SidenoteMode"
+
+// SetOption implements SetOptioner.
+func (c *SidenoteConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optSidenoteMode:
+		c.Mode = value.(SidenoteMode)
+	}
+}
+
+// A SidenoteOption interface sets options for the Sidenote extension.
+type SidenoteOption interface {
+	parser.Option
+	SetSidenoteOption(*SidenoteConfig)
+}
+
+type withSidenoteMode struct {
+	value SidenoteMode
+}
+
+func (o *withSidenoteMode) SetParserOption(c *parser.Config) {
+	c.Options[optSidenoteMode] = o.value
+}
+
+func (o *withSidenoteMode) SetSidenoteOption(c *SidenoteConfig) {
+	c.Mode = o.value
+}
+
+// WithSidenoteMode is a functional option that specify a layout of
+// sidenotes. This defaults to SidenoteInline.
+func WithSidenoteMode(mode SidenoteMode) SidenoteOption {
+	return &withSidenoteMode{mode}
+}
+
+type sidenoteASTTransformer struct {
+	SidenoteConfig
+}
+
+// NewSidenoteASTTransformer returns a new parser.ASTTransformer that
+// moves footnotes to Sidenote nodes after their first references.
+// This transformer must run after the footnote transformer.
+func NewSidenoteASTTransformer(opts ...SidenoteOption) parser.ASTTransformer {
+	t := &sidenoteASTTransformer{}
+	for _, o := range opts {
+		o.SetSidenoteOption(&t.SidenoteConfig)
+	}
+	return t
+}
+
+// isInlineFootnote returns true if the given footnote consists of
+// paragraphs. Other blocks like lists can not be written in sidenotes.
+func isInlineFootnote(fn *ast.Footnote) bool {
+	if !fn.HasChildren() {
+		return false
+	}
+	for c := fn.FirstChild(); c != nil; c = c.NextSibling() {
+		if !gast.IsParagraph(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *sidenoteASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	if a.Mode != SidenoteInline {
+		return
+	}
+	list, ok := node.LastChild().(*ast.FootnoteList)
+	if !ok {
+		return
+	}
+	footnotes := map[int]*ast.Footnote{}
+	for c := list.FirstChild(); c != nil; c = c.NextSibling() {
+		if fn := c.(*ast.Footnote); isInlineFootnote(fn) {
+			footnotes[fn.Index] = fn
+		}
+	}
+	var links []*ast.FootnoteLink
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		if n.Kind() == ast.KindFootnoteList {
+			return gast.WalkSkipChildren, nil
+		}
+		if link, ok := n.(*ast.FootnoteLink); ok && footnotes[link.Index] != nil {
+			links = append(links, link)
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, link := range links {
+		parent := link.Parent()
+		ref := ast.NewSidenoteRef(link.Index)
+		parent.ReplaceChild(parent, link, ref)
+		if link.RefIndex != 0 {
+			continue
+		}
+		fn := footnotes[link.Index]
+		sidenote := ast.NewSidenote(link.Index)
+		for p := fn.FirstChild(); p != nil; p = p.NextSibling() {
+			for c := p.FirstChild(); c != nil; {
+				next := c.NextSibling()
+				if c.Kind() != ast.KindFootnoteBacklink {
+					sidenote.AppendChild(sidenote, c)
+				}
+				c = next
+			}
+			if p.NextSibling() == nil {
+				continue
+			}
+			// paragraphs are separated by line breaks
+			if t, ok := sidenote.LastChild().(*gast.Text); ok {
+				t.SetSoftLineBreak(true)
+			} else {
+				sidenote.AppendChild(sidenote, gast.NewString([]byte("\n")))
+			}
+		}
+		parent.InsertAfter(parent, ref, sidenote)
+		list.RemoveChild(list, fn)
+	}
+	if !list.HasChildren() {
+		node.RemoveChild(node, list)
+		return
+	}
+	// footnotes those remain at the bottom keep their numbers
+	for c := list.FirstChild(); c != nil; c = c.NextSibling() {
+		c.SetAttributeString("value", []byte(strconv.Itoa(c.(*ast.Footnote).Index)))
+	}
+}
+
+// SidenoteHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders SidenoteRef and Sidenote nodes.
+type SidenoteHTMLRenderer struct {
+	html.Config
+}
+
+// NewSidenoteHTMLRenderer returns a new SidenoteHTMLRenderer.
+func NewSidenoteHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &SidenoteHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *SidenoteHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindSidenoteRef, r.renderSidenoteRef)
+	reg.Register(ast.KindSidenote, r.renderSidenote)
+}
+
+func (r *SidenoteHTMLRenderer) renderSidenoteRef(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<span class="sidenote-ref">`)
+		_, _ = w.WriteString(strconv.Itoa(node.(*ast.SidenoteRef).Index))
+		_, _ = w.WriteString("</span>")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *SidenoteHTMLRenderer) renderSidenote(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<span class="sidenote" data-index="`)
+		_, _ = w.WriteString(strconv.Itoa(node.(*ast.Sidenote).Index))
+		_, _ = w.WriteString(`">`)
+	} else {
+		_, _ = w.WriteString("</span>")
+	}
+	return gast.WalkContinue, nil
+}
+
+type sidenote struct {
+	options []SidenoteOption
+}
+
+// Sidenote is an extension that renders footnotes like '[^label]' as
+// sidenotes those are placed beside the text. Footnotes that have blocks
+// other than paragraphs remain at the bottom of the document.
+// This extension includes the Footnote extension, so they should not be
+// used together.
+var Sidenote = &sidenote{}
+
+// NewSidenote returns a new extension with given options.
+func NewSidenote(opts ...SidenoteOption) goldmark.Extender {
+	return &sidenote{
+		options: opts,
+	}
+}
+
+func (e *sidenote) Extend(m goldmark.Markdown) {
+	Footnote.Extend(m)
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewSidenoteASTTransformer(e.options...), 1000),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewSidenoteHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,38 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestSidenote(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Sidenote,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/sidenote.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestSidenoteBottom(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewSidenote(WithSidenoteMode(SidenoteBottom)),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:       1,
+		Markdown: "Text[^1].\n\n[^1]: Note.",
+		Expected: `<p>Text<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup>.</p>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="fn:1">
+<p>Note.&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+	}, t)
+}
@@ -0,0 +1,267 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type spoilerDelimiterProcessor struct {
+}
+
+func (p *spoilerDelimiterProcessor) IsDelimiter(b byte) bool {
+	return b == '|'
+}
+
+func (p *spoilerDelimiterProcessor) CanOpenCloser(opener, closer *parser.Delimiter) bool {
+	return opener.Char == closer.Char
+}
+
+func (p *spoilerDelimiterProcessor) OnMatch(consumes int) gast.Node {
+	return ast.NewSpoiler()
+}
+
+var defaultSpoilerDelimiterProcessor = &spoilerDelimiterProcessor{}
+
+type spoilerParser struct {
+}
+
+var defaultSpoilerParser = &spoilerParser{}
+
+// NewSpoilerParser return a new InlineParser that parses
+// spoiler expressions like '||text||'.
+func NewSpoilerParser() parser.InlineParser {
+	return defaultSpoilerParser
+}
+
+func (s *spoilerParser) Trigger() []byte {
+	return []byte{'|'}
+}
+
+func (s *spoilerParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	before := block.PrecendingCharacter()
+	if before == '|' {
+		return nil
+	}
+	line, segment := block.PeekLine()
+	node := parser.ScanDelimiter(line, before, 2, defaultSpoilerDelimiterProcessor)
+	if node == nil || node.OriginalLength != 2 {
+		return nil
+	}
+	node.Segment = segment.WithStop(segment.Start + node.OriginalLength)
+	block.Advance(node.OriginalLength)
+	pc.PushDelimiter(node)
+	return node
+}
+
+func (s *spoilerParser) CloseBlock(parent gast.Node, pc parser.Context) {
+	// nothing to do
+}
+
+type spoilerASTTransformer struct {
+}
+
+var defaultSpoilerASTTransformer = &spoilerASTTransformer{}
+
+// NewSpoilerASTTransformer returns a new parser.ASTTransformer that
+// appends a SpoilerScript node to documents those have spoiler texts.
+func NewSpoilerASTTransformer() parser.ASTTransformer {
+	return defaultSpoilerASTTransformer
+}
+
+func (a *spoilerASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	found := false
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if entering && n.Kind() == ast.KindSpoiler {
+			found = true
+			return gast.WalkStop, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	if found {
+		node.AppendChild(node, ast.NewSpoilerScript())
+	}
+}
+
+// SpoilerRevealMethod is a method that reveals spoiler texts in browsers.
+type SpoilerRevealMethod int
+
+const (
+	// SpoilerRevealCSS reveals spoiler texts when they are hovered or
+	// focused. This method does not require JavaScript.
+	SpoilerRevealCSS SpoilerRevealMethod = iota
+
+	// SpoilerRevealJavaScript reveals spoiler texts when they are clicked.
+	// Spoiler texts are rendered with 'data-spoiler' attributes and
+	// SpoilerScript is appended to documents those have spoiler texts.
+	// The script has a nonce given by html.WithCSPNonce and is not rendered
+	// in the safe mode, so spoiler texts are revealed only by
+	// SpoilerStylesheet in the safe mode.
+	SpoilerRevealJavaScript
+)
+
+// SpoilerScript is a script that adds the 'revealed' class to spoiler
+// texts those are clicked or activated by keyboard.
+const SpoilerScript = `(function(){
+function reveal(e){
+if(e.type==='keydown'&&e.key!=='Enter'&&e.key!==' ')return;
+var s=e.target.closest&&e.target.closest('[data-spoiler]');
+if(s)s.classList.add('revealed');
+}
+document.addEventListener('click',reveal);
+document.addEventListener('keydown',reveal);
+})();
+`
+
+// SpoilerStylesheet returns a stylesheet that hides spoiler texts and
+// reveals them by the given method.
+func SpoilerStylesheet(method SpoilerRevealMethod) string {
+	css := ".spoiler{color:transparent;background:black}"
+	if method == SpoilerRevealJavaScript {
+		// spoiler texts rendered in the safe mode do not have data-spoiler
+		return css + ".spoiler.revealed,.spoiler:not([data-spoiler]):hover,.spoiler:not([data-spoiler]):focus" +
+			"{color:inherit;background:transparent}"
+	}
+	return css + ".spoiler:hover,.spoiler:focus{color:inherit;background:transparent}"
+}
+
+// A SpoilerConfig struct has configurations for the HTML based renderers.
+type SpoilerConfig struct {
+	html.Config
+
+	// RevealMethod is a method that reveals spoiler texts.
+	RevealMethod SpoilerRevealMethod
+}
+
+// SpoilerOption interface is a functional option interface for the extension.
+type SpoilerOption interface {
+	renderer.Option
+	// SetSpoilerOption sets given option to the extension.
+	SetSpoilerOption(*SpoilerConfig)
+}
+
+// NewSpoilerConfig returns a new Config with defaults.
+func NewSpoilerConfig() SpoilerConfig {
+	return SpoilerConfig{
+		Config:       html.NewConfig(),
+		RevealMethod: SpoilerRevealCSS,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *SpoilerConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optSpoilerRevealMethod:
+		c.RevealMethod = value.(SpoilerRevealMethod)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+const optSpoilerRevealMethod renderer.OptionName = "SpoilerRevealMethod"
+
+type withSpoilerRevealMethod struct {
+	value SpoilerRevealMethod
+}
+
+func (o *withSpoilerRevealMethod) SetConfig(c *renderer.Config) {
+	c.Options[optSpoilerRevealMethod] = o.value
+}
+
+func (o *withSpoilerRevealMethod) SetSpoilerOption(c *SpoilerConfig) {
+	c.RevealMethod = o.value
+}
+
+// WithSpoilerRevealMethod is a functional option that specify a method
+// that reveals spoiler texts. This defaults to SpoilerRevealCSS.
+// Spoiler texts are hidden by SpoilerStylesheet.
+func WithSpoilerRevealMethod(method SpoilerRevealMethod) SpoilerOption {
+	return &withSpoilerRevealMethod{method}
+}
+
+// SpoilerHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Spoiler nodes.
+type SpoilerHTMLRenderer struct {
+	SpoilerConfig
+}
+
+// NewSpoilerHTMLRenderer returns a new SpoilerHTMLRenderer.
+func NewSpoilerHTMLRenderer(opts ...SpoilerOption) renderer.NodeRenderer {
+	r := &SpoilerHTMLRenderer{
+		SpoilerConfig: NewSpoilerConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetSpoilerOption(&r.SpoilerConfig)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *SpoilerHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindSpoiler, r.renderSpoiler)
+	reg.Register(ast.KindSpoilerScript, r.renderSpoilerScript)
+}
+
+func (r *SpoilerHTMLRenderer) renderSpoiler(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</span>")
+		return gast.WalkContinue, nil
+	}
+	if r.RevealMethod == SpoilerRevealJavaScript && !r.SafeMode {
+		_, _ = w.WriteString(`<span class="spoiler" aria-label="Spoiler" role="button" tabindex="0" data-spoiler="">`)
+	} else {
+		_, _ = w.WriteString(`<span class="spoiler" aria-label="Spoiler" tabindex="0">`)
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *SpoilerHTMLRenderer) renderSpoilerScript(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	// scripts are not rendered in the safe mode
+	if !entering || r.RevealMethod != SpoilerRevealJavaScript || r.SafeMode {
+		return gast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("<script")
+	r.RenderCSPNonce(w, node)
+	_, _ = w.WriteString(">\n")
+	_, _ = w.WriteString(SpoilerScript)
+	_, _ = w.WriteString("</script>\n")
+	return gast.WalkContinue, nil
+}
+
+type spoiler struct {
+	options []SpoilerOption
+}
+
+// Spoiler is an extension that allow you to use spoiler texts like '||text||' .
+// Spoiler texts are rendered as '<span class="spoiler">' and are hidden by
+// SpoilerStylesheet. In table rows, '|' separates columns, so spoiler texts
+// can not be used in table cells.
+var Spoiler = &spoiler{}
+
+// NewSpoiler returns a new extension with given options.
+func NewSpoiler(opts ...SpoilerOption) goldmark.Extender {
+	return &spoiler{
+		options: opts,
+	}
+}
+
+func (e *spoiler) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithInlineParsers(
+			util.Prioritized(NewSpoilerParser(), 500),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(NewSpoilerASTTransformer(), 1000),
+		),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewSpoilerHTMLRenderer(e.options...), 500),
+	))
+}
@@ -0,0 +1,108 @@
+package extension
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestSpoiler(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Spoiler,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/spoiler.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestSpoilerRevealMethod(t *testing.T) {
+	span := `<span class="spoiler" aria-label="Spoiler" role="button" tabindex="0" data-spoiler="">`
+	cases := []struct {
+		option   renderer.Option
+		expected string
+	}{
+		{html.WithCSPNonce("abc"),
+			"<p>" + span + "secret</span></p>\n<script nonce=\"abc\">\n" + SpoilerScript + "</script>\n"},
+		{html.WithSafeMode(),
+			"<p><span class=\"spoiler\" aria-label=\"Spoiler\" tabindex=\"0\">secret</span></p>\n"},
+	}
+	for i, c := range cases {
+		markdown := goldmark.New(
+			goldmark.WithRendererOptions(c.option),
+			goldmark.WithExtensions(
+				NewSpoiler(WithSpoilerRevealMethod(SpoilerRevealJavaScript)),
+			),
+		)
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte("||secret||"), &b); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != c.expected {
+			t.Errorf("%d: %s", i, testutil.DiffPretty([]byte(c.expected), b.Bytes()))
+		}
+	}
+
+	// documents without spoiler texts do not have the script
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewSpoiler(WithSpoilerRevealMethod(SpoilerRevealJavaScript)),
+		),
+	)
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte("text"), &b); err != nil || b.String() != "<p>text</p>\n" {
+		t.Errorf("unexpected result: %q, %v", b.String(), err)
+	}
+}
+
+func TestSpoilerTable(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Spoiler,
+			Table,
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "table separators take precedence",
+		Markdown:    "| a | b | c |\n|---|---|---|\n| x || y |\n\n||secret||",
+		Expected: `<table>
+<thead>
+<tr>
+<th>a</th>
+<th>b</th>
+<th>c</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>x</td>
+<td></td>
+<td>y</td>
+</tr>
+</tbody>
+</table>
+<p><span class="spoiler" aria-label="Spoiler" tabindex="0">secret</span></p>`,
+	}, t)
+}
+
+func TestDiscord(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Discord,
+		),
+	)
+	var b bytes.Buffer
+	source := []byte("# Heading\n\n||secret|| ~~old~~ https://discord.com\n")
+	if err := markdown.Convert(source, &b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "<p>Heading</p>\n<p><span class=\"spoiler\" aria-label=\"Spoiler\" tabindex=\"0\">secret</span> <del>old</del> " +
+		"<a href=\"https://discord.com\">https://discord.com</a></p>\n"
+	if b.String() != expected {
+		t.Errorf("%s", testutil.DiffPretty([]byte(expected), b.Bytes()))
+	}
+}
@@ -424,6 +424,10 @@ var TableRowAttributeFilter = html.GlobalAttributeFilter.Extend(
 func (r *TableHTMLRenderer) renderTableRow(
 	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
 	if entering {
+		if n.PreviousSibling() == nil {
+			// tables those have no headers
+			_, _ = w.WriteString("<tbody>\n")
+		}
 		_, _ = w.WriteString("<tr")
 		if n.Attributes() != nil {
 			html.RenderAttributes(w, n, TableRowAttributeFilter)
@@ -0,0 +1,387 @@
+package extension
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A TableSortType is a type of values in a table column.
+type TableSortType string
+
+const (
+	// TableSortNumeric indicates values are numbers like '1,234.5' .
+	TableSortNumeric TableSortType = "numeric"
+
+	// TableSortDate indicates values are dates like '2006-01-02' .
+	TableSortDate TableSortType = "date"
+
+	// TableSortString indicates values are compared as strings.
+	TableSortString TableSortType = "string"
+)
+
+// DefaultTableSortSampleRows is a default number of rows those are sampled
+// to detect types of columns.
+const DefaultTableSortSampleRows = 5
+
+// DefaultTableSortScript is a default script that sorts rows of sortable
+// tables when their headers are clicked.
+const DefaultTableSortScript = `(function(){
+document.querySelectorAll('table[data-sortable]').forEach(function(t){
+var hs=t.querySelectorAll('thead th');
+hs.forEach(function(th,i){
+th.style.cursor='pointer';
+th.addEventListener('click',function(){
+var b=t.tBodies[0];
+if(!b)return;
+var type=th.getAttribute('data-sort-type');
+var asc=th.getAttribute('aria-sort')!=='ascending';
+var v=function(r){return r.cells[i]?r.cells[i].textContent.trim():'';};
+var rows=Array.prototype.slice.call(b.rows);
+rows.sort(function(x,y){
+var a=v(x),c=v(y),d;
+if(type==='numeric'){d=parseFloat(a.replace(/,/g,''))-parseFloat(c.replace(/,/g,''));}
+else if(type==='date'){d=Date.parse(a)-Date.parse(c);}
+else{d=a.localeCompare(c);}
+if(isNaN(d))d=0;
+return asc?d:-d;
+});
+rows.forEach(function(r){b.appendChild(r);});
+hs.forEach(function(h){h.removeAttribute('aria-sort');});
+th.setAttribute('aria-sort',asc?'ascending':'descending');
+});
+});
+});
+})();
+`
+
+var tableSortDateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// detectTableSortType returns a type of the given values.
+// Empty values are ignored.
+func detectTableSortType(values []string) TableSortType {
+	numeric, date, found := true, true, false
+	for _, value := range values {
+		if len(value) == 0 {
+			continue
+		}
+		found = true
+		if _, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64); err != nil {
+			numeric = false
+		}
+		if date {
+			parsed := false
+			for _, layout := range tableSortDateLayouts {
+				if _, err := time.Parse(layout, value); err == nil {
+					parsed = true
+					break
+				}
+			}
+			date = parsed
+		}
+	}
+	switch {
+	case !found:
+		return TableSortString
+	case numeric:
+		return TableSortNumeric
+	case date:
+		return TableSortDate
+	}
+	return TableSortString
+}
+
+// A TableSortConfig struct is a data structure that holds configuration of the
+// TableSort extension.
+type TableSortConfig struct {
+	// All indicates that all tables are sortable.
+	All bool
+
+	// SampleRows is a number of rows those are sampled to detect types of
+	// columns.
+	SampleRows int
+
+	// Script is a script appended to documents those have sortable tables.
+	// If Script is empty, no scripts are appended.
+	Script string
+}
+
+const optTableSortAll parser.OptionName = "TableSortAll"
+
+const optTableSortSampleRows parser.OptionName = "TableSortSampleRows"
+
+const optTableSortScript parser.OptionName = "TableSortScript"
+
+// SetOption implements SetOptioner.
+func (c *TableSortConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optTableSortAll:
+		c.All = value.(bool)
+	case optTableSortSampleRows:
+		c.SampleRows = value.(int)
+	case optTableSortScript:
+		c.Script = value.(string)
+	}
+}
+
+// A TableSortOption interface sets options for the TableSort extension.
+type TableSortOption interface {
+	parser.Option
+	SetTableSortOption(*TableSortConfig)
+}
+
+type withTableSortAll struct {
+	value bool
+}
+
+func (o *withTableSortAll) SetParserOption(c *parser.Config) {
+	c.Options[optTableSortAll] = o.value
+}
+
+func (o *withTableSortAll) SetTableSortOption(c *TableSortConfig) {
+	c.All = o.value
+}
+
+// WithTableSortAll is a functional option that makes all tables sortable
+// without '{sortable}' markers.
+func WithTableSortAll(value bool) TableSortOption {
+	return &withTableSortAll{value}
+}
+
+type withTableSortSampleRows struct {
+	value int
+}
+
+func (o *withTableSortSampleRows) SetParserOption(c *parser.Config) {
+	c.Options[optTableSortSampleRows] = o.value
+}
+
+func (o *withTableSortSampleRows) SetTableSortOption(c *TableSortConfig) {
+	c.SampleRows = o.value
+}
+
+// WithTableSortSampleRows is a functional option that specify a number of
+// rows those are sampled to detect types of columns.
+// This defaults to DefaultTableSortSampleRows.
+func WithTableSortSampleRows(value int) TableSortOption {
+	return &withTableSortSampleRows{value}
+}
+
+type withTableSortScript struct {
+	value string
+}
+
+func (o *withTableSortScript) SetParserOption(c *parser.Config) {
+	c.Options[optTableSortScript] = o.value
+}
+
+func (o *withTableSortScript) SetTableSortOption(c *TableSortConfig) {
+	c.Script = o.value
+}
+
+// WithTableSortScript is a functional option that specify a script
+// appended to documents those have sortable tables. An empty script
+// disables scripts so that you can load your own one.
+// This defaults to DefaultTableSortScript.
+func WithTableSortScript(value string) TableSortOption {
+	return &withTableSortScript{value}
+}
+
+type tableSortASTTransformer struct {
+	TableSortConfig
+}
+
+// NewTableSortASTTransformer returns a new parser.ASTTransformer that
+// adds sort types to headers of sortable tables.
+func NewTableSortASTTransformer(opts ...TableSortOption) parser.ASTTransformer {
+	t := &tableSortASTTransformer{
+		TableSortConfig: TableSortConfig{
+			SampleRows: DefaultTableSortSampleRows,
+			Script:     DefaultTableSortScript,
+		},
+	}
+	for _, o := range opts {
+		o.SetTableSortOption(&t.TableSortConfig)
+	}
+	return t
+}
+
+var tableSortMarkers = [][]byte{
+	[]byte("{sortable}"),
+	[]byte("{.sortable}"),
+}
+
+// tableMarker returns a paragraph that consists of a line like
+// '{sortable}' written just before the given table and the trimmed line.
+func tableMarker(table *ast.Table, source []byte) (gast.Node, []byte) {
+	prev, ok := table.PreviousSibling().(*gast.Paragraph)
+	if !ok || prev.Lines().Len() != 1 {
+		return nil, nil
+	}
+	segment := prev.Lines().At(0)
+	// tables are made from paragraphs, so the marker and the table are
+	// separated by blank lines if the next line is blank
+	next := source[segment.Stop:]
+	if i := bytes.IndexByte(next, '\n'); i > -1 {
+		next = next[i+1:]
+	}
+	if i := bytes.IndexByte(next, '\n'); i > -1 {
+		next = next[:i+1]
+	}
+	if util.IsBlank(next) {
+		return nil, nil
+	}
+	return prev, util.TrimRightSpace(util.TrimLeftSpace(segment.Value(source)))
+}
+
+// tableSortMarker returns a paragraph like '{sortable}' that is written
+// just before the given table.
+func tableSortMarker(table *ast.Table, source []byte) gast.Node {
+	marker, value := tableMarker(table, source)
+	if marker == nil {
+		return nil
+	}
+	for _, m := range tableSortMarkers {
+		if bytes.Equal(value, m) {
+			return marker
+		}
+	}
+	return nil
+}
+
+func (a *tableSortASTTransformer) sort(table *ast.Table, source []byte) {
+	header, ok := table.FirstChild().(*ast.TableHeader)
+	if !ok {
+		return
+	}
+	columns := make([][]string, header.ChildCount())
+	rows := 0
+	for row := header.NextSibling(); row != nil && rows < a.SampleRows; row = row.NextSibling() {
+		i := 0
+		for cell := row.FirstChild(); cell != nil && i < len(columns); cell = cell.NextSibling() {
+			columns[i] = append(columns[i], strings.TrimSpace(string(cell.Text(source))))
+			i++
+		}
+		rows++
+	}
+	table.SetAttributeString("data-sortable", []byte("true"))
+	i := 0
+	for cell := header.FirstChild(); cell != nil; cell = cell.NextSibling() {
+		cell.SetAttributeString("data-sort-type", []byte(detectTableSortType(columns[i])))
+		i++
+	}
+}
+
+func (a *tableSortASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var tables []*ast.Table
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		if table, ok := n.(*ast.Table); ok {
+			tables = append(tables, table)
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	sorted := false
+	for _, table := range tables {
+		marker := tableSortMarker(table, source)
+		if marker != nil {
+			marker.Parent().RemoveChild(marker.Parent(), marker)
+		} else if !a.All {
+			continue
+		}
+		a.sort(table, source)
+		sorted = true
+	}
+	if sorted && len(a.Script) != 0 {
+		node.AppendChild(node, ast.NewTableSortScript([]byte(a.Script)))
+	}
+}
+
+// TableSortHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders TableSortScript nodes.
+type TableSortHTMLRenderer struct {
+	html.Config
+}
+
+// NewTableSortHTMLRenderer returns a new TableSortHTMLRenderer.
+func NewTableSortHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &TableSortHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *TableSortHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindTableSortScript, r.renderTableSortScript)
+}
+
+func (r *TableSortHTMLRenderer) renderTableSortScript(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	// scripts are not rendered in the safe mode
+	if !entering || r.SafeMode {
+		return gast.WalkContinue, nil
+	}
+	n := node.(*ast.TableSortScript)
+	_, _ = w.WriteString("<script")
+	r.RenderCSPNonce(w, node)
+	_, _ = w.WriteString(">\n")
+	_, _ = w.Write(n.Script)
+	if len(n.Script) != 0 && n.Script[len(n.Script)-1] != '\n' {
+		_ = w.WriteByte('\n')
+	}
+	_, _ = w.WriteString("</script>\n")
+	return gast.WalkContinue, nil
+}
+
+type tableSort struct {
+	options []TableSortOption
+}
+
+// TableSort is an extension that makes tables sortable by clicking their
+// headers. Tables those are preceded by a line like '{sortable}' are
+// sortable. Headers of sortable tables have 'data-sort-type' attributes
+// detected from the first rows and a script that sorts rows is appended
+// to the document. The script has a nonce given by html.WithCSPNonce and
+// is not rendered in the safe mode. Tables are parsed by the Table
+// extension.
+var TableSort = &tableSort{}
+
+// NewTableSort returns a new extension with given options.
+func NewTableSort(opts ...TableSortOption) goldmark.Extender {
+	return &tableSort{
+		options: opts,
+	}
+}
+
+func (e *tableSort) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewTableSortASTTransformer(e.options...), 1000),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewTableSortHTMLRenderer(), 500),
+	))
+}
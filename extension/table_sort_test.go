@@ -0,0 +1,92 @@
+package extension
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestTableSort(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Table,
+			NewTableSort(WithTableSortScript("sort();")),
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/table_sort.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestTableSortAll(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Table,
+			NewTableSort(WithTableSortAll(true), WithTableSortScript(""), WithTableSortSampleRows(1)),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No: 1,
+		Markdown: `| A |
+|---|
+| 1 |
+| a |`,
+		Expected: `<table data-sortable="true">
+<thead>
+<tr>
+<th data-sort-type="numeric">A</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>1</td>
+</tr>
+<tr>
+<td>a</td>
+</tr>
+</tbody>
+</table>`,
+	}, t)
+}
+
+func TestTableSortScript(t *testing.T) {
+	source := "{sortable}\n| A |\n|---|\n| 1 |\n"
+	table := `<table data-sortable="true">
+<thead>
+<tr>
+<th data-sort-type="numeric">A</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>1</td>
+</tr>
+</tbody>
+</table>
+`
+	cases := []struct {
+		option   renderer.Option
+		expected string
+	}{
+		{html.WithCSPNonce("abc"), table + "<script nonce=\"abc\">\nsort();\n</script>\n"},
+		{html.WithSafeMode(), table},
+	}
+	for i, c := range cases {
+		markdown := goldmark.New(
+			goldmark.WithRendererOptions(c.option),
+			goldmark.WithExtensions(
+				Table,
+				NewTableSort(WithTableSortScript("sort();")),
+			),
+		)
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte(source), &b); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != c.expected {
+			t.Errorf("%d: expected %q, but got %q", i, c.expected, b.String())
+		}
+	}
+}
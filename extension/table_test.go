@@ -1,12 +1,14 @@
 package extension
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	east "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/testutil"
 	"github.com/yuin/goldmark/text"
@@ -26,162 +28,55 @@ func TestTable(t *testing.T) {
 	testutil.DoTestCaseFile(markdown, "_test/table.txt", t, testutil.ParseCliCaseArg()...)
 }
 
-func TestTableWithAlignDefault(t *testing.T) {
-	markdown := goldmark.New(
-		goldmark.WithRendererOptions(
-			html.WithXHTML(),
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			NewTable(
-				WithTableCellAlignMethod(TableCellAlignDefault),
-			),
-		),
-	)
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          1,
-			Description: "Cell with TableCellAlignDefault and XHTML should be rendered as an align attribute",
-			Markdown: `
-| abc | defghi |
-:-: | -----------:
-bar | baz
-`,
-			Expected: `<table>
-<thead>
-<tr>
-<th align="center">abc</th>
-<th align="right">defghi</th>
-</tr>
-</thead>
-<tbody>
-<tr>
-<td align="center">bar</td>
-<td align="right">baz</td>
-</tr>
-</tbody>
-</table>`,
-		},
-		t,
-	)
+// goldenDir is a directory that holds golden files of testutil.GoldenTest.
+const goldenDir = "_test/golden"
 
-	markdown = goldmark.New(
-		goldmark.WithRendererOptions(
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			NewTable(
-				WithTableCellAlignMethod(TableCellAlignDefault),
-			),
-		),
-	)
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          2,
-			Description: "Cell with TableCellAlignDefault and HTML5 should be rendered as a style attribute",
-			Markdown: `
+const tableAlignSource = `
 | abc | defghi |
 :-: | -----------:
 bar | baz
-`,
-			Expected: `<table>
-<thead>
-<tr>
-<th style="text-align:center">abc</th>
-<th style="text-align:right">defghi</th>
-</tr>
-</thead>
-<tbody>
-<tr>
-<td style="text-align:center">bar</td>
-<td style="text-align:right">baz</td>
-</tr>
-</tbody>
-</table>`,
-		},
-		t,
-	)
+`
+
+type tableAlignCase struct {
+	// name is a name of the golden file.
+	name    string
+	xhtml   bool
+	options []goldmark.Option
 }
 
-func TestTableWithAlignAttribute(t *testing.T) {
-	markdown := goldmark.New(
-		goldmark.WithRendererOptions(
-			html.WithXHTML(),
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			NewTable(
-				WithTableCellAlignMethod(TableCellAlignAttribute),
-			),
-		),
-	)
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          1,
-			Description: "Cell with TableCellAlignAttribute and XHTML should be rendered as an align attribute",
-			Markdown: `
-| abc | defghi |
-:-: | -----------:
-bar | baz
-`,
-			Expected: `<table>
-<thead>
-<tr>
-<th align="center">abc</th>
-<th align="right">defghi</th>
-</tr>
-</thead>
-<tbody>
-<tr>
-<td align="center">bar</td>
-<td align="right">baz</td>
-</tr>
-</tbody>
-</table>`,
-		},
-		t,
-	)
+func doTableAlignTest(t *testing.T, method TableCellAlignMethod, cases []tableAlignCase) {
+	for _, c := range cases {
+		rendererOptions := []renderer.Option{html.WithUnsafe()}
+		if c.xhtml {
+			rendererOptions = append(rendererOptions, html.WithXHTML())
+		}
+		options := []goldmark.Option{
+			goldmark.WithRendererOptions(rendererOptions...),
+			goldmark.WithExtensions(NewTable(WithTableCellAlignMethod(method))),
+		}
+		markdown := goldmark.New(append(options, c.options...)...)
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte(tableAlignSource), &b); err != nil {
+			t.Fatal(err)
+		}
+		testutil.GoldenTest(t, goldenDir, c.name, b.String())
+	}
+}
 
-	markdown = goldmark.New(
-		goldmark.WithRendererOptions(
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			NewTable(
-				WithTableCellAlignMethod(TableCellAlignAttribute),
-			),
-		),
-	)
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          2,
-			Description: "Cell with TableCellAlignAttribute and HTML5 should be rendered as an align attribute",
-			Markdown: `
-| abc | defghi |
-:-: | -----------:
-bar | baz
-`,
-			Expected: `<table>
-<thead>
-<tr>
-<th align="center">abc</th>
-<th align="right">defghi</th>
-</tr>
-</thead>
-<tbody>
-<tr>
-<td align="center">bar</td>
-<td align="right">baz</td>
-</tr>
-</tbody>
-</table>`,
-		},
-		t,
-	)
+func TestTableWithAlignDefault(t *testing.T) {
+	doTableAlignTest(t, TableCellAlignDefault, []tableAlignCase{
+		// cells with XHTML should be rendered as an align attribute
+		{name: "table-align-default-xhtml", xhtml: true},
+		// cells with HTML5 should be rendered as a style attribute
+		{name: "table-align-default-html5"},
+	})
+}
+
+func TestTableWithAlignAttribute(t *testing.T) {
+	doTableAlignTest(t, TableCellAlignAttribute, []tableAlignCase{
+		{name: "table-align-attribute-xhtml", xhtml: true},
+		{name: "table-align-attribute-html5"},
+	})
 }
 
 type tableStyleTransformer struct {
@@ -193,165 +88,22 @@ func (a *tableStyleTransformer) Transform(node *ast.Document, reader text.Reader
 }
 
 func TestTableWithAlignStyle(t *testing.T) {
-	markdown := goldmark.New(
-		goldmark.WithRendererOptions(
-			html.WithXHTML(),
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			NewTable(
-				WithTableCellAlignMethod(TableCellAlignStyle),
-			),
-		),
-	)
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          1,
-			Description: "Cell with TableCellAlignStyle and XHTML should be rendered as a style attribute",
-			Markdown: `
-| abc | defghi |
-:-: | -----------:
-bar | baz
-`,
-			Expected: `<table>
-<thead>
-<tr>
-<th style="text-align:center">abc</th>
-<th style="text-align:right">defghi</th>
-</tr>
-</thead>
-<tbody>
-<tr>
-<td style="text-align:center">bar</td>
-<td style="text-align:right">baz</td>
-</tr>
-</tbody>
-</table>`,
-		},
-		t,
-	)
-
-	markdown = goldmark.New(
-		goldmark.WithRendererOptions(
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			NewTable(
-				WithTableCellAlignMethod(TableCellAlignStyle),
-			),
-		),
-	)
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          2,
-			Description: "Cell with TableCellAlignStyle and HTML5 should be rendered as a style attribute",
-			Markdown: `
-| abc | defghi |
-:-: | -----------:
-bar | baz
-`,
-			Expected: `<table>
-<thead>
-<tr>
-<th style="text-align:center">abc</th>
-<th style="text-align:right">defghi</th>
-</tr>
-</thead>
-<tbody>
-<tr>
-<td style="text-align:center">bar</td>
-<td style="text-align:right">baz</td>
-</tr>
-</tbody>
-</table>`,
-		},
-		t,
-	)
-
-	markdown = goldmark.New(
-		goldmark.WithParserOptions(
-			parser.WithASTTransformers(
-				util.Prioritized(&tableStyleTransformer{}, 0),
+	doTableAlignTest(t, TableCellAlignStyle, []tableAlignCase{
+		{name: "table-align-style-xhtml", xhtml: true},
+		{name: "table-align-style-html5"},
+		// styled cells should not be broken the style by the alignments
+		{name: "table-align-style-styled-cell", options: []goldmark.Option{
+			goldmark.WithParserOptions(
+				parser.WithASTTransformers(
+					util.Prioritized(&tableStyleTransformer{}, 0),
+				),
 			),
-		),
-		goldmark.WithRendererOptions(
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			NewTable(
-				WithTableCellAlignMethod(TableCellAlignStyle),
-			),
-		),
-	)
-
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          3,
-			Description: "Styled cell should not be broken the style by the alignments",
-			Markdown: `
-| abc | defghi |
-:-: | -----------:
-bar | baz
-`,
-			Expected: `<table>
-<thead>
-<tr>
-<th style="font-size:1em;text-align:center">abc</th>
-<th style="text-align:right">defghi</th>
-</tr>
-</thead>
-<tbody>
-<tr>
-<td style="text-align:center">bar</td>
-<td style="text-align:right">baz</td>
-</tr>
-</tbody>
-</table>`,
-		},
-		t,
-	)
+		}},
+	})
 }
 
 func TestTableWithAlignNone(t *testing.T) {
-	markdown := goldmark.New(
-		goldmark.WithRendererOptions(
-			html.WithXHTML(),
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			NewTable(
-				WithTableCellAlignMethod(TableCellAlignNone),
-			),
-		),
-	)
-	testutil.DoTestCase(
-		markdown,
-		testutil.MarkdownTestCase{
-			No:          1,
-			Description: "Cell with TableCellAlignStyle and XHTML should not be rendered",
-			Markdown: `
-| abc | defghi |
-:-: | -----------:
-bar | baz
-`,
-			Expected: `<table>
-<thead>
-<tr>
-<th>abc</th>
-<th>defghi</th>
-</tr>
-</thead>
-<tbody>
-<tr>
-<td>bar</td>
-<td>baz</td>
-</tr>
-</tbody>
-</table>`,
-		},
-		t,
-	)
+	doTableAlignTest(t, TableCellAlignNone, []tableAlignCase{
+		{name: "table-align-none-xhtml", xhtml: true},
+	})
 }
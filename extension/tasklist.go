@@ -93,11 +93,8 @@ func (r *TaskCheckBoxHTMLRenderer) renderTaskCheckBox(
 	} else {
 		_, _ = w.WriteString(`<input disabled="" type="checkbox"`)
 	}
-	if r.XHTML {
-		_, _ = w.WriteString(" /> ")
-	} else {
-		_, _ = w.WriteString("> ")
-	}
+	_, _ = w.WriteString(r.VoidElementEnd())
+	_ = w.WriteByte(' ')
 	return gast.WalkContinue, nil
 }
 
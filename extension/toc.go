@@ -0,0 +1,322 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// TOCListStyle is a style of lists in the table of contents.
+type TOCListStyle int
+
+const (
+	// TOCUnordered renders the table of contents as unordered lists.
+	TOCUnordered TOCListStyle = iota
+
+	// TOCOrdered renders the table of contents as ordered lists.
+	TOCOrdered
+)
+
+// A TOCConfig struct is a data structure that holds configuration of the
+// TOC extension.
+type TOCConfig struct {
+	// MinDepth is a minimum level of headings in the table of contents.
+	MinDepth int
+
+	// MaxDepth is a maximum level of headings in the table of contents.
+	MaxDepth int
+
+	// ListStyle is a style of lists in the table of contents.
+	ListStyle TOCListStyle
+
+	// HeadingIDFunc is a function that returns an id of the heading
+	// that has no id attribute. If HeadingIDFunc is nil, ids are generated
+	// by parser.Context.IDs.
+	HeadingIDFunc func(heading *gast.Heading, source []byte) []byte
+}
+
+// NewTOCConfig returns a new TOCConfig with defaults.
+func NewTOCConfig() TOCConfig {
+	return TOCConfig{
+		MinDepth:  1,
+		MaxDepth:  6,
+		ListStyle: TOCUnordered,
+	}
+}
+
+const (
+	optTOCMinDepth      parser.OptionName = "TOCMinDepth"
+	optTOCMaxDepth      parser.OptionName = "TOCMaxDepth"
+	optTOCListStyle     parser.OptionName = "TOCListStyle"
+	optTOCHeadingIDFunc parser.OptionName = "TOCHeadingIDFunc"
+)
+
+// SetOption implements SetOptioner.
+func (c *TOCConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optTOCMinDepth:
+		c.MinDepth = value.(int)
+	case optTOCMaxDepth:
+		c.MaxDepth = value.(int)
+	case optTOCListStyle:
+		c.ListStyle = value.(TOCListStyle)
+	case optTOCHeadingIDFunc:
+		c.HeadingIDFunc = value.(func(*gast.Heading, []byte) []byte)
+	}
+}
+
+// A TOCOption interface sets options for the TOC extension.
+type TOCOption interface {
+	parser.Option
+	SetTOCOption(*TOCConfig)
+}
+
+type withTOCMinDepth struct {
+	value int
+}
+
+func (o *withTOCMinDepth) SetParserOption(c *parser.Config) {
+	c.Options[optTOCMinDepth] = o.value
+}
+
+func (o *withTOCMinDepth) SetTOCOption(c *TOCConfig) {
+	c.MinDepth = o.value
+}
+
+// WithTOCMinDepth is a functional option that specify a minimum level
+// of headings in the table of contents. This defaults to 1.
+func WithTOCMinDepth(depth int) TOCOption {
+	return &withTOCMinDepth{depth}
+}
+
+type withTOCMaxDepth struct {
+	value int
+}
+
+func (o *withTOCMaxDepth) SetParserOption(c *parser.Config) {
+	c.Options[optTOCMaxDepth] = o.value
+}
+
+func (o *withTOCMaxDepth) SetTOCOption(c *TOCConfig) {
+	c.MaxDepth = o.value
+}
+
+// WithTOCMaxDepth is a functional option that specify a maximum level
+// of headings in the table of contents. This defaults to 6.
+func WithTOCMaxDepth(depth int) TOCOption {
+	return &withTOCMaxDepth{depth}
+}
+
+type withTOCListStyle struct {
+	value TOCListStyle
+}
+
+func (o *withTOCListStyle) SetParserOption(c *parser.Config) {
+	c.Options[optTOCListStyle] = o.value
+}
+
+func (o *withTOCListStyle) SetTOCOption(c *TOCConfig) {
+	c.ListStyle = o.value
+}
+
+// WithTOCListStyle is a functional option that specify a style of lists
+// in the table of contents. This defaults to TOCUnordered.
+func WithTOCListStyle(style TOCListStyle) TOCOption {
+	return &withTOCListStyle{style}
+}
+
+type withTOCHeadingIDFunc struct {
+	value func(*gast.Heading, []byte) []byte
+}
+
+func (o *withTOCHeadingIDFunc) SetParserOption(c *parser.Config) {
+	c.Options[optTOCHeadingIDFunc] = o.value
+}
+
+func (o *withTOCHeadingIDFunc) SetTOCOption(c *TOCConfig) {
+	c.HeadingIDFunc = o.value
+}
+
+// WithTOCHeadingIDFunc is a functional option that specify a function
+// that returns an id of the heading that has no id attribute.
+func WithTOCHeadingIDFunc(f func(heading *gast.Heading, source []byte) []byte) TOCOption {
+	return &withTOCHeadingIDFunc{f}
+}
+
+type tocASTTransformer struct {
+	TOCConfig
+}
+
+// NewTOCASTTransformer returns a new parser.ASTTransformer that inserts
+// a table of contents to the document. The table of contents replaces
+// a '[TOC]' paragraph if exists, otherwise it is prepended to the document.
+func NewTOCASTTransformer(opts ...TOCOption) parser.ASTTransformer {
+	t := &tocASTTransformer{
+		TOCConfig: NewTOCConfig(),
+	}
+	for _, o := range opts {
+		o.SetTOCOption(&t.TOCConfig)
+	}
+	return t
+}
+
+var tocPlaceholder = []byte("[TOC]")
+
+func isTOCPlaceholder(node gast.Node, source []byte) bool {
+	if node.Kind() != gast.KindParagraph || node.Lines().Len() != 1 {
+		return false
+	}
+	line := node.Lines().At(0)
+	return bytes.Equal(util.TrimRightSpace(util.TrimLeftSpace(line.Value(source))), tocPlaceholder)
+}
+
+func (a *tocASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var placeholder gast.Node
+	var headings []*gast.Heading
+	for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+		if placeholder == nil && isTOCPlaceholder(c, source) {
+			placeholder = c
+			continue
+		}
+		if h, ok := c.(*gast.Heading); ok && h.Level >= a.MinDepth && h.Level <= a.MaxDepth {
+			headings = append(headings, h)
+		}
+	}
+	if len(headings) == 0 {
+		if placeholder != nil {
+			node.RemoveChild(node, placeholder)
+		}
+		return
+	}
+
+	toc := ast.NewTableOfContents()
+	type level struct {
+		list  *gast.List
+		depth int
+	}
+	var stack []level
+	for _, h := range headings {
+		item := gast.NewListItem(0)
+		tb := gast.NewTextBlock()
+		link := gast.NewLink()
+		link.Destination = append([]byte("#"), a.headingID(h, source, pc)...)
+		link.AppendChild(link, gast.NewString(h.Text(source)))
+		tb.AppendChild(tb, link)
+		item.AppendChild(item, tb)
+
+		for len(stack) > 1 && stack[len(stack)-1].depth > h.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			list := a.newList()
+			toc.AppendChild(toc, list)
+			stack = append(stack, level{list, h.Level})
+		} else if top := stack[len(stack)-1]; top.depth < h.Level {
+			parent := top.list.LastChild()
+			list := a.newList()
+			parent.AppendChild(parent, list)
+			stack = append(stack, level{list, h.Level})
+		}
+		top := stack[len(stack)-1].list
+		top.AppendChild(top, item)
+	}
+
+	if placeholder != nil {
+		node.ReplaceChild(node, placeholder, toc)
+	} else {
+		node.InsertBefore(node, node.FirstChild(), toc)
+	}
+}
+
+func (a *tocASTTransformer) newList() *gast.List {
+	if a.ListStyle == TOCOrdered {
+		list := gast.NewList('.')
+		list.Start = 1
+		return list
+	}
+	return gast.NewList('-')
+}
+
+func (a *tocASTTransformer) headingID(h *gast.Heading, source []byte, pc parser.Context) []byte {
+	if id, ok := h.AttributeString("id"); ok {
+		if v, ok := id.([]byte); ok {
+			return v
+		}
+	}
+	var id []byte
+	if a.HeadingIDFunc != nil {
+		id = a.HeadingIDFunc(h, source)
+		pc.IDs().Put(id)
+	} else {
+		id = pc.IDs().Generate(h.Text(source), gast.KindHeading)
+	}
+	h.SetAttributeString("id", id)
+	return id
+}
+
+// TOCHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders TableOfContents nodes.
+type TOCHTMLRenderer struct {
+	html.Config
+}
+
+// NewTOCHTMLRenderer returns a new TOCHTMLRenderer.
+func NewTOCHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &TOCHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *TOCHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindTableOfContents, r.renderTableOfContents)
+}
+
+func (r *TOCHTMLRenderer) renderTableOfContents(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<nav class="toc"`)
+		if node.Attributes() != nil {
+			html.RenderAttributes(w, node, html.GlobalAttributeFilter)
+		}
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</nav>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+type toc struct {
+	options []TOCOption
+}
+
+// TOC is an extension that inserts a table of contents to the document.
+var TOC = &toc{}
+
+// NewTOC returns a new extension with given options.
+func NewTOC(opts ...TOCOption) goldmark.Extender {
+	return &toc{
+		options: opts,
+	}
+}
+
+func (e *toc) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewTOCASTTransformer(e.options...), 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewTOCHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,58 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestTOC(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+		goldmark.WithExtensions(
+			TOC,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/toc.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestTOCOptions(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTOC(
+				WithTOCMinDepth(2),
+				WithTOCMaxDepth(3),
+				WithTOCListStyle(TOCOrdered),
+			),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:          1,
+		Description: "depth and list style",
+		Markdown: `# Title
+[TOC]
+## A
+### A-1
+#### A-1-a
+## A`,
+		Expected: `<h1>Title</h1>
+<nav class="toc">
+<ol>
+<li><a href="#a">A</a>
+<ol>
+<li><a href="#a-1">A-1</a></li>
+</ol>
+</li>
+<li><a href="#a-2">A</a></li>
+</ol>
+</nav>
+<h2 id="a">A</h2>
+<h3 id="a-1">A-1</h3>
+<h4>A-1-a</h4>
+<h2 id="a-2">A</h2>`,
+	}, t)
+}
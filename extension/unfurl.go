@@ -0,0 +1,242 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// An OpenGraphData struct holds Open Graph metadata of a web page.
+type OpenGraphData struct {
+	// Title is a value of 'og:title'.
+	Title string
+
+	// Description is a value of 'og:description'.
+	Description string
+
+	// Image is a value of 'og:image'.
+	Image string
+}
+
+// An UnfurlProvider interface fetches Open Graph metadata of web pages.
+// UnfurlProviders should cache results if needed.
+type UnfurlProvider interface {
+	// Fetch returns Open Graph metadata of the given URL.
+	Fetch(url string) (OpenGraphData, error)
+}
+
+// An UnfurlConfig struct is a data structure that holds configuration of the
+// Unfurl extension.
+type UnfurlConfig struct {
+	// Provider is an UnfurlProvider that fetches Open Graph metadata.
+	// Links are not unfurled if Provider is nil.
+	Provider UnfurlProvider
+}
+
+const optUnfurlProvider parser.OptionName = "UnfurlProvider"
+
+// SetOption implements SetOptioner.
+func (c *UnfurlConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optUnfurlProvider:
+		c.Provider = value.(UnfurlProvider)
+	}
+}
+
+// An UnfurlOption interface sets options for the Unfurl extension.
+type UnfurlOption interface {
+	parser.Option
+	SetUnfurlOption(*UnfurlConfig)
+}
+
+type withUnfurlProvider struct {
+	value UnfurlProvider
+}
+
+func (o *withUnfurlProvider) SetParserOption(c *parser.Config) {
+	c.Options[optUnfurlProvider] = o.value
+}
+
+func (o *withUnfurlProvider) SetUnfurlOption(c *UnfurlConfig) {
+	c.Provider = o.value
+}
+
+// WithUnfurlProvider is a functional option that specify an
+// UnfurlProvider that fetches Open Graph metadata of links.
+func WithUnfurlProvider(value UnfurlProvider) UnfurlOption {
+	return &withUnfurlProvider{value}
+}
+
+type unfurlASTTransformer struct {
+	UnfurlConfig
+}
+
+// NewUnfurlASTTransformer returns a new parser.ASTTransformer that
+// replaces paragraphs those contain only a bare link with LinkCards.
+func NewUnfurlASTTransformer(opts ...UnfurlOption) parser.ASTTransformer {
+	t := &unfurlASTTransformer{}
+	for _, o := range opts {
+		o.SetUnfurlOption(&t.UnfurlConfig)
+	}
+	return t
+}
+
+// bareLinkURL returns an URL if the given paragraph contains only
+// a link to a web page and the link text is same as its URL.
+func bareLinkURL(paragraph gast.Node, source []byte) []byte {
+	c := paragraph.FirstChild()
+	if c == nil || c.NextSibling() != nil {
+		return nil
+	}
+	var url []byte
+	switch n := c.(type) {
+	case *gast.AutoLink:
+		if n.AutoLinkType != gast.AutoLinkURL {
+			return nil
+		}
+		url = n.URL(source)
+	case *gast.Link:
+		t, ok := n.FirstChild().(*gast.Text)
+		if !ok || t.NextSibling() != nil || !bytes.Equal(t.Segment.Value(source), n.Destination) {
+			return nil
+		}
+		url = n.Destination
+	default:
+		return nil
+	}
+	if !bytes.HasPrefix(url, []byte("http://")) && !bytes.HasPrefix(url, []byte("https://")) {
+		return nil
+	}
+	return url
+}
+
+func (a *unfurlASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	if a.Provider == nil {
+		return
+	}
+	var paragraphs []gast.Node
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if entering && n.Kind() == gast.KindParagraph {
+			paragraphs = append(paragraphs, n)
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	source := reader.Source()
+	for _, paragraph := range paragraphs {
+		url := bareLinkURL(paragraph, source)
+		if url == nil {
+			continue
+		}
+		data, err := a.Provider.Fetch(string(url))
+		if err != nil {
+			continue
+		}
+		card := ast.NewLinkCard(url)
+		card.Title = []byte(data.Title)
+		card.Description = []byte(data.Description)
+		card.Image = []byte(data.Image)
+		paragraph.Parent().ReplaceChild(paragraph.Parent(), paragraph, card)
+	}
+}
+
+// LinkCardHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders LinkCard nodes.
+type LinkCardHTMLRenderer struct {
+	html.Config
+}
+
+// NewLinkCardHTMLRenderer returns a new LinkCardHTMLRenderer.
+func NewLinkCardHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &LinkCardHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *LinkCardHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindLinkCard, r.renderLinkCard)
+}
+
+func (r *LinkCardHTMLRenderer) writeURL(w util.BufWriter, url []byte) {
+	if !r.Unsafe && html.IsDangerousURL(url) {
+		return
+	}
+	url = util.URLEscape(url, false)
+	if r.URLSanitizer != nil && !r.URLSanitizer(string(url)) {
+		url = []byte(r.URLSanitizerFallback)
+	}
+	_, _ = w.Write(util.EscapeHTML(url))
+}
+
+func (r *LinkCardHTMLRenderer) renderLinkCard(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+	n := node.(*ast.LinkCard)
+	_, _ = w.WriteString(`<div class="link-card"`)
+	if n.Attributes() != nil {
+		html.RenderAttributes(w, n, html.GlobalAttributeFilter)
+	}
+	_, _ = w.WriteString(`><a href="`)
+	r.writeURL(w, n.Destination)
+	_, _ = w.WriteString("\">\n")
+	if len(n.Image) != 0 {
+		_, _ = w.WriteString(`<img class="link-card-image" src="`)
+		r.writeURL(w, n.Image)
+		_, _ = w.WriteString(`" alt=""`)
+		_, _ = w.WriteString(r.VoidElementEnd())
+		_ = w.WriteByte('\n')
+	}
+	title := n.Title
+	if len(title) == 0 {
+		title = n.Destination
+	}
+	_, _ = w.WriteString(`<span class="link-card-title">`)
+	_, _ = w.Write(util.EscapeHTML(title))
+	_, _ = w.WriteString("</span>\n")
+	if len(n.Description) != 0 {
+		_, _ = w.WriteString(`<span class="link-card-description">`)
+		_, _ = w.Write(util.EscapeHTML(n.Description))
+		_, _ = w.WriteString("</span>\n")
+	}
+	_, _ = w.WriteString("</a></div>\n")
+	return gast.WalkSkipChildren, nil
+}
+
+type unfurl struct {
+	options []UnfurlOption
+}
+
+// Unfurl is an extension that renders paragraphs those contain only
+// a bare link as link cards. Use NewUnfurl with WithUnfurlProvider
+// to fetch Open Graph metadata of links.
+var Unfurl = &unfurl{}
+
+// NewUnfurl returns a new extension with given options.
+func NewUnfurl(opts ...UnfurlOption) goldmark.Extender {
+	return &unfurl{
+		options: opts,
+	}
+}
+
+func (e *unfurl) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewUnfurlASTTransformer(e.options...), 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewLinkCardHTMLRenderer(), 500),
+	))
+}
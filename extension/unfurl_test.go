@@ -0,0 +1,34 @@
+package extension
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+type testUnfurlProvider map[string]OpenGraphData
+
+func (p testUnfurlProvider) Fetch(url string) (OpenGraphData, error) {
+	if data, ok := p[url]; ok {
+		return data, nil
+	}
+	return OpenGraphData{}, errors.New("not found")
+}
+
+func TestUnfurl(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewUnfurl(WithUnfurlProvider(testUnfurlProvider{
+				"https://example.com/a": {
+					Title:       "Page <A>",
+					Description: "About A & more",
+					Image:       "https://example.com/a.png",
+				},
+				"https://example.com/b": {},
+			})),
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/unfurl.txt", t, testutil.ParseCliCaseArg()...)
+}
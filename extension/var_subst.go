@@ -0,0 +1,210 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// An UndefinedVariableHandler is a function that is called with a name of
+// a variable like '{{NAME}}' those value is not defined.
+type UndefinedVariableHandler func(name string)
+
+// A VarSubstConfig struct is a data structure that holds configuration of the
+// VarSubst extension.
+type VarSubstConfig struct {
+	// Variables is a map of variable names and values.
+	Variables map[string]string
+
+	// UndefinedVariableHandler is called when an undefined variable is found.
+	UndefinedVariableHandler UndefinedVariableHandler
+}
+
+const optVariables parser.OptionName = "Variables"
+
+const optUndefinedVariableHandler parser.OptionName = "UndefinedVariableHandler"
+
+// SetOption implements SetOptioner.
+func (c *VarSubstConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optVariables:
+		c.Variables = value.(map[string]string)
+	case optUndefinedVariableHandler:
+		c.UndefinedVariableHandler = value.(UndefinedVariableHandler)
+	}
+}
+
+// A VarSubstOption interface sets options for the VarSubst extension.
+type VarSubstOption interface {
+	parser.Option
+	SetVarSubstOption(*VarSubstConfig)
+}
+
+type withVariables struct {
+	value map[string]string
+}
+
+func (o *withVariables) SetParserOption(c *parser.Config) {
+	c.Options[optVariables] = o.value
+}
+
+func (o *withVariables) SetVarSubstOption(c *VarSubstConfig) {
+	c.Variables = o.value
+}
+
+// WithVariables is a functional option that specify values of variables.
+func WithVariables(value map[string]string) VarSubstOption {
+	return &withVariables{value}
+}
+
+type withUndefinedVariableHandler struct {
+	value UndefinedVariableHandler
+}
+
+func (o *withUndefinedVariableHandler) SetParserOption(c *parser.Config) {
+	c.Options[optUndefinedVariableHandler] = o.value
+}
+
+func (o *withUndefinedVariableHandler) SetVarSubstOption(c *VarSubstConfig) {
+	c.UndefinedVariableHandler = o.value
+}
+
+// WithUndefinedVariableHandler is a functional option that specify a
+// function called when an undefined variable is found. Undefined variables
+// are left unchanged regardless of this option.
+func WithUndefinedVariableHandler(value UndefinedVariableHandler) VarSubstOption {
+	return &withUndefinedVariableHandler{value}
+}
+
+type varSubstASTTransformer struct {
+	VarSubstConfig
+}
+
+// NewVarSubstASTTransformer returns a new parser.ASTTransformer that
+// replaces variables like '{{NAME}}' in texts with their values.
+func NewVarSubstASTTransformer(opts ...VarSubstOption) parser.ASTTransformer {
+	t := &varSubstASTTransformer{}
+	for _, o := range opts {
+		o.SetVarSubstOption(&t.VarSubstConfig)
+	}
+	return t
+}
+
+var (
+	varSubstOpener = []byte("{{")
+	varSubstCloser = []byte("}}")
+)
+
+// isVariableName returns true if the given name consists of
+// alphanumerics, '_', '-' and '.' .
+func isVariableName(name []byte) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for _, c := range name {
+		if !util.IsAlphaNumeric(c) && c != '_' && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeNextTexts merges following Text nodes those are contiguous in the
+// source into the given Text node. Inline parsers split texts at
+// characters like '_' and '[' even if they are not delimiters.
+func mergeNextTexts(t *gast.Text, source []byte) {
+	for next, ok := t.NextSibling().(*gast.Text); ok; next, ok = t.NextSibling().(*gast.Text) {
+		if !t.Merge(next, source) {
+			return
+		}
+		t.Parent().RemoveChild(t.Parent(), next)
+	}
+}
+
+// substitute replaces variables in the given Text node. Replaced values
+// are inserted as String nodes so that renderers escape them.
+func (a *varSubstASTTransformer) substitute(n *gast.Text, source []byte) {
+	parent := n.Parent()
+	for {
+		value := source[n.Segment.Start:n.Segment.Stop]
+		start := bytes.Index(value, varSubstOpener)
+		if start < 0 {
+			return
+		}
+		stop := bytes.Index(value[start+len(varSubstOpener):], varSubstCloser)
+		if stop < 0 {
+			return
+		}
+		stop += start + len(varSubstOpener)
+		name := value[start+len(varSubstOpener) : stop]
+		end := stop + len(varSubstCloser)
+		v, ok := a.Variables[string(name)]
+		if !ok || !isVariableName(name) {
+			if a.UndefinedVariableHandler != nil && isVariableName(name) {
+				a.UndefinedVariableHandler(string(name))
+			}
+			// '{{{NAME}}}' has a variable after the first '{'
+			before := gast.NewTextSegment(n.Segment.WithStop(n.Segment.Start + start + 1))
+			parent.InsertBefore(parent, n, before)
+			n.Segment = text.NewSegment(n.Segment.Start+start+1, n.Segment.Stop)
+			continue
+		}
+		if start != 0 || n.Segment.Padding != 0 {
+			parent.InsertBefore(parent, n, gast.NewTextSegment(n.Segment.WithStop(n.Segment.Start+start)))
+		}
+		parent.InsertBefore(parent, n, gast.NewString([]byte(v)))
+		n.Segment = text.NewSegment(n.Segment.Start+end, n.Segment.Stop)
+	}
+}
+
+func (a *varSubstASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var texts []*gast.Text
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch v := n.(type) {
+		case *gast.CodeSpan:
+			return gast.WalkSkipChildren, nil
+		case *gast.Text:
+			if !v.IsRaw() {
+				texts = append(texts, v)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, t := range texts {
+		if t.Parent() == nil {
+			continue
+		}
+		mergeNextTexts(t, source)
+		a.substitute(t, source)
+	}
+}
+
+type varSubst struct {
+	options []VarSubstOption
+}
+
+// VarSubst is an extension that replaces variables like '{{VERSION}}' in
+// texts with values given by WithVariables. Values are escaped like
+// other texts. Variables in code spans and code blocks are not replaced.
+var VarSubst = &varSubst{}
+
+// NewVarSubst returns a new extension with given options.
+func NewVarSubst(opts ...VarSubstOption) goldmark.Extender {
+	return &varSubst{
+		options: opts,
+	}
+}
+
+func (e *varSubst) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewVarSubstASTTransformer(e.options...), 999),
+	))
+}
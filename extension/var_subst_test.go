@@ -0,0 +1,46 @@
+package extension
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+var varSubstTestVariables = map[string]string{
+	"VERSION":      "1.2.3",
+	"PRODUCT_NAME": "A <b> & c",
+	"TAG":          "<tag>",
+}
+
+func TestVarSubst(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewVarSubst(WithVariables(varSubstTestVariables)),
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/var_subst.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestUndefinedVariableHandler(t *testing.T) {
+	var names []string
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewVarSubst(
+				WithVariables(varSubstTestVariables),
+				WithUndefinedVariableHandler(func(name string) {
+					names = append(names, name)
+				}),
+			),
+		),
+	)
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte("{{A}} {{VERSION}} {{B_C}} {{not a variable}}"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"A", "B_C"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, but got %v", expected, names)
+	}
+}
@@ -0,0 +1,264 @@
+package extension
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var errInvalidYAMLTable = errors.New("goldmark: not a YAML sequence of mappings")
+
+type yamlField struct {
+	key   string
+	value string
+}
+
+// parseYAMLScalar returns a value of the given plain or quoted scalar.
+// Comments after the scalar are removed.
+func parseYAMLScalar(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > 1 && value[0] == '"' {
+		for i := 1; i < len(value); i++ {
+			if value[i] == '\\' {
+				i++
+			} else if value[i] == '"' {
+				if unquoted, err := strconv.Unquote(value[:i+1]); err == nil {
+					return unquoted
+				}
+				break
+			}
+		}
+	}
+	if len(value) > 1 && value[0] == '\'' {
+		for i := 1; i < len(value); i++ {
+			if value[i] != '\'' {
+				continue
+			}
+			if i+1 < len(value) && value[i+1] == '\'' {
+				i++
+				continue
+			}
+			return strings.ReplaceAll(value[1:i], "''", "'")
+		}
+	}
+	if i := strings.Index(value, " #"); i > -1 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
+
+// parseYAMLField parses a 'key: value' pair.
+func parseYAMLField(line string) (yamlField, bool) {
+	i := strings.Index(line, ": ")
+	if i < 0 {
+		if !strings.HasSuffix(line, ":") {
+			return yamlField{}, false
+		}
+		i = len(line) - 1
+	}
+	key := parseYAMLScalar(line[:i])
+	if len(key) == 0 {
+		return yamlField{}, false
+	}
+	value := strings.TrimSpace(line[i+1:])
+	if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+		return yamlField{}, false
+	}
+	return yamlField{key, parseYAMLScalar(value)}, true
+}
+
+// parseYAMLFlowMapping parses a mapping like '{a: 1, b: 2}'.
+func parseYAMLFlowMapping(value string) ([]yamlField, bool) {
+	var fields []yamlField
+	for _, item := range splitObsidianList(value[1 : len(value)-1]) {
+		field, ok := parseYAMLField(item)
+		if !ok {
+			return nil, false
+		}
+		fields = append(fields, field)
+	}
+	return fields, true
+}
+
+// parseYAMLTable parses a YAML subset that consists of a sequence of
+// mappings those values are scalars.
+func parseYAMLTable(source string) ([][]yamlField, error) {
+	var records [][]yamlField
+	indent := -1
+	for _, line := range strings.Split(source, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 || trimmed[0] == '#' || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(line, "- ") || line == "-" {
+			item := strings.TrimSpace(line[1:])
+			indent = len(line) - len(strings.TrimLeft(line[1:], " "))
+			var record []yamlField
+			switch {
+			case len(item) == 0:
+				indent = -1
+			case strings.HasPrefix(item, "{") && strings.HasSuffix(item, "}"):
+				fields, ok := parseYAMLFlowMapping(item)
+				if !ok {
+					return nil, errInvalidYAMLTable
+				}
+				record = fields
+			default:
+				field, ok := parseYAMLField(item)
+				if !ok {
+					return nil, errInvalidYAMLTable
+				}
+				record = []yamlField{field}
+			}
+			records = append(records, record)
+			continue
+		}
+		// fields of a mapping must be aligned, nested values are not supported
+		leading := len(line) - len(strings.TrimLeft(line, " "))
+		if indent < 0 {
+			indent = leading
+		}
+		if len(records) == 0 || leading == 0 || leading != indent {
+			return nil, errInvalidYAMLTable
+		}
+		field, ok := parseYAMLField(trimmed)
+		if !ok {
+			return nil, errInvalidYAMLTable
+		}
+		records[len(records)-1] = append(records[len(records)-1], field)
+	}
+	if len(records) == 0 {
+		return nil, errInvalidYAMLTable
+	}
+	return records, nil
+}
+
+// A YAMLTableConfig struct is a data structure that holds configuration of the
+// YAMLTable extension.
+type YAMLTableConfig struct {
+	// ColumnOrder is a list of keys rendered as columns.
+	// If ColumnOrder is empty, keys of the first mapping are followed by
+	// keys those appear in other mappings.
+	ColumnOrder []string
+}
+
+const optYAMLTableColumnOrder parser.OptionName = "YAMLTableColumnOrder"
+
+// SetOption implements SetOptioner.
+func (c *YAMLTableConfig) SetOption(name parser.OptionName, value interface{}) {
+	switch name {
+	case optYAMLTableColumnOrder:
+		c.ColumnOrder = value.([]string)
+	}
+}
+
+// A YAMLTableOption interface sets options for the YAMLTable extension.
+type YAMLTableOption interface {
+	parser.Option
+	SetYAMLTableOption(*YAMLTableConfig)
+}
+
+type withYAMLTableColumnOrder struct {
+	value []string
+}
+
+func (o *withYAMLTableColumnOrder) SetParserOption(c *parser.Config) {
+	c.Options[optYAMLTableColumnOrder] = o.value
+}
+
+func (o *withYAMLTableColumnOrder) SetYAMLTableOption(c *YAMLTableConfig) {
+	c.ColumnOrder = o.value
+}
+
+// WithYAMLTableColumnOrder is a functional option that specify keys
+// rendered as columns in order. Other keys are not rendered.
+func WithYAMLTableColumnOrder(value []string) YAMLTableOption {
+	return &withYAMLTableColumnOrder{value}
+}
+
+type yamlTableASTTransformer struct {
+	YAMLTableConfig
+}
+
+// NewYAMLTableASTTransformer returns a new parser.ASTTransformer that
+// converts fenced code blocks those language is 'yaml-table' into tables.
+func NewYAMLTableASTTransformer(opts ...YAMLTableOption) parser.ASTTransformer {
+	t := &yamlTableASTTransformer{}
+	for _, o := range opts {
+		o.SetYAMLTableOption(&t.YAMLTableConfig)
+	}
+	return t
+}
+
+func (a *yamlTableASTTransformer) columns(records [][]yamlField) []string {
+	if len(a.ColumnOrder) != 0 {
+		return a.ColumnOrder
+	}
+	var columns []string
+	seen := map[string]bool{}
+	for _, record := range records {
+		for _, field := range record {
+			if !seen[field.key] {
+				seen[field.key] = true
+				columns = append(columns, field.key)
+			}
+		}
+	}
+	return columns
+}
+
+func (a *yamlTableASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	for _, block := range fencedCodeBlocks(node, source, "yaml-table") {
+		records, err := parseYAMLTable(string(linesValue(block, source)))
+		if err != nil {
+			continue
+		}
+		columns := a.columns(records)
+		if len(columns) == 0 {
+			continue
+		}
+		rows := [][]string{columns}
+		for _, record := range records {
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				for _, field := range record {
+					if field.key == column {
+						row[i] = field.value
+					}
+				}
+			}
+			rows = append(rows, row)
+		}
+		block.Parent().ReplaceChild(block.Parent(), block, newTableFromRecords(rows, true))
+	}
+}
+
+type yamlTable struct {
+	options []YAMLTableOption
+}
+
+// YAMLTable is an extension that converts fenced code blocks those
+// language is 'yaml-table' and contents are a YAML sequence of mappings
+// into tables. Keys of mappings are used as headers. Tables are rendered
+// by a renderer for tables like the one of the Table extension.
+var YAMLTable = &yamlTable{}
+
+// NewYAMLTable returns a new extension with given options.
+func NewYAMLTable(opts ...YAMLTableOption) goldmark.Extender {
+	return &yamlTable{
+		options: opts,
+	}
+}
+
+func (e *yamlTable) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewYAMLTableASTTransformer(e.options...), 999),
+	))
+}
@@ -0,0 +1,45 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestYAMLTable(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Table,
+			YAMLTable,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/yaml_table.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestYAMLTableColumnOrder(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Table,
+			NewYAMLTable(WithYAMLTableColumnOrder([]string{"b", "a"})),
+		),
+	)
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:       1,
+		Markdown: "```yaml-table\n- a: 1\n  b: 2\n  c: 3\n```",
+		Expected: `<table>
+<thead>
+<tr>
+<th>b</th>
+<th>a</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>2</td>
+<td>1</td>
+</tr>
+</tbody>
+</table>`,
+	}, t)
+}
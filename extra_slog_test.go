@@ -0,0 +1,12 @@
+//go:build go1.21
+// +build go1.21
+
+package goldmark_test
+
+import (
+	"log/slog"
+
+	"github.com/yuin/goldmark/parser"
+)
+
+var _ parser.DebugLogger = (*slog.Logger)(nil)
@@ -2,21 +2,32 @@ package goldmark_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	. "github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 var testTimeoutMultiplier = 1.0
 
+// goldenDir is a directory that holds golden files of testutil.GoldenTest.
+const goldenDir = "_test/golden"
+
 func init() {
 	m, err := strconv.ParseFloat(os.Getenv("GOLDMARK_TEST_TIMEOUT_MULTIPLIER"), 64)
 	if err == nil {
@@ -92,10 +103,178 @@ func TestAutogeneratedIDs(t *testing.T) {
 	if err != nil {
 		t.Error(err.Error())
 	}
-	if b.String() != `<h1 id="my-id">Title1</h1>
-<h2 id="my-id">Title2</h2>
+	testutil.GoldenTest(t, goldenDir, "autogenerated-ids", b.String())
+}
+
+func TestDuplicatedHeadingIDs(t *testing.T) {
+	markdown := New(WithParserOptions(parser.WithAutoHeadingID()))
+	cases := []struct {
+		source   string
+		options  []parser.ContextOption
+		expected string
+	}{
+		{
+			"# Intro\n# Intro\n# Intro",
+			nil,
+			"<h1 id=\"intro\">Intro</h1>\n<h1 id=\"intro-1\">Intro</h1>\n<h1 id=\"intro-2\">Intro</h1>\n",
+		},
+		{
+			"# Intro\n# INTRO\n# intro",
+			[]parser.ContextOption{parser.WithIDSuffixStart(2)},
+			"<h1 id=\"intro\">Intro</h1>\n<h1 id=\"intro-2\">INTRO</h1>\n<h1 id=\"intro-3\">intro</h1>\n",
+		},
+		{
+			// generated ids do not collide with headings like 'Intro 2'
+			"# Intro 2\n# Intro\n# Intro",
+			[]parser.ContextOption{parser.WithIDSuffixStart(2)},
+			"<h1 id=\"intro-2\">Intro 2</h1>\n<h1 id=\"intro\">Intro</h1>\n<h1 id=\"intro-3\">Intro</h1>\n",
+		},
+	}
+	for i, c := range cases {
+		// ids are scoped per document
+		for j := 0; j < 2; j++ {
+			var b bytes.Buffer
+			ctx := parser.NewContext(c.options...)
+			if err := markdown.Convert([]byte(c.source), &b, parser.WithContext(ctx)); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != c.expected {
+				t.Errorf("%d: expected %q, but got %q", i, c.expected, b.String())
+			}
+		}
+	}
+	for i := 0; i < 2; i++ {
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte("# Intro\n# Intro"), &b); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != "<h1 id=\"intro\">Intro</h1>\n<h1 id=\"intro-1\">Intro</h1>\n" {
+			t.Errorf("ids are not reset between documents: %q", b.String())
+		}
+	}
+}
+
+func TestParserPool(t *testing.T) {
+	pool := parser.NewParserPool(
+		parser.WithBlockParsers(parser.DefaultBlockParsers()...),
+		parser.WithInlineParsers(parser.DefaultInlineParsers()...),
+		parser.WithParagraphTransformers(parser.DefaultParagraphTransformers()...),
+		parser.WithAutoHeadingID(),
+	)
+	markdown := New()
+	render := func(source string) string {
+		p := pool.Get()
+		defer pool.Put(p)
+		markdown.SetParser(p)
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte(source), &b); err != nil {
+			t.Fatal(err)
+		}
+		return b.String()
+	}
+	for i := 0; i < 3; i++ {
+		if out := render("# Title\n\n[a]\n\n[a]: /url"); out != `<h1 id="title">Title</h1>
+<p><a href="/url">a</a></p>
 ` {
-		t.Errorf("%s\n---------\n%s", source, b.String())
+			t.Errorf("unexpected output: %s", out)
+		}
+		if out := render("# Title\n\n[a]"); out != `<h1 id="title">Title</h1>
+<p>[a]</p>
+` {
+			t.Errorf("references are not reset: %s", out)
+		}
+	}
+}
+
+type resettableIDs struct {
+	myIDs
+	resets int
+}
+
+func (s *resettableIDs) Reset() {
+	s.resets++
+}
+
+func TestParserPoolIDs(t *testing.T) {
+	parserOptions := []parser.Option{
+		parser.WithBlockParsers(parser.DefaultBlockParsers()...),
+		parser.WithInlineParsers(parser.DefaultInlineParsers()...),
+		parser.WithParagraphTransformers(parser.DefaultParagraphTransformers()...),
+		parser.WithAutoHeadingID(),
+	}
+	render := func(pool *parser.ParserPool) string {
+		p := pool.Get()
+		defer pool.Put(p)
+		markdown := New(WithParser(p))
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte("# Intro\n# Intro"), &b); err != nil {
+			t.Fatal(err)
+		}
+		return b.String()
+	}
+
+	pool := parser.NewParserPoolWithContextOptions(
+		[]parser.ContextOption{parser.WithIDSuffixStart(2)}, parserOptions...)
+	for i := 0; i < 3; i++ {
+		if out := render(pool); out != "<h1 id=\"intro\">Intro</h1>\n<h1 id=\"intro-2\">Intro</h1>\n" {
+			t.Errorf("suffix start is not kept: %q", out)
+		}
+	}
+
+	ids := &resettableIDs{}
+	pool = parser.NewParserPoolWithContextOptions(
+		[]parser.ContextOption{parser.WithIDs(ids)}, parserOptions...)
+	for i := 0; i < 3; i++ {
+		if out := render(pool); out != "<h1 id=\"my-id\">Intro</h1>\n<h1 id=\"my-id\">Intro</h1>\n" {
+			t.Errorf("ids are not kept: %q", out)
+		}
+	}
+	if ids.resets == 0 {
+		t.Errorf("ids are not reset")
+	}
+}
+
+func parallelRenderingSource(lines int) []byte {
+	var b bytes.Buffer
+	for i := 0; b.Len() == 0 || strings.Count(b.String(), "\n") < lines; i++ {
+		b.WriteString("## Section " + strconv.Itoa(i) + "\n\n")
+		b.WriteString("Some *emphasis* and `code` with [a link](/url" + strconv.Itoa(i) + ").\n\n")
+		b.WriteString("```go\nfunc main() {\n\tprintln(\"hello\")\n}\n```\n\n")
+		b.WriteString("- item 1\n- item 2\n\n")
+	}
+	return b.Bytes()
+}
+
+func TestParallelRendering(t *testing.T) {
+	source := parallelRenderingSource(1000)
+	var expected bytes.Buffer
+	if err := New().Convert(source, &expected); err != nil {
+		t.Fatal(err)
+	}
+	markdown := New(WithRendererOptions(renderer.WithParallelism(4)))
+	var actual bytes.Buffer
+	if err := markdown.Convert(source, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual.String() != expected.String() {
+		t.Errorf("parallel rendering result differs from sequential rendering")
+	}
+}
+
+func BenchmarkParallelRendering(b *testing.B) {
+	source := parallelRenderingSource(10000)
+	for _, n := range []int{1, 4} {
+		markdown := New(WithRendererOptions(renderer.WithParallelism(n)))
+		doc := markdown.Parser().Parse(text.NewReader(source))
+		b.Run("Parallelism"+strconv.Itoa(n), func(b *testing.B) {
+			var out bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				out.Reset()
+				if err := markdown.Renderer().Render(&out, source, doc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
 	}
 }
 
@@ -209,13 +388,1063 @@ func TestDangerousURLStringCase(t *testing.T) {
 
 	source := []byte(`[Basic](javascript:alert('Basic'))
 [CaseInsensitive](JaVaScRiPt:alert('CaseInsensitive'))
-`)
-	expected := []byte(`<p><a href="">Basic</a>
-<a href="">CaseInsensitive</a></p>
 `)
 	var b bytes.Buffer
 	_ = markdown.Convert(source, &b)
-	if !bytes.Equal(expected, b.Bytes()) {
-		t.Error("Dangerous URL should ignore cases:\n" + string(testutil.DiffPretty(expected, b.Bytes())))
+	testutil.GoldenTest(t, goldenDir, "dangerous-url-string-case", b.String())
+}
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+	source := []byte("# Title\n\n[link][ref] *emphasis*\n\n[ref]: /url \"title\"\n")
+	path := dir + "/test.md"
+	if err := os.WriteFile(path, source, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	markdown := New()
+	var expected bytes.Buffer
+	if err := markdown.Convert(source, &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	node, f, err := ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := markdown.Renderer().Render(&b, f.Bytes(), node); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Errorf("Close should be idempotent: %v", err)
+	}
+	if b.String() != expected.String() {
+		t.Errorf("%q\n---- Expected ----\n%s\n---- Actual ----\n%s", source, expected.String(), b.String())
+	}
+
+	// line endings are normalized like Convert
+	crlf := dir + "/crlf.md"
+	if err := os.WriteFile(crlf, bytes.ReplaceAll(source, []byte("\n"), []byte("\r\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	node, f, err = ParseFile(crlf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(f.Bytes(), source) {
+		t.Errorf("line endings should be normalized: %q", f.Bytes())
+	}
+	b.Reset()
+	if err := markdown.Renderer().Render(&b, f.Bytes(), node); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	if b.String() != expected.String() {
+		t.Errorf("expected %q, but got %q", expected.String(), b.String())
+	}
+
+	empty := dir + "/empty.md"
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	node, f, err = ParseFile(empty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.HasChildren() {
+		t.Error("an empty file should have no blocks")
+	}
+	_ = f.Close()
+
+	if _, _, err := ParseFile(dir + "/missing.md"); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, but got %v", err)
+	}
+}
+
+func TestURLSanitizer(t *testing.T) {
+	markdown := New(WithRendererOptions(
+		html.WithUnsafe(),
+		html.WithURLSanitizer(html.DefaultURLSanitizer),
+	))
+	source := []byte(`[a](https://example.com/) [b](/path?q=a:b) [c](#a:b) [d](MAILTO:a@example.com)
+[e](javascript:alert(1)) [f](data:text/html,x) ![g](data:image/png;base64,AAA) [h](java&#9;script:alert(1))
+<vbscript:msgbox> <a@example.com>
+`)
+	var b bytes.Buffer
+	if err := markdown.Convert(source, &b); err != nil {
+		t.Fatal(err)
+	}
+	testutil.GoldenTest(t, goldenDir, "url-sanitizer", b.String())
+
+	markdown = New(WithRendererOptions(
+		html.WithURLSanitizer(html.DefaultURLSanitizer),
+		html.WithURLSanitizerFallback("#"),
+	))
+	b.Reset()
+	if err := markdown.Convert([]byte("[a](ssh://example.com)\n"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "<p><a href=\"#\">a</a></p>\n" {
+		t.Errorf("fallback URL should be used: %s", b.String())
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	source := []byte("# Title\n\n- *a*\n- **b**\n")
+	var b bytes.Buffer
+
+	markdown := New(WithParserOptions(parser.WithMaxDocumentSize(int64(len(source)))))
+	if err := markdown.Convert(source, &b); err != nil {
+		t.Errorf("a document within the limit should be converted: %v", err)
+	}
+	markdown = New(WithParserOptions(parser.WithMaxDocumentSize(int64(len(source) - 1))))
+	b.Reset()
+	if err := markdown.Convert(source, &b); err != parser.ErrDocumentTooLarge {
+		t.Errorf("expected ErrDocumentTooLarge, but got %v", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("nothing should be rendered, but got %q", b.String())
+	}
+
+	// Document, Heading, Text, List, 2 ListItems, 2 TextBlocks,
+	// 2 Emphasis and 2 Texts
+	markdown = New(WithParserOptions(parser.WithMaxNodeCount(12)))
+	b.Reset()
+	if err := markdown.Convert(source, &b); err != nil {
+		t.Errorf("a document within the limit should be converted: %v", err)
+	}
+	markdown = New(WithParserOptions(parser.WithMaxNodeCount(11)))
+	pc := parser.NewContext()
+	doc := markdown.Parser().Parse(text.NewReader(source), parser.WithContext(pc))
+	if doc.HasChildren() {
+		t.Error("an empty document should be returned")
+	}
+	if errs := parser.Errors(pc); len(errs) != 1 || errs[0] != parser.ErrTooManyNodes {
+		t.Errorf("expected ErrTooManyNodes, but got %v", errs)
+	}
+	markdown = New(WithParserOptions(parser.WithMaxNodeCount(5)))
+	if err := markdown.Convert(source, &b); err != parser.ErrTooManyNodes {
+		t.Errorf("expected ErrTooManyNodes, but got %v", err)
+	}
+
+	// parsing stops as soon as the limit is exceeded
+	logger := &testDebugLogger{}
+	markdown = New(WithParserOptions(parser.WithMaxNodeCount(10)), WithDebugLogger(logger))
+	if err := markdown.Convert([]byte(strings.Repeat("- a\n", 1000)), &b); err != parser.ErrTooManyNodes {
+		t.Errorf("expected ErrTooManyNodes, but got %v", err)
+	}
+	if len(logger.messages) > 10 {
+		t.Errorf("expected at most 10 blocks are opened, but got %d", len(logger.messages))
+	}
+}
+
+func TestEntityExpansionLimit(t *testing.T) {
+	source := []byte("&amp; [&lt;](/a&amp;b \"&gt;\")\n\n> `&amp;` &ouml;\n\nlast &amp;\n")
+	markdown := New(WithParserOptions(parser.WithEntityExpansionLimit(6)))
+	var b bytes.Buffer
+	if err := markdown.Convert(source, &b); err != nil {
+		t.Errorf("a document within the limit should be converted: %v", err)
+	}
+
+	markdown = New(WithParserOptions(parser.WithEntityExpansionLimit(5)))
+	pc := parser.NewContext()
+	doc := markdown.Parser().Parse(text.NewReader(source), parser.WithContext(pc))
+	if errs := parser.Errors(pc); len(errs) != 1 || errs[0] != parser.ErrTooManyEntityReferences {
+		t.Errorf("expected ErrTooManyEntityReferences, but got %v", errs)
+	}
+	b.Reset()
+	if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+		t.Fatal(err)
+	}
+	// the document should be truncated before the last paragraph
+	testutil.GoldenTest(t, goldenDir, "entity-expansion-limit", b.String())
+
+	// Convert writes the truncated document and returns the error
+	var converted bytes.Buffer
+	if err := markdown.Convert(source, &converted); !errors.Is(err, parser.ErrTooManyEntityReferences) {
+		t.Errorf("expected ErrTooManyEntityReferences, but got %v", err)
+	}
+	if converted.String() != b.String() {
+		t.Errorf("expected the truncated result %q, but got %q", b.String(), converted.String())
+	}
+	out, err := ConvertString(string(source), WithParserOptions(parser.WithEntityExpansionLimit(5)))
+	if !errors.Is(err, parser.ErrTooManyEntityReferences) || out != b.String() {
+		t.Errorf("expected the truncated result and ErrTooManyEntityReferences, but got %q, %v", out, err)
+	}
+}
+
+func TestSafeMode(t *testing.T) {
+	source := []byte(`<div>block</div>
+
+&lt;script&gt; \&amp; [a](/url "&quot;") <b>inline</b> &#60;
+`)
+	cases := []struct {
+		name    string
+		options []renderer.Option
+	}{
+		{"safe-mode", []renderer.Option{html.WithSafeMode()}},
+		// WithSafeMode overrides WithUnsafe regardless of the order
+		{"safe-mode", []renderer.Option{html.WithUnsafe(), html.WithSafeMode()}},
+		{"safe-mode", []renderer.Option{html.WithSafeMode(), html.WithUnsafe()}},
+		{"safe-mode-default", nil},
+	}
+	for _, c := range cases {
+		markdown := New(WithRendererOptions(c.options...))
+		var b bytes.Buffer
+		if err := markdown.Convert(source, &b); err != nil {
+			t.Fatal(err)
+		}
+		testutil.GoldenTest(t, goldenDir, c.name, b.String())
+	}
+}
+
+type scriptRenderer struct {
+	html.Config
+}
+
+func (r *scriptRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindThematicBreak, func(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			_, _ = w.WriteString("<script")
+			r.RenderCSPNonce(w, n)
+			_, _ = w.WriteString("></script>\n")
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+func TestCSPNonce(t *testing.T) {
+	source := []byte("***\n\n<script>alert(1)</script>\n")
+	markdown := New(WithRendererOptions(
+		html.WithUnsafe(),
+		html.WithCSPNonce(`abc"`),
+		renderer.WithNodeRenderers(util.Prioritized(&scriptRenderer{html.NewConfig()}, 1)),
+	))
+	var b bytes.Buffer
+	if err := markdown.Convert(source, &b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "<script nonce=\"abc&quot;\"></script>\n<script>alert(1)</script>\n"
+	if b.String() != expected {
+		t.Errorf("%s", testutil.DiffPretty([]byte(expected), b.Bytes()))
+	}
+
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	doc.OwnerDocument().AddMeta(html.CSPNonceMetaKey, "xyz")
+	b.Reset()
+	if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+		t.Fatal(err)
+	}
+	expected = "<script nonce=\"xyz\"></script>\n<script>alert(1)</script>\n"
+	if b.String() != expected {
+		t.Errorf("%s", testutil.DiffPretty([]byte(expected), b.Bytes()))
+	}
+}
+
+// adversarialAutoLinkCases returns sources those may take quadratic time
+// with their expected results.
+func adversarialAutoLinkCases(n int) [][2]string {
+	email := strings.Repeat("a-", n)
+	attrs := strings.Repeat(" b='c'", n)
+	links := strings.Repeat("<a@a.", n)
+	return [][2]string{
+		{"<a@" + email + "\n", "<p>&lt;a@" + email + "</p>\n"},
+		{"<a" + attrs + "\n", "<p>&lt;a" + attrs + "</p>\n"},
+		{links, "<p>" + strings.ReplaceAll(links, "<", "&lt;") + "</p>\n"},
+	}
+}
+
+func TestAdversarialAutoLink(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping a test with large inputs in short mode")
+	}
+	markdown := New(WithRendererOptions(
+		html.WithXHTML(),
+		html.WithUnsafe(),
+	))
+	for _, c := range adversarialAutoLinkCases(50000) {
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte(c[0]), &b); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != c[1] {
+			t.Errorf("unexpected result for %q...: %q...", c[0][:10], b.String()[:40])
+		}
+	}
+}
+
+func BenchmarkAdversarialAutoLink(b *testing.B) {
+	markdown := New(WithRendererOptions(
+		html.WithXHTML(),
+		html.WithUnsafe(),
+	))
+	for _, c := range adversarialAutoLinkCases(50000) {
+		source := []byte(c[0])
+		b.Run(c[0][:10], func(b *testing.B) {
+			var out bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				out.Reset()
+				_ = markdown.Convert(source, &out)
+			}
+		})
+	}
+}
+
+func TestConvertString(t *testing.T) {
+	actual, err := ConvertString("# Title\n\n<b>raw</b>\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<h1>Title</h1>\n<p><!-- raw HTML omitted -->raw<!-- raw HTML omitted --></p>\n"; actual != expected {
+		t.Errorf("expected %q, but got %q", expected, actual)
+	}
+	actual = MustConvertString("<b>raw</b>\n", WithRendererOptions(html.WithUnsafe()))
+	if expected := "<p><b>raw</b></p>\n"; actual != expected {
+		t.Errorf("expected %q, but got %q", expected, actual)
+	}
+
+	_, err = ConvertString("too large", WithParserOptions(parser.WithMaxDocumentSize(1)))
+	if err != parser.ErrDocumentTooLarge {
+		t.Errorf("expected ErrDocumentTooLarge, but got %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("MustConvertString should panic")
+		}
+	}()
+	MustConvertString("too large", WithParserOptions(parser.WithMaxDocumentSize(1)))
+}
+
+type initExtension struct {
+	ctx      context.Context
+	err      error
+	extended bool
+}
+
+func (e *initExtension) Extend(m Markdown) {
+	e.extended = true
+}
+
+func (e *initExtension) ExtensionInit(ctx context.Context) error {
+	if !e.extended {
+		return errors.New("ExtensionInit should be called after Extend")
+	}
+	e.ctx = ctx
+	return e.err
+}
+
+func TestNewWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ext := &initExtension{}
+	markdown, err := NewWithContext(ctx, WithExtensions(ext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ext.ctx != ctx {
+		t.Error("the context should be given to the extension")
+	}
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte("a"), &b); err != nil || b.String() != "<p>a</p>\n" {
+		t.Errorf("unexpected result: %q, %v", b.String(), err)
+	}
+
+	failed := errors.New("failed")
+	if _, err := NewWithContext(ctx, WithExtensions(&initExtension{err: failed})); err != failed {
+		t.Errorf("expected an error of the extension, but got %v", err)
+	}
+}
+
+func TestNewWithBufferPool(t *testing.T) {
+	gets := 0
+	pool := &sync.Pool{New: func() interface{} {
+		gets++
+		return &bytes.Buffer{}
+	}}
+	markdown := NewWithBufferPool(pool, WithRendererOptions(html.WithXHTML()))
+	for i, source := range []string{"# a\n\nb  \nc", "d"} {
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte(source), &b); err != nil {
+			t.Fatal(err)
+		}
+		if expected := MustConvertString(source, WithRendererOptions(html.WithXHTML())); b.String() != expected {
+			t.Errorf("%d: expected %q, but got %q", i, expected, b.String())
+		}
+	}
+	if gets == 0 {
+		t.Error("buffers should be taken from the pool")
+	}
+
+	gets = 0
+	pool = &sync.Pool{New: pool.New}
+	variant := WithOptions(NewWithBufferPool(pool), WithRendererOptions(html.WithHardWraps()))
+	var b bytes.Buffer
+	if err := variant.Convert([]byte("a\nb"), &b); err != nil || b.String() != "<p>a<br>\nb</p>\n" {
+		t.Errorf("unexpected result: %q, %v", b.String(), err)
+	}
+	if gets == 0 {
+		t.Error("WithOptions should keep the pool")
+	}
+
+	// pools without New are allowed
+	markdown = NewWithBufferPool(&sync.Pool{})
+	b.Reset()
+	if err := markdown.Convert([]byte("a"), &b); err != nil || b.String() != "<p>a</p>\n" {
+		t.Errorf("unexpected result: %q, %v", b.String(), err)
+	}
+}
+
+type countExtension struct {
+	count int
+}
+
+func (e *countExtension) Extend(m Markdown) {
+	e.count++
+}
+
+type extenderFunc func(Markdown)
+
+func (f extenderFunc) Extend(m Markdown) {
+	f(m)
+}
+
+func TestWithoutExtension(t *testing.T) {
+	a, b := &countExtension{}, &countExtension{}
+	called := false
+	f := extenderFunc(func(Markdown) { called = true })
+	options := []Option{WithExtension(a), WithExtensions(b, f)}
+	options = append(options, WithoutExtension(a), WithoutExtension(f))
+	_ = New(options...)
+	if a.count != 0 {
+		t.Error("a removed extension should not be applied")
+	}
+	if b.count != 1 {
+		t.Error("an extension should be applied once")
+	}
+	if !called {
+		t.Error("an uncomparable extension should not be removed")
+	}
+}
+
+type testDebugLogger struct {
+	messages []string
+}
+
+func (l *testDebugLogger) Debug(msg string, args ...interface{}) {
+	l.messages = append(l.messages, strings.TrimSpace(fmt.Sprintln(append([]interface{}{msg}, args...)...)))
+}
+
+func TestDebugLogger(t *testing.T) {
+	logger := &testDebugLogger{}
+	markdown := New(WithDebugLogger(logger))
+	source := []byte("# Title\n\n*a* [b]\n\n[b]: /url\n")
+	var b bytes.Buffer
+	if err := markdown.Convert(source, &b); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"block parser opened a block parser *parser.atxHeadingParser kind Heading offset 0 line # Title",
+		"block parser opened a block parser *parser.paragraphParser kind Paragraph offset 9 line *a* [b]",
+		"block parser opened a block parser *parser.paragraphParser kind Paragraph offset 18 line [b]: /url",
+		"link reference definition label b destination /url title",
+		"inline parser matched a span parser *parser.emphasisParser kind Delimiter offset 9 span *",
+		"inline parser matched a span parser *parser.emphasisParser kind Delimiter offset 11 span *",
+		"inline parser matched a span parser *parser.linkParser kind LinkLabelState offset 13 span [",
+		"inline parser matched a span parser *parser.linkParser kind Link offset 15 span ]",
+	}
+	if strings.Join(logger.messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected logs:\n%s", strings.Join(logger.messages, "\n"))
+	}
+
+	var nilLogger *testDebugLogger
+	markdown = New(WithDebugLogger(nilLogger))
+	if err := markdown.Convert(source, &b); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConvertFileToFile(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/src.md"
+	dst := dir + "/dst.html"
+	if err := os.WriteFile(src, []byte("# Title\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := ConvertFile(src, &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "<h1>Title</h1>\n" {
+		t.Errorf("unexpected result: %q", b.String())
+	}
+
+	if err := os.WriteFile(dst, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ConvertFileToFile(src, dst, WithParserOptions(parser.WithAutoHeadingID())); err != nil {
+		t.Fatal(err)
+	}
+	bs, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != "<h1 id=\"title\">Title</h1>\n" {
+		t.Errorf("unexpected result: %q", bs)
+	}
+
+	if err := ConvertFileToFile(dir+"/missing.md", dst); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, but got %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("temporary files should be removed: %v", entries)
+	}
+	if bs, _ := os.ReadFile(dst); string(bs) != "<h1 id=\"title\">Title</h1>\n" {
+		t.Errorf("dst should not be changed on errors: %q", bs)
+	}
+
+	// permissions of existing files are kept
+	if fi, err := os.Stat(dst); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode().Perm() != 0o600 {
+		t.Errorf("permissions should be kept: %v", fi.Mode())
+	}
+	// new files are created like os.Create
+	created, err := os.Create(dir + "/created.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = created.Close()
+	expected, err := os.Stat(created.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ConvertFileToFile(src, dir+"/new.html"); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(dir + "/new.html"); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode().Perm() != expected.Mode().Perm() {
+		t.Errorf("expected %v, but got %v", expected.Mode(), fi.Mode())
+	}
+}
+
+func TestWithOptions(t *testing.T) {
+	ext := &countExtension{}
+	base := New(WithExtensions(ext), WithRendererOptions(html.WithXHTML()))
+	variant := WithOptions(base, WithParserOptions(parser.WithAutoHeadingID()), WithRendererOptions(html.WithHardWraps()))
+	if ext.count != 2 {
+		t.Errorf("extensions should be applied to the variant, but applied %d times", ext.count)
+	}
+	source := []byte("# Title\na\nb\n")
+	var b bytes.Buffer
+	if err := variant.Convert(source, &b); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<h1 id=\"title\">Title</h1>\n<p>a<br />\nb</p>\n"; b.String() != expected {
+		t.Errorf("unexpected result of the variant: %q", b.String())
+	}
+	b.Reset()
+	if err := base.Convert(source, &b); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<h1>Title</h1>\n<p>a\nb</p>\n"; b.String() != expected {
+		t.Errorf("the base should not be changed: %q", b.String())
+	}
+
+	// options given to New are copied
+	options := []Option{WithRendererOptions(html.WithXHTML()), WithRendererOptions(html.WithXHTML())}
+	aliased := New(options[:1]...)
+	options[0] = WithRendererOptions(html.WithHardWraps())
+	b.Reset()
+	if err := WithOptions(aliased).Convert([]byte("a  \nb"), &b); err != nil || b.String() != "<p>a<br />\nb</p>\n" {
+		t.Errorf("unexpected result: %q, %v", b.String(), err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("WithOptions should panic with Markdowns those are not created by New")
+		}
+	}()
+	WithOptions(struct{ Markdown }{base})
+}
+
+func TestRendererMiddleware(t *testing.T) {
+	var kinds []string
+	markdown := New(WithRendererOptions(
+		renderer.WithMiddleware(func(w util.BufWriter, source []byte, n ast.Node, entering bool,
+			next renderer.NodeRendererFunc) (ast.WalkStatus, error) {
+			if entering {
+				kinds = append(kinds, n.Kind().String())
+			}
+			return next(w, source, n, entering)
+		}),
+		renderer.WithMiddleware(func(w util.BufWriter, source []byte, n ast.Node, entering bool,
+			next renderer.NodeRendererFunc) (ast.WalkStatus, error) {
+			if n.Kind() != ast.KindImage {
+				return next(w, source, n, entering)
+			}
+			if entering {
+				_, _ = w.WriteString("<figure>")
+			}
+			s, err := next(w, source, n, entering)
+			if entering && s == ast.WalkSkipChildren {
+				_, _ = w.WriteString("</figure>")
+			}
+			return s, err
+		}),
+	))
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte("a ![b](c.png)\n"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<p>a <figure><img src=\"c.png\" alt=\"b\"></figure></p>\n"; b.String() != expected {
+		t.Errorf("unexpected result: %q", b.String())
+	}
+	if expected := "Document Paragraph Text Image"; strings.Join(kinds, " ") != expected {
+		t.Errorf("middlewares should be called for every node: %v", kinds)
+	}
+}
+
+func TestEntityResolver(t *testing.T) {
+	resolver := func(name string) ([]byte, bool) {
+		switch name {
+		case "company":
+			return []byte("<ACME>"), true
+		case "amp":
+			return []byte("and"), true
+		}
+		return nil, false
+	}
+	source := []byte("&company; &amp; &lt; \\&company; &unknown;\n")
+	cases := []struct {
+		name    string
+		options []renderer.Option
+	}{
+		{"entity-resolver", []renderer.Option{html.WithEntityResolver(resolver)}},
+		{"entity-resolver", []renderer.Option{html.WithEntityResolver(resolver), html.WithXHTML()}},
+		// resolvers are ignored in safe mode
+		{"entity-resolver-safe-mode", []renderer.Option{html.WithEntityResolver(resolver), html.WithSafeMode()}},
+	}
+	for _, c := range cases {
+		markdown := New(WithRendererOptions(c.options...))
+		var b bytes.Buffer
+		if err := markdown.Convert(source, &b); err != nil {
+			t.Fatal(err)
+		}
+		testutil.GoldenTest(t, goldenDir, c.name, b.String())
+	}
+
+	r := html.NewRenderer(html.WithWriter(html.NewWriter(html.WithEntityResolverWriter(resolver))))
+	markdown := New(WithRenderer(renderer.NewRenderer(renderer.WithNodeRenderers(util.Prioritized(r, 1000)))))
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte("&company;"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "<p>&lt;ACME&gt;</p>\n" {
+		t.Errorf("unexpected result: %q", b.String())
+	}
+}
+
+func TestLoad(t *testing.T) {
+	markdown := New()
+	wt, err := Load(markdown, []byte("# Title\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, w := io.Pipe()
+	go func() {
+		_, err := wt.WriteTo(w)
+		_ = w.CloseWithError(err)
+	}()
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != "<h1>Title</h1>\n" {
+		t.Errorf("unexpected result: %q", bs)
+	}
+	var b bytes.Buffer
+	n, err := wt.WriteTo(&b)
+	if err != nil || n != int64(b.Len()) {
+		t.Errorf("WriteTo should return a number of written bytes: %d, %v", n, err)
+	}
+
+	_, err = Load(New(WithParserOptions(parser.WithMaxDocumentSize(1))), []byte("too large"))
+	if err != parser.ErrDocumentTooLarge {
+		t.Errorf("expected ErrDocumentTooLarge, but got %v", err)
+	}
+}
+
+func TestNULCharacters(t *testing.T) {
+	markdown := New(WithParserOptions(parser.WithAttribute()))
+	source := []byte("# h {data-x=\"a\x00b\"}\n\nfoo\x00bar `c\x00d`\n")
+	expected := "<h1 data-x=\"a�b\">h</h1>\n<p>foo�bar <code>c�d</code></p>\n"
+
+	// parses the source without Convert, that normalizes NUL characters
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	var b bytes.Buffer
+	if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected {
+		t.Errorf("expected %q, but got %q", expected, b.String())
+	}
+}
+
+func TestLenientAutolinks(t *testing.T) {
+	source := "<https://very-long\\\nurl.com/path> <foo\\\n@example.com>"
+	cases := []struct {
+		name     string
+		options  []parser.Option
+		expected string
+	}{
+		{"default", nil, "<p>&lt;https://very-long<br>\nurl.com/path&gt; &lt;foo<br>\n@example.com&gt;</p>"},
+		{"lenient", []parser.Option{parser.WithLenientAutolinks(true)},
+			`<p><a href="https://very-longurl.com/path">https://very-longurl.com/path</a> ` +
+				`<a href="mailto:foo@example.com">foo@example.com</a></p>`},
+	}
+	for i, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			markdown := New(WithParserOptions(c.options...))
+			testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+				No:       i + 1,
+				Markdown: source,
+				Expected: c.expected,
+			}, t)
+		})
+	}
+}
+
+func TestStrictFenceLength(t *testing.T) {
+	source := "````go\na := 1\n```\n`````\nb := 2\n````\n\n~~~\nc\n~~~\n"
+	cases := []struct {
+		name     string
+		options  []parser.Option
+		expected string
+	}{
+		{"default", nil, "<pre><code class=\"language-go\">a := 1\n```\n</code></pre>\n" +
+			"<p>b := 2</p>\n<pre><code>\n~~~\nc\n~~~\n</code></pre>"},
+		{"strict", []parser.Option{parser.WithStrictFenceLength(true)},
+			"<pre><code class=\"language-go\">a := 1\n```\n`````\nb := 2\n</code></pre>\n" +
+				"<pre><code>c\n</code></pre>"},
+	}
+	for i, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			markdown := New(WithParserOptions(c.options...))
+			testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+				No:       i + 1,
+				Markdown: source,
+				Expected: c.expected,
+			}, t)
+		})
+	}
+}
+
+func TestInlineDelimiter(t *testing.T) {
+	markdown := New(WithParserOptions(
+		parser.WithInlineDelimiter('!', '!', 2, 2, func(int) ast.Node {
+			return ast.NewEmphasis(2)
+		}),
+		parser.WithInlineDelimiter('{', '}', 1, 1, func(int) ast.Node {
+			return ast.NewEmphasis(1)
+		}),
+	))
+	testutil.DoTestCase(markdown, testutil.MarkdownTestCase{
+		No:       1,
+		Markdown: "!!important!! !!!three!!! {emphasized *text*} }not{ ![image](/a.png)",
+		Expected: `<p><strong>important</strong> !!!three!!! <em>emphasized <em>text</em></em> }not{ <img src="/a.png" alt="image"></p>`,
+	}, t)
+}
+
+func TestRenderHook(t *testing.T) {
+	var events []string
+	markdown := New(WithRendererOptions(
+		renderer.WithRenderHook(func(kind ast.NodeKind, entering bool) {
+			if entering {
+				events = append(events, "+"+kind.String())
+			} else {
+				events = append(events, "-"+kind.String())
+			}
+		}),
+	))
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte("a *b*\n"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<p>a <em>b</em></p>\n"; b.String() != expected {
+		t.Errorf("unexpected result: %q", b.String())
+	}
+	expected := "+Document +Paragraph +Text -Text +Emphasis +Text -Text -Emphasis -Paragraph -Document"
+	if actual := strings.Join(events, " "); actual != expected {
+		t.Errorf("expected %q, but got %q", expected, actual)
+	}
+}
+
+func TestLinkReferenceMap(t *testing.T) {
+	source := []byte(`[Foo Bar]: /url "title"
+[foo  bar]: /ignored
+[baz]: <https://example.com>
+
+[foo bar] [unused]
+`)
+	doc := New().Parser().Parse(text.NewReader(source))
+	refs := doc.OwnerDocument().LinkReferenceMap()
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 references, but got %d", len(refs))
+	}
+	ref, ok := refs["foo bar"]
+	if !ok {
+		t.Fatalf("reference 'foo bar' should be found: %v", refs)
+	}
+	if string(ref.Label) != "Foo Bar" || string(ref.Destination) != "/url" || string(ref.Title) != "title" {
+		t.Errorf("unexpected reference: %q %q %q", ref.Label, ref.Destination, ref.Title)
+	}
+	if ref := refs["baz"]; string(ref.Destination) != "https://example.com" {
+		t.Errorf("unexpected destination: %q", ref.Destination)
+	}
+}
+
+func TestCodeClass(t *testing.T) {
+	source := "```go\na\n```\n\n```\nb\n```\n"
+	cases := []struct {
+		option   renderer.Option
+		expected string
+	}{
+		{
+			html.WithCodeLanguagePrefix("lang-"),
+			"<pre><code class=\"lang-go\">a\n</code></pre>\n<pre><code>b\n</code></pre>\n",
+		},
+		{
+			html.WithCodeClassFunc(func(lang string) string {
+				if lang == "go" {
+					return "hljs " + lang + " <x>"
+				}
+				return ""
+			}),
+			"<pre><code class=\"hljs go &lt;x&gt;\">a\n</code></pre>\n<pre><code>b\n</code></pre>\n",
+		},
+		{
+			html.WithCodeClassFunc(func(lang string) string { return "" }),
+			"<pre><code>a\n</code></pre>\n<pre><code>b\n</code></pre>\n",
+		},
+	}
+	for i, c := range cases {
+		actual := MustConvertString(source, WithRendererOptions(c.option))
+		if actual != c.expected {
+			t.Errorf("%d: expected %q, but got %q", i, c.expected, actual)
+		}
+	}
+}
+
+func TestHeadingAnchors(t *testing.T) {
+	source := "# Title\n\n## Sub *title*\n"
+	cases := []struct {
+		option   renderer.Option
+		expected string
+	}{
+		{
+			html.WithHeadingAnchors(html.AnchorLeft, "#"),
+			"<h1 id=\"title\"><a class=\"heading-anchor\" href=\"#title\" aria-hidden=\"true\">#</a> Title</h1>\n" +
+				"<h2 id=\"sub-title\"><a class=\"heading-anchor\" href=\"#sub-title\" aria-hidden=\"true\">#</a> Sub <em>title</em></h2>\n",
+		},
+		{
+			html.WithHeadingAnchors(html.AnchorRight, "¶"),
+			"<h1 id=\"title\">Title <a class=\"heading-anchor\" href=\"#title\" aria-hidden=\"true\">¶</a></h1>\n" +
+				"<h2 id=\"sub-title\">Sub <em>title</em> <a class=\"heading-anchor\" href=\"#sub-title\" aria-hidden=\"true\">¶</a></h2>\n",
+		},
+	}
+	for i, c := range cases {
+		actual := MustConvertString(source,
+			WithParserOptions(parser.WithAutoHeadingID()),
+			WithRendererOptions(c.option))
+		if actual != c.expected {
+			t.Errorf("%d: expected %q, but got %q", i, c.expected, actual)
+		}
+	}
+
+	// headings without ids do not have anchor links
+	actual := MustConvertString(source, WithRendererOptions(html.WithHeadingAnchors(html.AnchorLeft, "#")))
+	if expected := "<h1>Title</h1>\n<h2>Sub <em>title</em></h2>\n"; actual != expected {
+		t.Errorf("expected %q, but got %q", expected, actual)
+	}
+}
+
+func TestVoidElementStyle(t *testing.T) {
+	source := "a  \nb ![i](/i.png)\n\n***\n"
+	cases := []struct {
+		options  []renderer.Option
+		expected string
+	}{
+		{
+			nil,
+			"<p>a<br>\nb <img src=\"/i.png\" alt=\"i\"></p>\n<hr>\n",
+		},
+		{
+			[]renderer.Option{html.WithXHTML()},
+			"<p>a<br />\nb <img src=\"/i.png\" alt=\"i\" /></p>\n<hr />\n",
+		},
+		{
+			[]renderer.Option{html.WithXHTML(), html.WithVoidElementStyle(html.VoidElementHTML5)},
+			"<p>a<br>\nb <img src=\"/i.png\" alt=\"i\"></p>\n<hr>\n",
+		},
+		{
+			[]renderer.Option{html.WithVoidElementStyle(html.VoidElementXHTML)},
+			"<p>a<br />\nb <img src=\"/i.png\" alt=\"i\" /></p>\n<hr />\n",
+		},
+		{
+			[]renderer.Option{html.WithVoidElementStyle(html.VoidElementXMLSelfClose)},
+			"<p>a<br/>\nb <img src=\"/i.png\" alt=\"i\"/></p>\n<hr/>\n",
+		},
+	}
+	for i, c := range cases {
+		actual := MustConvertString(source, WithRendererOptions(c.options...))
+		if actual != c.expected {
+			t.Errorf("%d: expected %q, but got %q", i, c.expected, actual)
+		}
+	}
+}
+
+func TestWrapper(t *testing.T) {
+	source := "# Title\n\ntext\n"
+	body := "<h1>Title</h1>\n<p>text</p>\n"
+	cases := []struct {
+		tag      string
+		class    string
+		attrs    map[string]string
+		expected string
+	}{
+		{"", "markdown-body", nil, body},
+		{"div", "markdown-body", nil, "<div class=\"markdown-body\">\n" + body + "</div>\n"},
+		{"article", "", nil, "<article>\n" + body + "</article>\n"},
+		{"div", "markdown-body", map[string]string{"class": "dark", "id": "doc", "onclick": "x"},
+			"<div class=\"markdown-body dark\" id=\"doc\">\n" + body + "</div>\n"},
+		{"div", "", map[string]string{"class": "dark"}, "<div class=\"dark\">\n" + body + "</div>\n"},
+	}
+	for i, c := range cases {
+		markdown := New(WithRendererOptions(html.WithWrapper(c.tag, c.class)))
+		doc := markdown.Parser().Parse(text.NewReader([]byte(source)))
+		for _, name := range []string{"class", "id", "onclick"} {
+			if v, ok := c.attrs[name]; ok {
+				doc.SetAttributeString(name, []byte(v))
+			}
+		}
+		var b bytes.Buffer
+		if err := markdown.Renderer().Render(&b, []byte(source), doc); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != c.expected {
+			t.Errorf("%d: expected %q, but got %q", i, c.expected, b.String())
+		}
+	}
+}
+
+func TestBlockquoteCite(t *testing.T) {
+	// extracts a link in the last paragraph like '— Author ([Source](url))'
+	extractor := func(blockquote ast.Node, source []byte) string {
+		var cite string
+		if p := blockquote.LastChild(); p != nil {
+			_ = ast.Walk(p, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+				if link, ok := n.(*ast.Link); ok && entering {
+					cite = string(link.Destination)
+					return ast.WalkStop, nil
+				}
+				return ast.WalkContinue, nil
+			})
+		}
+		return cite
+	}
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		{
+			"> Quote\n>\n> — Author ([Source](<https://example.com/a b>))\n",
+			"<blockquote cite=\"https://example.com/a%20b\">\n<p>Quote</p>\n" +
+				"<p>— Author (<a href=\"https://example.com/a%20b\">Source</a>)</p>\n</blockquote>\n",
+		},
+		{
+			"> Quote\n",
+			"<blockquote>\n<p>Quote</p>\n</blockquote>\n",
+		},
+		{
+			"> [Source](javascript:alert(1))\n",
+			"<blockquote>\n<p><a href=\"\">Source</a></p>\n</blockquote>\n",
+		},
+	}
+	for i, c := range cases {
+		actual := MustConvertString(c.source, WithRendererOptions(html.WithBlockquoteCite(extractor)))
+		if actual != c.expected {
+			t.Errorf("%d: expected %q, but got %q", i, c.expected, actual)
+		}
+	}
+
+	// cite attributes of nodes take precedence
+	markdown := New(WithRendererOptions(html.WithBlockquoteCite(extractor)))
+	source := []byte("> [Source](/a)\n")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	doc.FirstChild().SetAttributeString("cite", []byte("/b"))
+	var b bytes.Buffer
+	if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+		t.Fatal(err)
+	}
+	expected := "<blockquote cite=\"/b\"><p><a href=\"/a\">Source</a></p>\n</blockquote>\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, but got %q", expected, b.String())
+	}
+}
+
+func TestTextContent(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		{"# Hello *emphasized* **world**", "Hello emphasized world"},
+		{"# Code `x := 1` and <b>html</b> ![image](a.png)", "Code and html"},
+		{"a\\*b &amp; &#65; [link](/url) <https://example.com>", "a*b & A link https://example.com"},
+		{"soft\nbreak  \nhard\\\nbreak", "soft break\nhard\nbreak"},
+		{"- item\n\n  second   paragraph\n- next", "item\nsecond paragraph\nnext"},
+	}
+	md := New()
+	for i, c := range cases {
+		source := []byte(c.source)
+		doc := md.Parser().Parse(text.NewReader(source))
+		if actual := ast.TextContent(doc, source); actual != c.expected {
+			t.Errorf("%d: expected %q, but got %q", i, c.expected, actual)
+		}
+	}
+}
+
+func TestHeadingStyle(t *testing.T) {
+	source := []byte("# ATX\n\nSetext 1\n===\n\nSetext 2\n---\n\n## ATX 2 ##\n")
+	expected := []ast.HeadingStyle{
+		ast.HeadingStyleATX,
+		ast.HeadingStyleSetext,
+		ast.HeadingStyleSetext,
+		ast.HeadingStyleATX,
+	}
+	doc := New().Parser().Parse(text.NewReader(source))
+	i := 0
+	for c := doc.FirstChild(); c != nil; c = c.NextSibling() {
+		heading, ok := c.(*ast.Heading)
+		if !ok {
+			t.Fatalf("%d: expected a heading, but got %s", i, c.Kind())
+		}
+		if i >= len(expected) {
+			t.Fatalf("expected %d headings, but got more", len(expected))
+		}
+		if heading.Style != expected[i] {
+			t.Errorf("%d: expected %s, but got %s", i, expected[i], heading.Style)
+		}
+		i++
+	}
+	if i != len(expected) {
+		t.Errorf("expected %d headings, but got %d", len(expected), i)
 	}
 }
@@ -0,0 +1,142 @@
+package goldmark
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A MappedFile is a read-only file that is mapped into memory if
+// the platform supports it.
+//
+// Contents of a mapped file are read from the file while they are used.
+// If another process truncates or rewrites the file before Close, reading
+// the contents may crash the program with SIGBUS or return changed bytes.
+// Do not map files those may be modified while they are in use; read them
+// by ConvertFile, or by os.ReadFile and Markdown.Convert instead.
+type MappedFile struct {
+	data  []byte
+	unmap func() error
+}
+
+// Bytes returns contents of the file.
+// Returned bytes must not be modified and must not be used after Close.
+func (f *MappedFile) Bytes() []byte {
+	return f.data
+}
+
+// Close unmaps the file. Close does nothing if the file has been closed.
+func (f *MappedFile) Close() error {
+	if f.unmap == nil {
+		return nil
+	}
+	unmap := f.unmap
+	f.data = nil
+	f.unmap = nil
+	return unmap()
+}
+
+// OpenMappedFile maps the given file into memory.
+// OpenMappedFile reads the whole file into the heap on platforms those
+// do not support memory mapping.
+func OpenMappedFile(path string) (*MappedFile, error) {
+	return openMappedFile(path)
+}
+
+// ParseFile parses the given Markdown file without copying its contents
+// into the heap. The file is parsed by Markdown that is created with
+// given options. The file must not be modified until the returned
+// MappedFile is closed. See MappedFile.
+//
+// ParseFile returns the source file with the AST because the AST refers
+// the source. The file must be closed after the AST is no longer needed.
+// ParseFile returns the first error added to the parser.Context if any.
+//
+// Line endings and NUL characters are normalized by
+// util.NormalizeLineEndings like Markdown.Convert. If the file has '\r' or
+// NUL characters, the returned MappedFile holds a normalized copy of the
+// contents in the heap, so render the AST with its Bytes.
+func ParseFile(path string, opts ...Option) (ast.Node, *MappedFile, error) {
+	f, err := OpenMappedFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	f.data = util.NormalizeLineEndings(f.data)
+	pc := parser.NewContext()
+	node := New(opts...).Parser().Parse(text.NewReader(f.Bytes()), parser.WithContext(pc))
+	if errs := parser.Errors(pc); len(errs) != 0 {
+		_ = f.Close()
+		return nil, nil, errs[0]
+	}
+	return node, f, nil
+}
+
+// ConvertFile converts the given Markdown file and writes rendered contents
+// to a writer w. The file is converted by Markdown that is created with
+// given options.
+//
+// Unlike ParseFile, ConvertFile reads the whole file into the heap, so
+// the file may be modified by other processes during the conversion.
+func ConvertFile(path string, w io.Writer, opts ...Option) error {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return New(opts...).Convert(source, w)
+}
+
+// ConvertFileToFile converts the Markdown file src and writes rendered
+// contents to the file dst. dst is replaced atomically by renaming
+// a temporary file, so dst is never left half written. Permissions of
+// dst are kept if dst exists. Otherwise dst is created with permissions
+// like os.Create, 0666 before the umask.
+func ConvertFileToFile(src, dst string, opts ...Option) (err error) {
+	tmp, err := createTempFile(filepath.Dir(dst), "."+filepath.Base(dst)+".")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	w := bufio.NewWriter(tmp)
+	if err = ConvertFile(src, w, opts...); err != nil {
+		return err
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	if fi, serr := os.Stat(dst); serr == nil {
+		if err = tmp.Chmod(fi.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// createTempFile creates a new file in the directory dir like
+// os.CreateTemp. Unlike os.CreateTemp, the file is created with 0666
+// before the umask like os.Create, not with 0600.
+func createTempFile(dir, prefix string) (*os.File, error) {
+	for i := 0; ; i++ {
+		name := filepath.Join(dir, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10))
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666)
+		if os.IsExist(err) && i < 10000 {
+			continue
+		}
+		return f, err
+	}
+}
@@ -0,0 +1,41 @@
+//go:build (linux || darwin || freebsd || netbsd || openbsd || dragonfly) && !appengine
+// +build linux darwin freebsd netbsd openbsd dragonfly
+// +build !appengine
+
+package goldmark
+
+import (
+	"os"
+	"syscall"
+)
+
+func openMappedFile(path string) (*MappedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if size == 0 {
+		return &MappedFile{data: []byte{}}, nil
+	}
+	if int64(int(size)) != size {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: syscall.EFBIG}
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return &MappedFile{
+		data: data,
+		unmap: func() error {
+			return syscall.Munmap(data)
+		},
+	}, nil
+}
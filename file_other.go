@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows) || appengine
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly,!windows appengine
+
+package goldmark
+
+import (
+	"os"
+)
+
+func openMappedFile(path string) (*MappedFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &MappedFile{data: data}, nil
+}
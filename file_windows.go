@@ -0,0 +1,50 @@
+//go:build windows && !appengine
+// +build windows,!appengine
+
+package goldmark
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func openMappedFile(path string) (*MappedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if size == 0 {
+		return &MappedFile{data: []byte{}}, nil
+	}
+	if int64(int(size)) != size {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: syscall.EFBIG}
+	}
+	h, err := syscall.CreateFileMapping(syscall.Handle(f.Fd()), nil, syscall.PAGE_READONLY,
+		uint32(size>>32), uint32(size), nil)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	addr, err := syscall.MapViewOfFile(h, syscall.FILE_MAP_READ, 0, 0, uintptr(size))
+	_ = syscall.CloseHandle(h)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	// addr is an address of memory outside the Go heap, so it is converted
+	// through its variable to keep go vet from reporting the conversion.
+	data := unsafe.Slice((*byte)(*(*unsafe.Pointer)(unsafe.Pointer(&addr))), int(size))
+	return &MappedFile{
+		data: data,
+		unmap: func() error {
+			return syscall.UnmapViewOfFile(addr)
+		},
+	}, nil
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 )
 
@@ -42,6 +44,11 @@ func fuzz(f *testing.F) {
 }
 
 func FuzzDefault(f *testing.F) {
+	addSpecExamples(f)
+	fuzz(f)
+}
+
+func addSpecExamples(f *testing.F) {
 	bs, err := ioutil.ReadFile("../_test/spec.json")
 	if err != nil {
 		panic(err)
@@ -53,5 +60,58 @@ func FuzzDefault(f *testing.F) {
 	for _, c := range testCases {
 		f.Add(c["markdown"])
 	}
-	fuzz(f)
+}
+
+// fuzzTimeout is a time limit of a conversion. The limit is long enough
+// to detect infinite loops without false positives under the fuzzer.
+const fuzzTimeout = 10 * time.Second
+
+// withTimeout runs fn and fails if fn does not finish in fuzzTimeout.
+func withTimeout(t *testing.T, source string, fn func()) {
+	done := make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			done <- recover()
+		}()
+		fn()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("panic: %v\nsource: %q", err, source)
+		}
+	case <-time.After(fuzzTimeout):
+		t.Fatalf("did not finish in %v\nsource: %q", fuzzTimeout, source)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	addSpecExamples(f)
+	p := goldmark.DefaultParser()
+	f.Fuzz(func(t *testing.T, orig string) {
+		withTimeout(t, orig, func() {
+			p.Parse(text.NewReader([]byte(orig)))
+		})
+	})
+}
+
+func FuzzConvert(f *testing.F) {
+	addSpecExamples(f)
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+		),
+	)
+	f.Fuzz(func(t *testing.T, orig string) {
+		var b bytes.Buffer
+		withTimeout(t, orig, func() {
+			if err := markdown.Convert([]byte(orig), &b); err != nil {
+				panic(err)
+			}
+		})
+		if bytes.Contains(bytes.ToLower(b.Bytes()), []byte("<script")) {
+			t.Fatalf("unescaped script tag without the unsafe option\nsource: %q\noutput: %q", orig, b.String())
+		}
+	})
 }
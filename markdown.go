@@ -2,12 +2,18 @@
 package goldmark
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
-	"io"
 )
 
 // DefaultParser returns a new Parser that is configured by default values.
@@ -26,16 +32,49 @@ func DefaultRenderer() renderer.Renderer {
 var defaultMarkdown = New()
 
 // Convert interprets a UTF-8 bytes source in Markdown and
-// write rendered contents to a writer w.
+// write rendered contents to a writer w. See Markdown.Convert.
 func Convert(source []byte, w io.Writer, opts ...parser.ParseOption) error {
 	return defaultMarkdown.Convert(source, w, opts...)
 }
 
+// ConvertString interprets a UTF-8 string source in Markdown and
+// returns rendered contents. The source is converted by Markdown that is
+// created with given options. Use Markdown.Convert instead of this function
+// when you convert many sources with the same options, because creating
+// a Markdown is not cheap.
+//
+// Like Markdown.Convert, ConvertString returns rendered contents with
+// the error if the error is added to the parser.Context.
+func ConvertString(source string, opts ...Option) (string, error) {
+	m := defaultMarkdown
+	if len(opts) != 0 {
+		m = New(opts...)
+	}
+	var b bytes.Buffer
+	err := m.Convert([]byte(source), &b)
+	return b.String(), err
+}
+
+// MustConvertString is like ConvertString but panics if the source can not
+// be converted.
+func MustConvertString(source string, opts ...Option) string {
+	s, err := ConvertString(source, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 // A Markdown interface offers functions to convert Markdown text to
 // a desired format.
 type Markdown interface {
 	// Convert interprets a UTF-8 bytes source in Markdown and write rendered
 	// contents to a writer w.
+	//
+	// If errors are added to the parser.Context while parsing, like
+	// parser.ErrTooManyEntityReferences, Convert still renders the parsed
+	// document, which may be truncated or empty, and returns the first
+	// error after writing. Use errors.Is to check the error.
 	Convert(source []byte, writer io.Writer, opts ...parser.ParseOption) error
 
 	// Parser returns a Parser that will be used for conversion.
@@ -61,6 +100,35 @@ func WithExtensions(ext ...Extender) Option {
 	}
 }
 
+// WithExtension adds an extension. WithExtension is a singular form of
+// WithExtensions for composing options with append.
+func WithExtension(ext Extender) Option {
+	return WithExtensions(ext)
+}
+
+// WithoutExtension removes extensions those are added by preceding options.
+// Extensions are compared with ==, so extensions inside an other extension
+// like Linkify in GFM can not be removed.
+func WithoutExtension(ext Extender) Option {
+	return func(m *markdown) {
+		extensions := m.extensions[:0]
+		for _, e := range m.extensions {
+			if !sameExtension(e, ext) {
+				extensions = append(extensions, e)
+			}
+		}
+		for i := len(extensions); i < len(m.extensions); i++ {
+			m.extensions[i] = nil
+		}
+		m.extensions = extensions
+	}
+}
+
+func sameExtension(a, b Extender) bool {
+	ta := reflect.TypeOf(a)
+	return ta == reflect.TypeOf(b) && ta.Comparable() && a == b
+}
+
 // WithParser allows you to override the default parser.
 func WithParser(p parser.Parser) Option {
 	return func(m *markdown) {
@@ -75,6 +143,14 @@ func WithParserOptions(opts ...parser.Option) Option {
 	}
 }
 
+// WithDebugLogger logs decisions of the parser at the debug level.
+// A *slog.Logger can be used as the logger. See parser.WithDebugLogger.
+func WithDebugLogger(logger parser.DebugLogger) Option {
+	return func(m *markdown) {
+		m.parser.AddOptions(parser.WithDebugLogger(logger))
+	}
+}
+
 // WithRenderer allows you to override the default renderer.
 func WithRenderer(r renderer.Renderer) Option {
 	return func(m *markdown) {
@@ -93,6 +169,8 @@ type markdown struct {
 	parser     parser.Parser
 	renderer   renderer.Renderer
 	extensions []Extender
+	options    []Option
+	bufferPool *sync.Pool
 }
 
 // New returns a new Markdown with given options.
@@ -101,6 +179,8 @@ func New(options ...Option) Markdown {
 		parser:     DefaultParser(),
 		renderer:   DefaultRenderer(),
 		extensions: []Extender{},
+		// options are copied because callers may reuse the slice
+		options: append([]Option(nil), options...),
 	}
 	for _, opt := range options {
 		opt(md)
@@ -111,10 +191,135 @@ func New(options ...Option) Markdown {
 	return md
 }
 
+// NewWithContext returns a new Markdown with given options like New.
+// Extensions those implement ExtensionInitializer are initialized with
+// the given context after they extend the Markdown. Extensions can release
+// their resources when the context is cancelled.
+func NewWithContext(ctx context.Context, options ...Option) (Markdown, error) {
+	md := New(options...).(*markdown)
+	for _, e := range md.extensions {
+		if i, ok := e.(ExtensionInitializer); ok {
+			if err := i.ExtensionInit(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return md, nil
+}
+
+// NewWithBufferPool returns a new Markdown with given options like New.
+// Convert of the Markdown renders contents into a *bytes.Buffer taken from
+// the given pool and writes them to the writer at once, so servers can
+// reuse buffers across requests. The pool should return *bytes.Buffer
+// values. Nothing is written to the writer if rendering fails.
+func NewWithBufferPool(pool *sync.Pool, options ...Option) Markdown {
+	opts := make([]Option, 0, len(options)+1)
+	opts = append(opts, withBufferPool(pool))
+	opts = append(opts, options...)
+	return New(opts...)
+}
+
+// withBufferPool is an option for NewWithBufferPool. This is an option so
+// that WithOptions keeps the pool.
+func withBufferPool(pool *sync.Pool) Option {
+	return func(m *markdown) {
+		m.bufferPool = pool
+	}
+}
+
 func (m *markdown) Convert(source []byte, writer io.Writer, opts ...parser.ParseOption) error {
-	reader := text.NewReader(source)
-	doc := m.parser.Parse(reader, opts...)
-	return m.renderer.Render(writer, source, doc)
+	source = util.NormalizeLineEndings(source)
+	doc, parseErr := parse(m.parser, source, opts)
+	if err := m.render(writer, source, doc); err != nil {
+		return err
+	}
+	return parseErr
+}
+
+func (m *markdown) render(writer io.Writer, source []byte, doc ast.Node) error {
+	if m.bufferPool == nil {
+		return m.renderer.Render(writer, source, doc)
+	}
+	buf, ok := m.bufferPool.Get().(*bytes.Buffer)
+	if !ok {
+		buf = &bytes.Buffer{}
+	}
+	buf.Reset()
+	defer m.bufferPool.Put(buf)
+	if err := m.renderer.Render(buf, source, doc); err != nil {
+		return err
+	}
+	_, err := writer.Write(buf.Bytes())
+	return err
+}
+
+// Load parses the given source with the given Markdown and returns
+// an io.WriterTo that writes rendered contents. Load allows you to parse
+// a source before a destination is ready, like a response of io.Pipe.
+// The source must not be modified until the WriterTo is written.
+//
+// If errors are added to the parser.Context, Load returns the first error
+// with a WriterTo that writes the parsed document like Markdown.Convert.
+func Load(m Markdown, source []byte, opts ...parser.ParseOption) (io.WriterTo, error) {
+	source = util.NormalizeLineEndings(source)
+	doc, err := parse(m.Parser(), source, opts)
+	return &loaded{
+		renderer: m.Renderer(),
+		source:   source,
+		node:     doc,
+	}, err
+}
+
+type loaded struct {
+	renderer renderer.Renderer
+	source   []byte
+	node     ast.Node
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (l *loaded) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	err := l.renderer.Render(cw, l.source, l.node)
+	return cw.n, err
+}
+
+// parse parses the given source and returns the document with the first
+// error added to the parser.Context.
+func parse(p parser.Parser, source []byte, opts []parser.ParseOption) (ast.Node, error) {
+	pc := parseContext(opts)
+	if pc == nil {
+		if cp, ok := p.(interface{ Context() parser.Context }); ok {
+			// pooled parsers reuse their own context
+			pc = cp.Context()
+		} else {
+			pc = parser.NewContext()
+			opts = append(opts, parser.WithContext(pc))
+		}
+	}
+	doc := p.Parse(text.NewReader(source), opts...)
+	if errs := parser.Errors(pc); len(errs) != 0 {
+		return doc, errs[0]
+	}
+	return doc, nil
+}
+
+// parseContext returns a parser.Context given by parser.WithContext.
+func parseContext(opts []parser.ParseOption) parser.Context {
+	c := &parser.ParseConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c.Context
 }
 
 func (m *markdown) Parser() parser.Parser {
@@ -133,8 +338,46 @@ func (m *markdown) SetRenderer(v renderer.Renderer) {
 	m.renderer = v
 }
 
+// optionsHolder is implemented by Markdowns those are created by New.
+type optionsHolder interface {
+	markdownOptions() []Option
+}
+
+func (m *markdown) markdownOptions() []Option {
+	return m.options
+}
+
+// WithOptions returns a new Markdown that is created by New with options
+// of the given Markdown followed by given options. The given Markdown is
+// not changed. WithOptions panics if the Markdown is not created by New
+// or functions like NewWithContext those call New.
+//
+// The new Markdown is built from options, so Parsers and Renderers set by
+// SetParser and SetRenderer after New are not copied. Parsers and
+// Renderers given by WithParser and WithRenderer are shared with the given
+// Markdown, so do not use WithOptions with them.
+func WithOptions(m Markdown, opts ...Option) Markdown {
+	h, ok := m.(optionsHolder)
+	if !ok {
+		panic("goldmark: WithOptions requires a Markdown created by New")
+	}
+	base := h.markdownOptions()
+	options := make([]Option, 0, len(base)+len(opts))
+	options = append(options, base...)
+	options = append(options, opts...)
+	return New(options...)
+}
+
 // An Extender interface is used for extending Markdown.
 type Extender interface {
 	// Extend extends the Markdown.
 	Extend(Markdown)
 }
+
+// An ExtensionInitializer interface is an optional interface of
+// Extenders those hold resources like database connections.
+type ExtensionInitializer interface {
+	// ExtensionInit initializes the extension with the given context.
+	// The context is given by NewWithContext.
+	ExtensionInit(ctx context.Context) error
+}
@@ -84,10 +84,8 @@ func (b *atxHeadingParser) Open(parent ast.Node, reader text.Reader, pc Context)
 	if pos < 0 {
 		return nil, NoChildren
 	}
-	i := pos
-	for ; i < len(line) && line[i] == '#'; i++ {
-	}
-	level := i - pos
+	level := util.CountLeadingBytes(line[pos:], '#')
+	i := pos + level
 	if i == pos || level > 6 {
 		return nil, NoChildren
 	}
@@ -1,20 +1,49 @@
 package parser
 
 import (
+	"bytes"
+
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 )
 
-type autoLinkParser struct {
+const optLenientAutolinks OptionName = "LenientAutolinks"
+
+type withLenientAutolinks struct {
+	value bool
+}
+
+func (o *withLenientAutolinks) SetParserOption(c *Config) {
+	c.Options[optLenientAutolinks] = o.value
 }
 
-var defaultAutoLinkParser = &autoLinkParser{}
+// WithLenientAutolinks is a functional option that allows autolinks to be
+// continued on the next line by a trailing backslash like
+// '<https://very-long\' and 'url.com/path>'.
+//
+// This is not a part of the CommonMark spec, so it is disabled by default.
+// Continued autolinks are parsed as Link nodes because their URLs are not
+// contiguous in the source.
+func WithLenientAutolinks(enabled bool) Option {
+	return &withLenientAutolinks{enabled}
+}
+
+type autoLinkParser struct {
+	lenient bool
+}
 
 // NewAutoLinkParser returns a new InlineParser that parses autolinks
 // surrounded by '<' and '>' .
 func NewAutoLinkParser() InlineParser {
-	return defaultAutoLinkParser
+	return &autoLinkParser{}
+}
+
+// SetOption implements SetOptioner.
+func (s *autoLinkParser) SetOption(name OptionName, value interface{}) {
+	if name == optLenientAutolinks {
+		s.lenient = value.(bool)
+	}
 }
 
 func (s *autoLinkParser) Trigger() []byte {
@@ -29,14 +58,59 @@ func (s *autoLinkParser) Parse(parent ast.Node, block text.Reader, pc Context) a
 		stop = util.FindURLIndex(line[1:])
 		typ = ast.AutoLinkURL
 	}
-	if stop < 0 {
-		return nil
+	if stop >= 0 {
+		stop++
 	}
-	stop++
-	if stop >= len(line) || line[stop] != '>' {
+	if stop < 0 || stop >= len(line) || line[stop] != '>' {
+		if s.lenient {
+			return s.parseContinuedAutoLink(block)
+		}
 		return nil
 	}
 	value := ast.NewTextSegment(text.NewSegment(segment.Start+1, segment.Start+stop))
 	block.Advance(stop + 1)
 	return ast.NewAutoLink(typ, value)
 }
+
+// parseContinuedAutoLink parses an autolink whose lines end with
+// backslashes.
+func (s *autoLinkParser) parseContinuedAutoLink(block text.Reader) ast.Node {
+	savedLine, savedPosition := block.Position()
+	line, _ := block.PeekLine()
+	line = line[1:]
+	offset := 1
+	var value []byte
+	for {
+		content := util.TrimRightSpace(line)
+		if i := bytes.IndexByte(content, '>'); i > -1 {
+			value = append(value, content[:i]...)
+			offset += i + 1
+			break
+		}
+		if len(content) == 0 || content[len(content)-1] != '\\' {
+			block.SetPosition(savedLine, savedPosition)
+			return nil
+		}
+		value = append(value, content[:len(content)-1]...)
+		block.AdvanceLine()
+		raw, _ := block.PeekLine()
+		if raw == nil {
+			block.SetPosition(savedLine, savedPosition)
+			return nil
+		}
+		line = util.TrimLeftSpace(raw)
+		offset = len(raw) - len(line)
+	}
+	link := ast.NewLink()
+	if util.FindEmailIndex(value) == len(value) {
+		link.Destination = append([]byte("mailto:"), value...)
+	} else if util.FindURLIndex(value) == len(value) {
+		link.Destination = value
+	} else {
+		block.SetPosition(savedLine, savedPosition)
+		return nil
+	}
+	block.Advance(offset)
+	link.AppendChild(link, ast.NewString(value))
+	return link
+}
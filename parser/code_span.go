@@ -3,6 +3,7 @@ package parser
 import (
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 type codeSpanParser struct {
@@ -22,9 +23,7 @@ func (s *codeSpanParser) Trigger() []byte {
 
 func (s *codeSpanParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.Node {
 	line, startSegment := block.PeekLine()
-	opener := 0
-	for ; opener < len(line) && line[opener] == '`'; opener++ {
-	}
+	opener := util.CountLeadingBytes(line, '`')
 	block.Advance(opener)
 	l, pos := block.Position()
 	node := ast.NewCodeSpan()
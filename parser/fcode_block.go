@@ -8,15 +8,52 @@ import (
 	"github.com/yuin/goldmark/util"
 )
 
-type fencedCodeBlockParser struct {
+const optStrictFenceLength OptionName = "StrictFenceLength"
+
+type withStrictFenceLength struct {
+	value bool
+}
+
+func (o *withStrictFenceLength) SetParserOption(c *Config) {
+	c.Options[optStrictFenceLength] = o.value
 }
 
-var defaultFencedCodeBlockParser = &fencedCodeBlockParser{}
+// WithStrictFenceLength is a functional option that requires closing
+// fences of fenced code blocks to have exactly the same length as their
+// opening fences. Longer closing fences like '````' for '```' are treated
+// as lines of the code.
+//
+// This is not a part of the CommonMark spec, so it is disabled by default.
+// Code blocks those are not closed continue to the end of their container
+// as the CommonMark spec defines.
+func WithStrictFenceLength(enabled bool) Option {
+	return &withStrictFenceLength{enabled}
+}
+
+type fencedCodeBlockParser struct {
+	strictFenceLength bool
+}
 
 // NewFencedCodeBlockParser returns a new BlockParser that
 // parses fenced code blocks.
 func NewFencedCodeBlockParser() BlockParser {
-	return defaultFencedCodeBlockParser
+	return &fencedCodeBlockParser{}
+}
+
+// SetOption implements SetOptioner.
+func (b *fencedCodeBlockParser) SetOption(name OptionName, value interface{}) {
+	if name == optStrictFenceLength {
+		b.strictFenceLength = value.(bool)
+	}
+}
+
+// isClosingFence returns true if a fence of the given length closes
+// the code block.
+func (b *fencedCodeBlockParser) isClosingFence(length int, fdata *fenceData) bool {
+	if b.strictFenceLength {
+		return length == fdata.length
+	}
+	return length >= fdata.length
 }
 
 type fenceData struct {
@@ -40,10 +77,8 @@ func (b *fencedCodeBlockParser) Open(parent ast.Node, reader text.Reader, pc Con
 	}
 	findent := pos
 	fenceChar := line[pos]
-	i := pos
-	for ; i < len(line) && line[i] == fenceChar; i++ {
-	}
-	oFenceLength := i - pos
+	oFenceLength := util.CountLeadingBytes(line[pos:], fenceChar)
+	i := pos + oFenceLength
 	if oFenceLength < 3 {
 		return nil, NoChildren
 	}
@@ -74,11 +109,9 @@ func (b *fencedCodeBlockParser) Continue(node ast.Node, reader text.Reader, pc C
 
 	w, pos := util.IndentWidth(line, reader.LineOffset())
 	if w < 4 {
-		i := pos
-		for ; i < len(line) && line[i] == fdata.char; i++ {
-		}
-		length := i - pos
-		if length >= fdata.length && util.IsBlank(line[i:]) {
+		length := util.CountLeadingBytes(line[pos:], fdata.char)
+		i := pos + length
+		if b.isClosingFence(length, fdata) && util.IsBlank(line[i:]) {
 			newline := 1
 			if line[len(line)-1] != '\n' {
 				newline = 0
@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// An InlineNodeFactory function returns a new inline node for a span
+// enclosed by delimiters. consumes is a number of delimiter characters
+// consumed by each side of the span.
+type InlineNodeFactory func(consumes int) ast.Node
+
+type inlineDelimiterProcessor struct {
+	opener  byte
+	closer  byte
+	factory InlineNodeFactory
+}
+
+func (p *inlineDelimiterProcessor) IsDelimiter(b byte) bool {
+	return b == p.opener || b == p.closer
+}
+
+func (p *inlineDelimiterProcessor) CanOpenCloser(opener, closer *Delimiter) bool {
+	return opener.Char == p.opener && closer.Char == p.closer
+}
+
+func (p *inlineDelimiterProcessor) OnMatch(consumes int) ast.Node {
+	return p.factory(consumes)
+}
+
+type inlineDelimiterParser struct {
+	processor *inlineDelimiterProcessor
+	minRun    int
+	maxRun    int
+}
+
+// NewInlineDelimiterParser returns a new InlineParser that parses
+// emphasis-like spans enclosed by runs of the opener and the closer
+// like '!!important!!'. Runs shorter than minRun or longer than maxRun are
+// not delimiters. The opener and the closer can be the same character.
+//
+// The opener and the closer must be ASCII punctuations.
+func NewInlineDelimiterParser(opener, closer rune, minRun, maxRun int, factory InlineNodeFactory) InlineParser {
+	for _, r := range []rune{opener, closer} {
+		if r > 0x7f || !util.IsPunct(byte(r)) {
+			panic(fmt.Sprintf("parser: inline delimiter must be an ASCII punctuation: %q", r))
+		}
+	}
+	if minRun < 1 || maxRun < minRun {
+		panic(fmt.Sprintf("parser: invalid inline delimiter run length: %d-%d", minRun, maxRun))
+	}
+	return &inlineDelimiterParser{
+		processor: &inlineDelimiterProcessor{
+			opener:  byte(opener),
+			closer:  byte(closer),
+			factory: factory,
+		},
+		minRun: minRun,
+		maxRun: maxRun,
+	}
+}
+
+func (s *inlineDelimiterParser) Trigger() []byte {
+	if s.processor.opener == s.processor.closer {
+		return []byte{s.processor.opener}
+	}
+	return []byte{s.processor.opener, s.processor.closer}
+}
+
+func (s *inlineDelimiterParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.Node {
+	before := block.PrecendingCharacter()
+	line, segment := block.PeekLine()
+	node := ScanDelimiter(line, before, s.minRun, s.processor)
+	if node == nil {
+		return nil
+	}
+	if node.OriginalLength > s.maxRun {
+		// consumes the whole run so that a part of the run is not
+		// parsed as a delimiter
+		block.Advance(node.OriginalLength)
+		return ast.NewTextSegment(segment.WithStop(segment.Start + node.OriginalLength))
+	}
+	if s.processor.opener != s.processor.closer {
+		node.CanOpen = node.CanOpen && node.Char == s.processor.opener
+		node.CanClose = node.CanClose && node.Char == s.processor.closer
+	}
+	node.Segment = segment.WithStop(segment.Start + node.OriginalLength)
+	block.Advance(node.OriginalLength)
+	pc.PushDelimiter(node)
+	return node
+}
+
+// WithInlineDelimiter is a functional option that adds an emphasis-like
+// inline syntax enclosed by runs of the opener and the closer.
+// See NewInlineDelimiterParser for details.
+//
+//	parser.WithInlineDelimiter('!', '!', 2, 2, func(int) ast.Node {
+//	    return ast.NewEmphasis(2)
+//	})
+func WithInlineDelimiter(opener, closer rune, minRun, maxRun int, factory InlineNodeFactory) Option {
+	return WithInlineParsers(
+		util.Prioritized(NewInlineDelimiterParser(opener, closer, minRun, maxRun, factory), 500),
+	)
+}
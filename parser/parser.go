@@ -2,7 +2,9 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 
@@ -62,13 +64,26 @@ type IDs interface {
 	Put(value []byte)
 }
 
+// A ResettableIDs is an IDs that can be cleared for the next document.
+// Contexts reused by a ParserPool call Reset between documents instead of
+// replacing the IDs. The default IDs implements this interface.
+type ResettableIDs interface {
+	IDs
+
+	// Reset removes all used ids. Settings like a first number of
+	// suffixes must be kept.
+	Reset()
+}
+
 type ids struct {
-	values map[string]bool
+	values      map[string]bool
+	suffixStart int
 }
 
 func newIDs() IDs {
 	return &ids{
-		values: map[string]bool{},
+		values:      map[string]bool{},
+		suffixStart: 1,
 	}
 }
 
@@ -103,7 +118,7 @@ func (s *ids) Generate(value []byte, kind ast.NodeKind) []byte {
 		s.values[util.BytesToReadOnlyString(result)] = true
 		return result
 	}
-	for i := 1; ; i++ {
+	for i := s.suffixStart; ; i++ {
 		newResult := fmt.Sprintf("%s-%d", result, i)
 		if _, ok := s.values[newResult]; !ok {
 			s.values[newResult] = true
@@ -117,6 +132,12 @@ func (s *ids) Put(value []byte) {
 	s.values[util.BytesToReadOnlyString(value)] = true
 }
 
+func (s *ids) Reset() {
+	for k := range s.values {
+		delete(s.values, k)
+	}
+}
+
 // ContextKey is a key that is used to set arbitrary values to the context.
 type ContextKey int
 
@@ -204,9 +225,29 @@ type Context interface {
 	IsInLinkLabel() bool
 }
 
+var errorsKey = NewContextKey()
+
+// AddError adds an error occurred while parsing to the given Context.
+// Errors are stored as a value of the Context, so any implementation of
+// the Context can hold them.
+func AddError(pc Context, err error) {
+	errs, _ := pc.Get(errorsKey).([]error)
+	pc.Set(errorsKey, append(errs, err))
+}
+
+// Errors returns errors added to the given Context by AddError.
+func Errors(pc Context) []error {
+	errs, _ := pc.Get(errorsKey).([]error)
+	return errs
+}
+
 // A ContextConfig struct is a data structure that holds configuration of the Context.
 type ContextConfig struct {
 	IDs IDs
+
+	// IDSuffixStart is a first number of suffixes of duplicated ids
+	// generated by the default IDs. See WithIDSuffixStart.
+	IDSuffixStart int
 }
 
 // An ContextOption is a functional option type for the Context.
@@ -219,6 +260,17 @@ func WithIDs(ids IDs) ContextOption {
 	}
 }
 
+// WithIDSuffixStart is a functional option for the Context that specifies
+// a first number of suffixes those are appended to duplicated ids like
+// 'title-1'. This defaults to 1 like GitHub. Set 2 to generate ids like
+// 'title', 'title-2' and 'title-3'. This option has no effect on IDs
+// given by WithIDs.
+func WithIDSuffixStart(n int) ContextOption {
+	return func(c *ContextConfig) {
+		c.IDSuffixStart = n
+	}
+}
+
 type parseContext struct {
 	store         []interface{}
 	ids           IDs
@@ -238,6 +290,9 @@ func NewContext(options ...ContextOption) Context {
 	for _, option := range options {
 		option(cfg)
 	}
+	if v, ok := cfg.IDs.(*ids); ok && cfg.IDSuffixStart > 0 {
+		v.suffixStart = cfg.IDSuffixStart
+	}
 
 	return &parseContext{
 		store:         make([]interface{}, ContextKeyMax+1),
@@ -251,6 +306,33 @@ func NewContext(options ...ContextOption) Context {
 	}
 }
 
+// reset clears all values of this context. Underlying buffers are kept
+// for the next use. IDs are kept too, and cleared only if they implement
+// ResettableIDs.
+func (p *parseContext) reset() {
+	if len(p.store) < int(ContextKeyMax)+1 {
+		p.store = make([]interface{}, ContextKeyMax+1)
+	} else {
+		for i := range p.store {
+			p.store[i] = nil
+		}
+	}
+	for k := range p.refs {
+		delete(p.refs, k)
+	}
+	if v, ok := p.ids.(ResettableIDs); ok {
+		v.Reset()
+	}
+	p.blockOffset = -1
+	p.blockIndent = -1
+	p.delimiters = nil
+	p.lastDelimiter = nil
+	for i := range p.openedBlocks {
+		p.openedBlocks[i] = Block{}
+	}
+	p.openedBlocks = p.openedBlocks[:0]
+}
+
 func (p *parseContext) Get(key ContextKey) interface{} {
 	return p.store[key]
 }
@@ -432,6 +514,10 @@ type Config struct {
 	ParagraphTransformers util.PrioritizedSlice /*<ParagraphTransformer>*/
 	ASTTransformers       util.PrioritizedSlice /*<ASTTransformer>*/
 	EscapedSpace          bool
+	MaxDocumentSize       int64
+	MaxNodeCount          int
+	MaxEntityExpansions   int
+	DebugLogger           DebugLogger
 }
 
 // NewConfig returns a new Config.
@@ -471,6 +557,14 @@ func WithAttribute() Option {
 // A Parser interface parses Markdown text into AST nodes.
 type Parser interface {
 	// Parse parses the given Markdown text into AST nodes.
+	//
+	// Parse does not normalize line endings and NUL characters because
+	// segments of the AST refer the source of the reader, and renderers
+	// must be given the same source. goldmark.Markdown.Convert and
+	// goldmark.ParseFile normalize them by util.NormalizeLineEndings, so
+	// callers those parse sources directly should normalize them with
+	// util.NormalizeLineEndings before creating the reader to get the
+	// same AST.
 	Parse(reader text.Reader, opts ...ParseOption) ast.Node
 
 	// AddOption adds the given option to this parser.
@@ -638,6 +732,10 @@ type parser struct {
 	paragraphTransformers []ParagraphTransformer
 	astTransformers       []ASTTransformer
 	escapedSpace          bool
+	maxDocumentSize       int64
+	maxNodeCount          int
+	maxEntityExpansions   int
+	logger                DebugLogger
 	config                *Config
 	initSync              sync.Once
 }
@@ -710,6 +808,93 @@ func WithEscapedSpace() Option {
 	return &withEscapedSpace{}
 }
 
+// ErrDocumentTooLarge is an error that is added to the Context when
+// the document exceeds the size given by WithMaxDocumentSize.
+var ErrDocumentTooLarge = errors.New("goldmark: document exceeds the maximum size")
+
+// ErrTooManyNodes is an error that is added to the Context when
+// the document exceeds the number of nodes given by WithMaxNodeCount.
+var ErrTooManyNodes = errors.New("goldmark: document exceeds the maximum number of nodes")
+
+type withMaxDocumentSize struct {
+	value int64
+}
+
+func (o *withMaxDocumentSize) SetParserOption(c *Config) {
+	c.MaxDocumentSize = o.value
+}
+
+// WithMaxDocumentSize is a functional option that limits a size of
+// documents in bytes. Parse returns an empty document and adds
+// ErrDocumentTooLarge to the Context if the document exceeds the limit.
+func WithMaxDocumentSize(n int64) Option {
+	return &withMaxDocumentSize{n}
+}
+
+type withMaxNodeCount struct {
+	value int
+}
+
+func (o *withMaxNodeCount) SetParserOption(c *Config) {
+	c.MaxNodeCount = o.value
+}
+
+// WithMaxNodeCount is a functional option that limits a number of nodes
+// in the AST. Parse returns an empty document and adds ErrTooManyNodes
+// to the Context if the AST exceeds the limit. Blocks are counted while
+// they are opened, so Parse stops as soon as the limit is exceeded.
+func WithMaxNodeCount(n int) Option {
+	return &withMaxNodeCount{n}
+}
+
+// ErrTooManyEntityReferences is an error that is added to the Context
+// when the document exceeds the number of entity references given by
+// WithEntityExpansionLimit.
+var ErrTooManyEntityReferences = errors.New("goldmark: document exceeds the maximum number of entity references")
+
+type withEntityExpansionLimit struct {
+	value int
+}
+
+func (o *withEntityExpansionLimit) SetParserOption(c *Config) {
+	c.MaxEntityExpansions = o.value
+}
+
+// WithEntityExpansionLimit is a functional option that limits a number of
+// entity references like '&amp;' and '&#1234;' in texts, link destinations
+// and link titles of the document. If the document exceeds the limit,
+// the document is truncated before the top level block that contains
+// the exceeding reference and ErrTooManyEntityReferences is added to
+// the Context.
+func WithEntityExpansionLimit(n int) Option {
+	return &withEntityExpansionLimit{n}
+}
+
+// A DebugLogger interface logs decisions of the parser.
+// *slog.Logger satisfies this interface.
+type DebugLogger interface {
+	// Debug logs a message with key value pairs.
+	Debug(msg string, args ...interface{})
+}
+
+type withDebugLogger struct {
+	value DebugLogger
+}
+
+func (o *withDebugLogger) SetParserOption(c *Config) {
+	c.DebugLogger = o.value
+	if v := reflect.ValueOf(o.value); v.Kind() == reflect.Ptr && v.IsNil() {
+		c.DebugLogger = nil
+	}
+}
+
+// WithDebugLogger is a functional option that logs which block parsers
+// opened blocks, which inline parsers matched spans and link reference
+// definitions found in the document. A nil logger disables logging.
+func WithDebugLogger(logger DebugLogger) Option {
+	return &withDebugLogger{logger}
+}
+
 type withOption struct {
 	name  OptionName
 	value interface{}
@@ -862,6 +1047,10 @@ func (p *parser) Parse(reader text.Reader, opts ...ParseOption) ast.Node {
 			p.addASTTransformer(v, p.config.Options)
 		}
 		p.escapedSpace = p.config.EscapedSpace
+		p.maxDocumentSize = p.config.MaxDocumentSize
+		p.maxNodeCount = p.config.MaxNodeCount
+		p.maxEntityExpansions = p.config.MaxEntityExpansions
+		p.logger = p.config.DebugLogger
 		p.config = nil
 	})
 	c := &ParseConfig{}
@@ -873,12 +1062,72 @@ func (p *parser) Parse(reader text.Reader, opts ...ParseOption) ast.Node {
 	}
 	pc := c.Context
 	root := ast.NewDocument()
+	if p.maxDocumentSize > 0 && int64(len(reader.Source())) > p.maxDocumentSize {
+		AddError(pc, ErrDocumentTooLarge)
+		return root
+	}
 	p.parseBlocks(root, reader, pc)
+	if p.tooManyBlocks(pc) {
+		AddError(pc, ErrTooManyNodes)
+		return ast.NewDocument()
+	}
+	for _, ref := range pc.References() {
+		root.AddLinkReference(ast.LinkReference{
+			Label:       ref.Label(),
+			Destination: ref.Destination(),
+			Title:       ref.Title(),
+		})
+	}
+	if p.logger != nil {
+		for _, ref := range pc.References() {
+			p.logger.Debug("link reference definition",
+				"label", string(ref.Label()),
+				"destination", string(ref.Destination()),
+				"title", string(ref.Title()))
+		}
+	}
 
+	nodes := 0
+	if p.maxNodeCount > 0 {
+		nodes = countBlocks(root)
+		if nodes > p.maxNodeCount {
+			AddError(pc, ErrTooManyNodes)
+			return ast.NewDocument()
+		}
+	}
+	entities := 0
+	var truncateAt ast.Node
 	blockReader := text.NewBlockReader(reader.Source(), nil)
 	p.walkBlock(root, func(node ast.Node) {
+		if nodes > p.maxNodeCount || truncateAt != nil {
+			return
+		}
 		p.parseBlock(blockReader, node, pc)
+		if p.maxNodeCount > 0 {
+			nodes += countInlines(node)
+		}
+		if p.maxEntityExpansions > 0 {
+			entities += countEntityReferences(node, reader.Source())
+			if entities > p.maxEntityExpansions {
+				truncateAt = node
+			}
+		}
 	})
+	if p.maxNodeCount > 0 && nodes > p.maxNodeCount {
+		AddError(pc, ErrTooManyNodes)
+		return ast.NewDocument()
+	}
+	if truncateAt != nil {
+		AddError(pc, ErrTooManyEntityReferences)
+		for truncateAt.Parent() != root {
+			truncateAt = truncateAt.Parent()
+		}
+		for c := truncateAt; c != nil; {
+			next := c.NextSibling()
+			root.RemoveChild(root, c)
+			c = next
+		}
+	}
 	for _, at := range p.astTransformers {
 		at.Transform(root, reader, pc)
 	}
@@ -933,7 +1182,7 @@ func (p *parser) openBlocks(parent ast.Node, blankLine bool, reader text.Reader,
 	}
 retry:
 	var bps []BlockParser
-	line, _ := reader.PeekLine()
+	line, lineSegment := reader.PeekLine()
 	w, pos := util.IndentWidth(line, reader.LineOffset())
 	if w >= len(line) {
 		pc.SetBlockOffset(-1)
@@ -967,6 +1216,13 @@ retry:
 		last := lastBlock.Node
 		node, state := bp.Open(parent, reader, pc)
 		if node != nil {
+			if p.logger != nil {
+				p.logger.Debug("block parser opened a block",
+					"parser", fmt.Sprintf("%T", bp),
+					"kind", node.Kind().String(),
+					"offset", lineSegment.Start,
+					"line", string(util.TrimRightSpace(line)))
+			}
 			// Parser requires last node to be a paragraph.
 			// With table extension:
 			//
@@ -1004,6 +1260,13 @@ retry:
 			result = newBlocksOpened
 			be := Block{node, bp}
 			pc.SetOpenedBlocks(append(pc.OpenedBlocks(), be))
+			if p.maxNodeCount > 0 {
+				count := pc.Get(blockCountKey).(*int)
+				*count++
+				if *count > p.maxNodeCount {
+					break // parseBlocks stops parsing
+				}
+			}
 			if state&HasChildren != 0 {
 				parent = node
 				goto retry // try child block
@@ -1047,8 +1310,24 @@ func isBlankLine(lineNum, level int, stats []lineStat) bool {
 	return ret
 }
 
+// blockCountKey is a ContextKey for a number of blocks opened by
+// parseBlocks including the document. This is set only if WithMaxNodeCount
+// is given.
+var blockCountKey = NewContextKey()
+
+// tooManyBlocks returns true if parseBlocks has opened more blocks than
+// the limit given by WithMaxNodeCount.
+func (p *parser) tooManyBlocks(pc Context) bool {
+	return p.maxNodeCount > 0 && *pc.Get(blockCountKey).(*int) > p.maxNodeCount
+}
+
 func (p *parser) parseBlocks(parent ast.Node, reader text.Reader, pc Context) {
 	pc.SetOpenedBlocks([]Block{})
+	if p.maxNodeCount > 0 {
+		// counts blocks while parsing to stop before the whole AST is built
+		count := 1
+		pc.Set(blockCountKey, &count)
+	}
 	blankLines := make([]lineStat, 0, 128)
 	var isBlank bool
 	for { // process blocks separated by blank lines
@@ -1066,7 +1345,7 @@ func (p *parser) parseBlocks(parent ast.Node, reader text.Reader, pc Context) {
 		}
 		isBlank = isBlankLine(lineNum-1, 0, blankLines)
 		// first, we try to open blocks
-		if p.openBlocks(parent, isBlank, reader, pc) != newBlocksOpened {
+		if p.openBlocks(parent, isBlank, reader, pc) != newBlocksOpened || p.tooManyBlocks(pc) {
 			return
 		}
 		reader.AdvanceLine()
@@ -1097,6 +1376,9 @@ func (p *parser) parseBlocks(parent ast.Node, reader text.Reader, pc Context) {
 						if state&HasChildren != 0 && i == lastIndex {
 							isBlank = isBlankLine(lineNum-1, i, blankLines)
 							p.openBlocks(be.Node, isBlank, reader, pc)
+							if p.tooManyBlocks(pc) {
+								return
+							}
 							break
 						}
 						continue
@@ -1110,6 +1392,9 @@ func (p *parser) parseBlocks(parent ast.Node, reader text.Reader, pc Context) {
 				}
 				lastNode := openedBlocks[lastIndex].Node
 				result := p.openBlocks(thisParent, isBlank, reader, pc)
+				if p.tooManyBlocks(pc) {
+					return
+				}
 				if result != paragraphContinuation {
 					// lastNode is a paragraph and was transformed by the paragraph
 					// transformers.
@@ -1133,6 +1418,62 @@ func (p *parser) walkBlock(block ast.Node, cb func(node ast.Node)) {
 	cb(block)
 }
 
+func (p *parser) logInlineNode(ip InlineParser, node ast.Node, block text.Reader, start text.Segment) {
+	_, end := block.Position()
+	var span []byte
+	if end.Start >= start.Start {
+		span = block.Source()[start.Start:end.Start]
+	}
+	p.logger.Debug("inline parser matched a span",
+		"parser", fmt.Sprintf("%T", ip),
+		"kind", node.Kind().String(),
+		"offset", start.Start,
+		"span", string(span))
+}
+
+func countBlocks(n ast.Node) int {
+	count := 1
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		count += countBlocks(c)
+	}
+	return count
+}
+
+// countInlines returns a number of inline nodes those are not children of
+// the nested blocks.
+func countInlines(n ast.Node) int {
+	count := 0
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if c.Type() == ast.TypeInline {
+			count += 1 + countInlines(c)
+		}
+	}
+	return count
+}
+
+// countEntityReferences returns a number of entity references those
+// will be resolved by renderers in inline nodes of the given block.
+func countEntityReferences(n ast.Node, source []byte) int {
+	count := 0
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if c.Type() != ast.TypeInline {
+			continue
+		}
+		switch v := c.(type) {
+		case *ast.Text:
+			count += util.CountEntityReferences(v.Segment.Value(source))
+		case *ast.Link:
+			count += util.CountEntityReferences(v.Destination) + util.CountEntityReferences(v.Title)
+		case *ast.Image:
+			count += util.CountEntityReferences(v.Destination) + util.CountEntityReferences(v.Title)
+		case *ast.CodeSpan, *ast.RawHTML, *ast.AutoLink:
+			continue
+		}
+		count += countEntityReferences(c, source)
+	}
+	return count
+}
+
 const (
 	lineBreakHard uint8 = 1 << iota
 	lineBreakSoft
@@ -1204,7 +1545,8 @@ func (p *parser) parseBlock(block text.BlockReader, parent ast.Node, pc Context)
 						_, startPosition = block.Position()
 					}
 					var inlineNode ast.Node
-					for _, ip := range ips {
+					var ip InlineParser
+					for _, ip = range ips {
 						inlineNode = ip.Parse(parent, block, pc)
 						if inlineNode != nil {
 							break
@@ -1212,6 +1554,9 @@ func (p *parser) parseBlock(block text.BlockReader, parent ast.Node, pc Context)
 						block.SetPosition(savedLine, savedPosition)
 					}
 					if inlineNode != nil {
+						if p.logger != nil {
+							p.logInlineNode(ip, inlineNode, block, savedPosition)
+						}
 						parent.AppendChild(parent, inlineNode)
 						goto retry
 					}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"sync"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// A ParserPool is a pool of Parsers those have the same options.
+// Parsers in the pool reuse their parsing state like the reference map
+// and the element ids between parses to reduce allocations.
+//
+// A Parser from Get should be returned to the pool by Put after its
+// result is no longer needed. Like other Parsers, pooled Parsers do not
+// normalize line endings. See Parser.Parse.
+type ParserPool struct { //nolint:revive
+	pool sync.Pool
+}
+
+// NewParserPool returns a new ParserPool that creates Parsers with
+// given options.
+func NewParserPool(options ...Option) *ParserPool {
+	return NewParserPoolWithContextOptions(nil, options...)
+}
+
+// NewParserPoolWithContextOptions returns a new ParserPool like
+// NewParserPool. Each Parser in the pool parses documents with its own
+// Context created by the given ContextOptions.
+//
+// IDs given by WithIDs are shared by all Parsers in the pool, so they must
+// be safe for concurrent use. IDs those do not implement ResettableIDs are
+// not cleared between documents.
+func NewParserPoolWithContextOptions(contextOptions []ContextOption, options ...Option) *ParserPool {
+	p := &ParserPool{}
+	p.pool.New = func() interface{} {
+		return newPooledParser(NewParser(options...), contextOptions...)
+	}
+	return p
+}
+
+// Get returns a Parser from the pool.
+func (p *ParserPool) Get() Parser {
+	return p.pool.Get().(*pooledParser)
+}
+
+// Put resets the given Parser and puts it back to the pool.
+// Parsers those are not created by this pool are ignored.
+func (p *ParserPool) Put(v Parser) {
+	pp, ok := v.(*pooledParser)
+	if !ok {
+		return
+	}
+	pp.Reset()
+	p.pool.Put(pp)
+}
+
+type pooledParser struct {
+	Parser
+	context *parseContext
+}
+
+func newPooledParser(p Parser, contextOptions ...ContextOption) *pooledParser {
+	return &pooledParser{
+		Parser:  p,
+		context: NewContext(contextOptions...).(*parseContext),
+	}
+}
+
+// Parse implements Parser.Parse. Parse uses the reusable context
+// unless the context is given by WithContext.
+func (p *pooledParser) Parse(reader text.Reader, opts ...ParseOption) ast.Node {
+	p.context.reset()
+	return p.Parser.Parse(reader, append([]ParseOption{WithContext(p.context)}, opts...)...)
+}
+
+// Reset clears the parsing state of this parser.
+func (p *pooledParser) Reset() {
+	p.context.reset()
+}
+
+// Context returns the reusable context of this parser. The context is
+// reset at the beginning of each Parse.
+func (p *pooledParser) Context() Context {
+	return p.context
+}
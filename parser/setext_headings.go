@@ -68,6 +68,7 @@ func (b *setextHeadingParser) Open(parent ast.Node, reader text.Reader, pc Conte
 		level = 2
 	}
 	node := ast.NewHeading(level)
+	node.Style = ast.HeadingStyleSetext
 	node.Lines().Append(segment)
 	pc.Set(temporaryParagraphKey, last)
 	return node, NoChildren | RequireParagraph
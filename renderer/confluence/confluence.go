@@ -0,0 +1,458 @@
+// Package confluence implements renderer that outputs Confluence wiki markup.
+//
+// Blockquotes starting with a GitHub style alert like '[!NOTE]' are
+// rendered as Confluence admonition macros like '{info}'.
+package confluence
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// AdmonitionMacros maps GitHub style alert types to Confluence macros.
+var AdmonitionMacros = map[string]string{
+	"NOTE":      "info",
+	"TIP":       "tip",
+	"IMPORTANT": "info",
+	"WARNING":   "note",
+	"CAUTION":   "warning",
+}
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that
+// renders nodes as Confluence wiki markup.
+type Renderer struct {
+}
+
+// NewRenderer returns a new Renderer.
+// The Renderer should be registered with a priority smaller than 500
+// to render nodes of extensions like Table.
+func NewRenderer() renderer.NodeRenderer {
+	return &Renderer{}
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	// blocks
+
+	reg.Register(ast.KindDocument, r.renderDocument)
+	reg.Register(ast.KindHeading, r.renderHeading)
+	reg.Register(ast.KindBlockquote, r.renderBlockquote)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindHTMLBlock, r.renderHTMLBlock)
+	reg.Register(ast.KindList, r.renderList)
+	reg.Register(ast.KindListItem, r.renderListItem)
+	reg.Register(ast.KindParagraph, r.renderParagraph)
+	reg.Register(ast.KindTextBlock, r.renderParagraph)
+	reg.Register(ast.KindThematicBreak, r.renderThematicBreak)
+
+	// inlines
+
+	reg.Register(ast.KindAutoLink, r.renderAutoLink)
+	reg.Register(ast.KindCodeSpan, r.renderCodeSpan)
+	reg.Register(ast.KindEmphasis, r.renderEmphasis)
+	reg.Register(ast.KindImage, r.renderImage)
+	reg.Register(ast.KindLink, r.renderLink)
+	reg.Register(ast.KindRawHTML, r.renderRawHTML)
+	reg.Register(ast.KindText, r.renderText)
+	reg.Register(ast.KindString, r.renderString)
+
+	// GFM extensions
+
+	reg.Register(east.KindStrikethrough, r.renderStrikethrough)
+	reg.Register(east.KindTable, r.renderTable)
+	reg.Register(east.KindTableHeader, r.renderTableRow)
+	reg.Register(east.KindTableRow, r.renderTableRow)
+	reg.Register(east.KindTableCell, r.renderTableCell)
+}
+
+func isWordByte(c byte) bool {
+	return util.IsAlphaNumeric(c) || c >= 0x80
+}
+
+// Escape escapes characters those have special meanings in
+// Confluence wiki markup with backslashes. '-' and '+' are escaped only at
+// boundaries of words.
+func Escape(v []byte) []byte {
+	var b bytes.Buffer
+	for i, c := range v {
+		switch c {
+		case '\\', '{', '}', '[', ']', '|', '*', '_', '!', '^', '~':
+			b.WriteByte('\\')
+		case '-', '+':
+			if i == 0 || i == len(v)-1 || !isWordByte(v[i-1]) || !isWordByte(v[i+1]) {
+				b.WriteByte('\\')
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.Bytes()
+}
+
+// admonitionMacro returns a macro name if the given blockquote starts with
+// a GitHub style alert like '[!NOTE]', otherwise an empty string.
+func admonitionMacro(n ast.Node, source []byte) string {
+	p, ok := n.FirstChild().(*ast.Paragraph)
+	if n.Kind() != ast.KindBlockquote || !ok || p.Lines().Len() == 0 {
+		return ""
+	}
+	line := p.Lines().At(0)
+	marker := string(util.TrimRightSpace(util.TrimLeftSpace(line.Value(source))))
+	if !strings.HasPrefix(marker, "[!") || !strings.HasSuffix(marker, "]") {
+		return ""
+	}
+	return AdmonitionMacros[strings.ToUpper(marker[2:len(marker)-1])]
+}
+
+// isAdmonitionMarker returns true if the given text is a part of
+// an alert marker like '[!NOTE]'.
+func isAdmonitionMarker(n *ast.Text, source []byte) bool {
+	p, ok := n.Parent().(*ast.Paragraph)
+	if !ok || p.Parent() == nil || p.Parent().FirstChild() != p || admonitionMacro(p.Parent(), source) == "" {
+		return false
+	}
+	return n.Segment.Stop <= p.Lines().At(0).Stop
+}
+
+func inListItem(n ast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Kind() == ast.KindListItem {
+			return true
+		}
+	}
+	return false
+}
+
+// writeBlockStart writes a blank line between blocks.
+// Blocks in lists are not separated by blank lines because a blank line
+// ends a list.
+func (r *Renderer) writeBlockStart(w util.BufWriter, n ast.Node) {
+	if n.PreviousSibling() == nil || inListItem(n) || n.Parent().Kind() == ast.KindList {
+		return
+	}
+	_ = w.WriteByte('\n')
+}
+
+func (r *Renderer) renderDocument(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	if entering {
+		r.writeBlockStart(w, node)
+		_, _ = w.WriteString("h" + strconv.Itoa(n.Level) + ". ")
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	macro := admonitionMacro(node, source)
+	if macro == "" {
+		macro = "quote"
+	}
+	if entering {
+		r.writeBlockStart(w, node)
+	}
+	_, _ = w.WriteString("{" + macro + "}\n")
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockStart(w, node)
+	var language []byte
+	if n, ok := node.(*ast.FencedCodeBlock); ok {
+		language = n.Language(source)
+	}
+	if len(language) != 0 {
+		_, _ = w.WriteString("{code:language=")
+		_, _ = w.Write(language)
+		_, _ = w.WriteString("}\n")
+	} else {
+		_, _ = w.WriteString("{code}\n")
+	}
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		_, _ = w.Write(line.Value(source))
+	}
+	_, _ = w.WriteString("{code}\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderHTMLBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockStart(w, node)
+	_, _ = w.WriteString("{noformat}\n")
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		_, _ = w.Write(line.Value(source))
+	}
+	_, _ = w.WriteString("{noformat}\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var marker []byte
+	for p := node.Parent(); p != nil; p = p.Parent() {
+		if list, ok := p.(*ast.List); ok {
+			if list.IsOrdered() {
+				marker = append(marker, '#')
+			} else {
+				marker = append(marker, '*')
+			}
+		}
+	}
+	for i, j := 0, len(marker)-1; i < j; i, j = i+1, j-1 {
+		marker[i], marker[j] = marker[j], marker[i]
+	}
+	_, _ = w.Write(marker)
+	_ = w.WriteByte(' ')
+	if !node.HasChildren() {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node)
+		return ast.WalkContinue, nil
+	}
+	// blocks in a list item are written in a line
+	if next := node.NextSibling(); next != nil && node.Parent().Kind() == ast.KindListItem &&
+		next.Kind() != ast.KindList {
+		_, _ = w.WriteString(" \\\\ ")
+		return ast.WalkContinue, nil
+	}
+	_ = w.WriteByte('\n')
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node)
+		_, _ = w.WriteString("----\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTableRow(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		return ast.WalkContinue, nil
+	}
+	if node.Kind() == east.KindTableHeader {
+		_, _ = w.WriteString("||\n")
+	} else {
+		_, _ = w.WriteString("|\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTableCell(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if node.Parent().Kind() == east.KindTableHeader {
+		_, _ = w.WriteString("||")
+	} else {
+		_ = w.WriteByte('|')
+	}
+	// an empty cell needs a space to be a cell
+	if !node.HasChildren() {
+		_ = w.WriteByte(' ')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.AutoLink)
+		_ = w.WriteByte('[')
+		_, _ = w.Write(n.URL(source))
+		_ = w.WriteByte(']')
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderCodeSpan(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("{{")
+	for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+		var value []byte
+		switch t := c.(type) {
+		case *ast.Text:
+			value = t.Segment.Value(source)
+		case *ast.String:
+			value = t.Value
+		}
+		_, _ = w.Write(Escape(bytes.ReplaceAll(value, []byte("\n"), []byte(" "))))
+	}
+	_, _ = w.WriteString("}}")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Emphasis)
+	if n.Level == 2 {
+		_ = w.WriteByte('*')
+	} else {
+		_ = w.WriteByte('_')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	_ = w.WriteByte('-')
+	return ast.WalkContinue, nil
+}
+
+// unescape resolves backslash escapes and character references in texts.
+func unescape(v []byte) []byte {
+	return util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(v)))
+}
+
+// plainText returns texts under the given node without markups.
+func plainText(n ast.Node, source []byte) []byte {
+	var b []byte
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			b = append(b, unescape(t.Segment.Value(source))...)
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b = append(b, ' ')
+			}
+		case *ast.String:
+			b = append(b, t.Value...)
+		default:
+			b = append(b, plainText(c, source)...)
+		}
+	}
+	return b
+}
+
+func (r *Renderer) renderImage(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+	n := node.(*ast.Image)
+	_ = w.WriteByte('!')
+	_, _ = w.Write(n.Destination)
+	if alt := plainText(n, source); len(alt) != 0 {
+		_, _ = w.WriteString("|alt=")
+		_, _ = w.Write(Escape(alt))
+	}
+	_ = w.WriteByte('!')
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+	n := node.(*ast.Link)
+	_ = w.WriteByte('[')
+	if label := plainText(n, source); len(label) != 0 && !bytes.Equal(label, n.Destination) {
+		_, _ = w.Write(Escape(label))
+		_ = w.WriteByte('|')
+	}
+	_, _ = w.Write(n.Destination)
+	_ = w.WriteByte(']')
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderRawHTML(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.RawHTML)
+		for i := 0; i < n.Segments.Len(); i++ {
+			segment := n.Segments.At(i)
+			_, _ = w.Write(Escape(segment.Value(source)))
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderText(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	if isAdmonitionMarker(n, source) {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.Write(Escape(unescape(n.Segment.Value(source))))
+	if n.NextSibling() != nil {
+		// a newline is a line break in Confluence wiki markup
+		if n.HardLineBreak() {
+			_, _ = w.WriteString("\\\\ ")
+		} else if n.SoftLineBreak() {
+			_ = w.WriteByte(' ')
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.String)
+	if n.IsCode() {
+		_, _ = w.Write(n.Value)
+	} else {
+		_, _ = w.Write(Escape(n.Value))
+	}
+	return ast.WalkContinue, nil
+}
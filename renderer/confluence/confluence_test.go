@@ -0,0 +1,20 @@
+package confluence
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestRenderer(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(extension.Strikethrough, extension.Table),
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(NewRenderer(), 100)))),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/confluence.txt", t, testutil.ParseCliCaseArg()...)
+}
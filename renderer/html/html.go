@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/yuin/goldmark/ast"
@@ -19,8 +20,63 @@ type Config struct {
 	EastAsianLineBreaks bool
 	XHTML               bool
 	Unsafe              bool
+
+	// SafeMode is a stricter mode than !Unsafe. See WithSafeMode.
+	SafeMode bool
+
+	// CSPNonce is a nonce of the Content-Security-Policy that is added to
+	// script and style elements rendered by extensions.
+	CSPNonce string
+
+	// EntityResolver resolves entity references before the HTML5 entities.
+	// See WithEntityResolver.
+	EntityResolver func(name string) ([]byte, bool)
+
+	// URLSanitizer reports whether the given link or image URL is safe.
+	// URLSanitizer receives URLs after entity references are resolved and
+	// unsafe characters are percent-encoded.
+	// URLs are not sanitized if URLSanitizer is nil.
+	URLSanitizer func(url string) bool
+
+	// URLSanitizerFallback is written instead of URLs those are rejected by
+	// URLSanitizer.
+	URLSanitizerFallback string
+
+	// CodeLanguagePrefix is a prefix of class names of fenced code blocks.
+	// See WithCodeLanguagePrefix.
+	CodeLanguagePrefix string
+
+	// CodeClassFunc returns a class name of fenced code blocks.
+	// See WithCodeClassFunc.
+	CodeClassFunc func(lang string) string
+
+	// HeadingAnchor is a position of anchor links of headings.
+	// See WithHeadingAnchors.
+	HeadingAnchor AnchorPosition
+
+	// HeadingAnchorSymbol is a text of anchor links of headings.
+	HeadingAnchorSymbol string
+
+	// VoidElementStyle is a style of void elements like '<br>'.
+	// See WithVoidElementStyle.
+	VoidElementStyle VoidElementStyle
+
+	// WrapperTag is a tag name of an element that wraps whole documents.
+	// Documents are not wrapped if WrapperTag is empty. See WithWrapper.
+	WrapperTag string
+
+	// WrapperClass is a class name of the wrapper element.
+	WrapperClass string
+
+	// BlockquoteCite returns a cite attribute value of blockquotes.
+	// See WithBlockquoteCite.
+	BlockquoteCite func(blockquote ast.Node, source []byte) string
 }
 
+// DefaultCodeLanguagePrefix is a default prefix of class names of
+// fenced code blocks.
+const DefaultCodeLanguagePrefix = "language-"
+
 // NewConfig returns a new Config with defaults.
 func NewConfig() Config {
 	return Config{
@@ -29,6 +85,7 @@ func NewConfig() Config {
 		EastAsianLineBreaks: false,
 		XHTML:               false,
 		Unsafe:              false,
+		CodeLanguagePrefix:  DefaultCodeLanguagePrefix,
 	}
 }
 
@@ -45,6 +102,74 @@ func (c *Config) SetOption(name renderer.OptionName, value interface{}) {
 		c.Unsafe = value.(bool)
 	case optTextWriter:
 		c.Writer = value.(Writer)
+	case optURLSanitizer:
+		c.URLSanitizer = value.(func(string) bool)
+	case optURLSanitizerFallback:
+		c.URLSanitizerFallback = value.(string)
+	case optSafeMode:
+		c.SafeMode = value.(bool)
+	case optCSPNonce:
+		c.CSPNonce = value.(string)
+	case optEntityResolver:
+		c.EntityResolver = value.(func(string) ([]byte, bool))
+	case optCodeLanguagePrefix:
+		c.CodeLanguagePrefix = value.(string)
+	case optCodeClassFunc:
+		c.CodeClassFunc = value.(func(string) string)
+	case optHeadingAnchors:
+		v := value.(headingAnchors)
+		c.HeadingAnchor = v.position
+		c.HeadingAnchorSymbol = v.symbol
+	case optVoidElementStyle:
+		c.VoidElementStyle = value.(VoidElementStyle)
+	case optWrapper:
+		v := value.(wrapper)
+		c.WrapperTag = v.tag
+		c.WrapperClass = v.class
+	case optBlockquoteCite:
+		c.BlockquoteCite = value.(func(ast.Node, []byte) string)
+	}
+	c.applyEntityResolver()
+	c.applySafeMode()
+}
+
+// applyEntityResolver sets the EntityResolver to the default Writer.
+func (c *Config) applyEntityResolver() {
+	if c.EntityResolver == nil || c.SafeMode {
+		return
+	}
+	w := c.Writer
+	sw, safe := w.(*safeModeWriter)
+	if safe {
+		w = sw.Writer
+	}
+	d, ok := w.(*defaultWriter)
+	if !ok {
+		return
+	}
+	nd := *d
+	nd.EntityResolver = c.EntityResolver
+	if safe {
+		c.Writer = &safeModeWriter{&nd}
+	} else {
+		c.Writer = &nd
+	}
+}
+
+// applySafeMode disables options those conflict with SafeMode.
+func (c *Config) applySafeMode() {
+	if !c.SafeMode {
+		return
+	}
+	c.Unsafe = false
+	if _, ok := c.Writer.(*safeModeWriter); !ok && c.Writer != nil {
+		w := c.Writer
+		if d, ok := w.(*defaultWriter); ok && d.EntityResolver != nil {
+			nd := *d
+			nd.EntityResolver = nil
+			w = &nd
+		}
+		c.Writer = &safeModeWriter{w}
 	}
 }
 
@@ -66,6 +191,8 @@ func (o *withWriter) SetConfig(c *renderer.Config) {
 
 func (o *withWriter) SetHTMLOption(c *Config) {
 	c.Writer = o.value
+	c.applyEntityResolver()
+	c.applySafeMode()
 }
 
 // WithWriter is a functional option that allow you to set the given writer to
@@ -158,6 +285,7 @@ func (o *withUnsafe) SetConfig(c *renderer.Config) {
 
 func (o *withUnsafe) SetHTMLOption(c *Config) {
 	c.Unsafe = true
+	c.applySafeMode()
 }
 
 // WithUnsafe is a functional option that renders dangerous contents
@@ -169,6 +297,397 @@ func WithUnsafe() interface {
 	return &withUnsafe{}
 }
 
+// SafeMode is an option name used in WithSafeMode.
+const optSafeMode renderer.OptionName = "SafeMode"
+
+type withSafeMode struct {
+}
+
+func (o *withSafeMode) SetConfig(c *renderer.Config) {
+	c.Options[optSafeMode] = true
+}
+
+func (o *withSafeMode) SetHTMLOption(c *Config) {
+	c.SafeMode = true
+	c.applySafeMode()
+}
+
+// WithSafeMode is a functional option that renders untrusted contents
+// more strictly than the default.
+//
+// By default (without WithUnsafe), raw HTML is replaced with an HTML
+// comment and potentially dangerous links are dropped, but entity
+// references like '&lt;' in texts are resolved. In safe mode:
+//
+//   - WithUnsafe is ignored.
+//   - Raw HTML is removed without leaving any comments.
+//   - Entity references in texts and titles are not resolved and
+//     are rendered as written like '&amp;lt;'.
+func WithSafeMode() interface {
+	renderer.Option
+	Option
+} {
+	return &withSafeMode{}
+}
+
+// CSPNonce is an option name used in WithCSPNonce.
+const optCSPNonce renderer.OptionName = "CSPNonce"
+
+type withCSPNonce struct {
+	value string
+}
+
+func (o *withCSPNonce) SetConfig(c *renderer.Config) {
+	c.Options[optCSPNonce] = o.value
+}
+
+func (o *withCSPNonce) SetHTMLOption(c *Config) {
+	c.CSPNonce = o.value
+}
+
+// WithCSPNonce is a functional option that adds a nonce attribute like
+// nonce="value" to script and style elements rendered by extensions.
+// Extensions should write the attribute by Config.RenderCSPNonce.
+// Raw HTML written by authors never has the nonce.
+//
+// A nonce must be unique for each response. Because options are fixed
+// when a Markdown is created, set a nonce of each response to the
+// document metadata with CSPNonceMetaKey instead of this option when
+// you share a Markdown between requests.
+func WithCSPNonce(nonce string) interface {
+	renderer.Option
+	Option
+} {
+	return &withCSPNonce{nonce}
+}
+
+// EntityResolver is an option name used in WithEntityResolver.
+const optEntityResolver renderer.OptionName = "EntityResolver"
+
+type withEntityResolver struct {
+	value func(name string) ([]byte, bool)
+}
+
+func (o *withEntityResolver) SetConfig(c *renderer.Config) {
+	c.Options[optEntityResolver] = o.value
+}
+
+func (o *withEntityResolver) SetHTMLOption(c *Config) {
+	c.EntityResolver = o.value
+	c.applyEntityResolver()
+	c.applySafeMode()
+}
+
+// WithEntityResolver is a functional option that resolves entity references
+// like '&name;' in texts by the given function before the HTML5 entities.
+// The function receives a name without '&' and ';', and returns characters
+// of the entity. Entities those are not resolved by the function are
+// resolved as HTML5 entities.
+//
+// This option works with the default Writer. Use WithEntityResolverWriter
+// for Writers created by NewWriter.
+func WithEntityResolver(resolver func(name string) ([]byte, bool)) interface {
+	renderer.Option
+	Option
+} {
+	return &withEntityResolver{resolver}
+}
+
+// CodeLanguagePrefix is an option name used in WithCodeLanguagePrefix.
+const optCodeLanguagePrefix renderer.OptionName = "CodeLanguagePrefix"
+
+type withCodeLanguagePrefix struct {
+	value string
+}
+
+func (o *withCodeLanguagePrefix) SetConfig(c *renderer.Config) {
+	c.Options[optCodeLanguagePrefix] = o.value
+}
+
+func (o *withCodeLanguagePrefix) SetHTMLOption(c *Config) {
+	c.CodeLanguagePrefix = o.value
+}
+
+// WithCodeLanguagePrefix is a functional option that specifies a prefix of
+// class names of fenced code blocks like 'lang-'. Defaults to
+// DefaultCodeLanguagePrefix.
+func WithCodeLanguagePrefix(prefix string) interface {
+	renderer.Option
+	Option
+} {
+	return &withCodeLanguagePrefix{prefix}
+}
+
+// CodeClassFunc is an option name used in WithCodeClassFunc.
+const optCodeClassFunc renderer.OptionName = "CodeClassFunc"
+
+type withCodeClassFunc struct {
+	value func(lang string) string
+}
+
+func (o *withCodeClassFunc) SetConfig(c *renderer.Config) {
+	c.Options[optCodeClassFunc] = o.value
+}
+
+func (o *withCodeClassFunc) SetHTMLOption(c *Config) {
+	c.CodeClassFunc = o.value
+}
+
+// WithCodeClassFunc is a functional option that specifies a function
+// returns a class attribute value of fenced code blocks from the language
+// of the block. The class attribute is omitted if the function returns an
+// empty string. This option takes precedence over WithCodeLanguagePrefix.
+func WithCodeClassFunc(f func(lang string) string) interface {
+	renderer.Option
+	Option
+} {
+	return &withCodeClassFunc{f}
+}
+
+// AnchorPosition is a position of anchor links of headings.
+type AnchorPosition int
+
+const (
+	// AnchorNone does not render anchor links.
+	AnchorNone AnchorPosition = iota
+
+	// AnchorLeft renders anchor links before texts of headings.
+	AnchorLeft
+
+	// AnchorRight renders anchor links after texts of headings.
+	AnchorRight
+)
+
+// HeadingAnchors is an option name used in WithHeadingAnchors.
+const optHeadingAnchors renderer.OptionName = "HeadingAnchors"
+
+type headingAnchors struct {
+	position AnchorPosition
+	symbol   string
+}
+
+type withHeadingAnchors struct {
+	value headingAnchors
+}
+
+func (o *withHeadingAnchors) SetConfig(c *renderer.Config) {
+	c.Options[optHeadingAnchors] = o.value
+}
+
+func (o *withHeadingAnchors) SetHTMLOption(c *Config) {
+	c.HeadingAnchor = o.value.position
+	c.HeadingAnchorSymbol = o.value.symbol
+}
+
+// WithHeadingAnchors is a functional option that renders anchor links like
+// '<a class="heading-anchor" href="#id" aria-hidden="true">symbol</a>'
+// inside headings at the given position. The symbol is written as a text
+// like '¶' or '#'. Only headings those have ids have anchor links, so
+// enable ids by parser.WithAutoHeadingID or parser.WithAttribute.
+func WithHeadingAnchors(position AnchorPosition, symbol string) interface {
+	renderer.Option
+	Option
+} {
+	return &withHeadingAnchors{headingAnchors{position, symbol}}
+}
+
+// VoidElementStyle is a style of void elements like '<br>' and '<hr>'.
+type VoidElementStyle int
+
+const (
+	// VoidElementDefault renders void elements like VoidElementXHTML if
+	// WithXHTML is given, otherwise like VoidElementHTML5.
+	VoidElementDefault VoidElementStyle = iota
+
+	// VoidElementHTML5 renders void elements like '<br>'.
+	VoidElementHTML5
+
+	// VoidElementXHTML renders void elements like '<br />'.
+	VoidElementXHTML
+
+	// VoidElementXMLSelfClose renders void elements like '<br/>'.
+	VoidElementXMLSelfClose
+)
+
+// VoidElementStyle is an option name used in WithVoidElementStyle.
+const optVoidElementStyle renderer.OptionName = "VoidElementStyle"
+
+type withVoidElementStyle struct {
+	value VoidElementStyle
+}
+
+func (o *withVoidElementStyle) SetConfig(c *renderer.Config) {
+	c.Options[optVoidElementStyle] = o.value
+}
+
+func (o *withVoidElementStyle) SetHTMLOption(c *Config) {
+	c.VoidElementStyle = o.value
+}
+
+// WithVoidElementStyle is a functional option that specifies how void
+// elements like '<br>', '<hr>', '<img>' and '<input>' are closed,
+// regardless of WithXHTML. Other XHTML outputs like align attributes of
+// table cells still follow WithXHTML.
+func WithVoidElementStyle(style VoidElementStyle) interface {
+	renderer.Option
+	Option
+} {
+	return &withVoidElementStyle{style}
+}
+
+// VoidElementEnd returns an end of void elements like '>' and ' />'.
+func (c *Config) VoidElementEnd() string {
+	switch c.VoidElementStyle {
+	case VoidElementHTML5:
+		return ">"
+	case VoidElementXHTML:
+		return " />"
+	case VoidElementXMLSelfClose:
+		return "/>"
+	}
+	if c.XHTML {
+		return " />"
+	}
+	return ">"
+}
+
+// Wrapper is an option name used in WithWrapper.
+const optWrapper renderer.OptionName = "Wrapper"
+
+type wrapper struct {
+	tag   string
+	class string
+}
+
+type withWrapper struct {
+	value wrapper
+}
+
+func (o *withWrapper) SetConfig(c *renderer.Config) {
+	c.Options[optWrapper] = o.value
+}
+
+func (o *withWrapper) SetHTMLOption(c *Config) {
+	c.WrapperTag = o.value.tag
+	c.WrapperClass = o.value.class
+}
+
+// WithWrapper is a functional option that wraps whole documents in an
+// element like '<div class="markdown-body">'. The class is added to
+// a class attribute of the Document node if it has one, and other
+// attributes of the Document node are also rendered on the element.
+// The class may be empty. Documents are not wrapped if the tag is empty.
+func WithWrapper(tag, class string) interface {
+	renderer.Option
+	Option
+} {
+	return &withWrapper{wrapper{tag, class}}
+}
+
+// BlockquoteCite is an option name used in WithBlockquoteCite.
+const optBlockquoteCite renderer.OptionName = "BlockquoteCite"
+
+type withBlockquoteCite struct {
+	value func(blockquote ast.Node, source []byte) string
+}
+
+func (o *withBlockquoteCite) SetConfig(c *renderer.Config) {
+	c.Options[optBlockquoteCite] = o.value
+}
+
+func (o *withBlockquoteCite) SetHTMLOption(c *Config) {
+	c.BlockquoteCite = o.value
+}
+
+// WithBlockquoteCite is a functional option that specifies a function
+// returns a source URL of blockquotes like a link in an attribution line.
+// The URL is rendered as a cite attribute of the blockquote and is
+// checked like link destinations. The cite attribute is omitted if the
+// function returns an empty string. Blockquotes those already have a cite
+// attribute keep it.
+func WithBlockquoteCite(f func(blockquote ast.Node, source []byte) string) interface {
+	renderer.Option
+	Option
+} {
+	return &withBlockquoteCite{f}
+}
+
+// CSPNonceMetaKey is a key of the document metadata that holds a nonce of
+// the Content-Security-Policy. A nonce in the metadata takes precedence
+// over a nonce given by WithCSPNonce.
+const CSPNonceMetaKey = "CSPNonce"
+
+// RenderCSPNonce writes a nonce attribute like ` nonce="value"` if
+// a nonce is given by the metadata of the document that owns the node
+// or WithCSPNonce.
+func (c *Config) RenderCSPNonce(w util.BufWriter, node ast.Node) {
+	nonce := c.CSPNonce
+	if doc := node.OwnerDocument(); doc != nil {
+		if v, ok := doc.Meta()[CSPNonceMetaKey].(string); ok {
+			nonce = v
+		}
+	}
+	if len(nonce) == 0 {
+		return
+	}
+	_, _ = w.WriteString(` nonce="`)
+	_, _ = w.Write(util.EscapeHTML([]byte(nonce)))
+	_ = w.WriteByte('"')
+}
+
+// URLSanitizer is an option name used in WithURLSanitizer.
+const optURLSanitizer renderer.OptionName = "URLSanitizer"
+
+type withURLSanitizer struct {
+	value func(url string) bool
+}
+
+func (o *withURLSanitizer) SetConfig(c *renderer.Config) {
+	c.Options[optURLSanitizer] = o.value
+}
+
+func (o *withURLSanitizer) SetHTMLOption(c *Config) {
+	c.URLSanitizer = o.value
+}
+
+// WithURLSanitizer is a functional option that specifies a function reports
+// whether URLs of links, autolinks and images are safe. Rejected URLs are
+// replaced with an empty string or a value specified by
+// WithURLSanitizerFallback. URLs are sanitized even if WithUnsafe is enabled.
+//
+// It is recommended to use DefaultURLSanitizer when you render
+// untrusted contents.
+func WithURLSanitizer(value func(url string) bool) interface {
+	renderer.Option
+	Option
+} {
+	return &withURLSanitizer{value}
+}
+
+// URLSanitizerFallback is an option name used in WithURLSanitizerFallback.
+const optURLSanitizerFallback renderer.OptionName = "URLSanitizerFallback"
+
+type withURLSanitizerFallback struct {
+	value string
+}
+
+func (o *withURLSanitizerFallback) SetConfig(c *renderer.Config) {
+	c.Options[optURLSanitizerFallback] = o.value
+}
+
+func (o *withURLSanitizerFallback) SetHTMLOption(c *Config) {
+	c.URLSanitizerFallback = o.value
+}
+
+// WithURLSanitizerFallback is a functional option that specifies an URL that
+// is written instead of URLs rejected by the URLSanitizer like '#'.
+func WithURLSanitizerFallback(value string) interface {
+	renderer.Option
+	Option
+} {
+	return &withURLSanitizerFallback{value}
+}
+
 // A Renderer struct is an implementation of renderer.NodeRenderer that renders
 // nodes as (X)HTML.
 type Renderer struct {
@@ -254,9 +773,49 @@ var GlobalAttributeFilter = util.NewBytesFilter(
 	[]byte("translate"),
 )
 
+// WrapperAttributeFilter defines attribute names which wrapper elements
+// given by WithWrapper can have.
+var WrapperAttributeFilter = GlobalAttributeFilter
+
 func (r *Renderer) renderDocument(
 	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
-	// nothing to do
+	if len(r.WrapperTag) == 0 {
+		return ast.WalkContinue, nil
+	}
+	if !entering {
+		_, _ = w.WriteString("</")
+		_, _ = w.WriteString(r.WrapperTag)
+		_, _ = w.WriteString(">\n")
+		return ast.WalkContinue, nil
+	}
+	_ = w.WriteByte('<')
+	_, _ = w.WriteString(r.WrapperTag)
+	class := []byte(r.WrapperClass)
+	if v, ok := node.AttributeString("class"); ok {
+		if len(class) != 0 {
+			class = append(class, ' ')
+		}
+		class = append(class, v.([]byte)...)
+	}
+	if len(class) != 0 {
+		_, _ = w.WriteString(` class="`)
+		_, _ = w.Write(util.EscapeHTML(class))
+		_ = w.WriteByte('"')
+	}
+	for _, attr := range node.Attributes() {
+		if bytes.Equal(attr.Name, []byte("class")) {
+			continue
+		}
+		if !WrapperAttributeFilter.Contains(attr.Name) && !bytes.HasPrefix(attr.Name, dataPrefix) {
+			continue
+		}
+		_ = w.WriteByte(' ')
+		_, _ = w.Write(attr.Name)
+		_, _ = w.WriteString(`="`)
+		_, _ = w.Write(util.EscapeHTML(attr.Value.([]byte)))
+		_ = w.WriteByte('"')
+	}
+	_, _ = w.WriteString(">\n")
 	return ast.WalkContinue, nil
 }
 
@@ -273,7 +832,13 @@ func (r *Renderer) renderHeading(
 			RenderAttributes(w, node, HeadingAttributeFilter)
 		}
 		_ = w.WriteByte('>')
+		if r.HeadingAnchor == AnchorLeft {
+			r.renderHeadingAnchor(w, n)
+		}
 	} else {
+		if r.HeadingAnchor == AnchorRight {
+			r.renderHeadingAnchor(w, n)
+		}
 		_, _ = w.WriteString("</h")
 		_ = w.WriteByte("0123456"[n.Level])
 		_, _ = w.WriteString(">\n")
@@ -281,6 +846,30 @@ func (r *Renderer) renderHeading(
 	return ast.WalkContinue, nil
 }
 
+// renderHeadingAnchor writes an anchor link to the given heading if
+// the heading has an id.
+func (r *Renderer) renderHeadingAnchor(w util.BufWriter, n *ast.Heading) {
+	id, ok := n.AttributeString("id")
+	if !ok {
+		return
+	}
+	value, ok := id.([]byte)
+	if !ok || len(value) == 0 {
+		return
+	}
+	if r.HeadingAnchor == AnchorRight {
+		_ = w.WriteByte(' ')
+	}
+	_, _ = w.WriteString(`<a class="heading-anchor" href="#`)
+	_, _ = w.Write(util.EscapeHTML(util.URLEscape(value, false)))
+	_, _ = w.WriteString(`" aria-hidden="true">`)
+	_, _ = w.Write(util.EscapeHTML([]byte(r.HeadingAnchorSymbol)))
+	_, _ = w.WriteString("</a>")
+	if r.HeadingAnchor == AnchorLeft {
+		_ = w.WriteByte(' ')
+	}
+}
+
 // BlockquoteAttributeFilter defines attribute names which blockquote elements can have.
 var BlockquoteAttributeFilter = GlobalAttributeFilter.Extend(
 	[]byte("cite"),
@@ -289,12 +878,13 @@ var BlockquoteAttributeFilter = GlobalAttributeFilter.Extend(
 func (r *Renderer) renderBlockquote(
 	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
 	if entering {
+		_, _ = w.WriteString("<blockquote")
+		r.renderBlockquoteCite(w, source, n)
 		if n.Attributes() != nil {
-			_, _ = w.WriteString("<blockquote")
 			RenderAttributes(w, n, BlockquoteAttributeFilter)
 			_ = w.WriteByte('>')
 		} else {
-			_, _ = w.WriteString("<blockquote>\n")
+			_, _ = w.WriteString(">\n")
 		}
 	} else {
 		_, _ = w.WriteString("</blockquote>\n")
@@ -302,6 +892,23 @@ func (r *Renderer) renderBlockquote(
 	return ast.WalkContinue, nil
 }
 
+// renderBlockquoteCite writes a cite attribute given by BlockquoteCite.
+func (r *Renderer) renderBlockquoteCite(w util.BufWriter, source []byte, n ast.Node) {
+	if r.BlockquoteCite == nil {
+		return
+	}
+	if _, ok := n.AttributeString("cite"); ok {
+		return
+	}
+	cite := []byte(r.BlockquoteCite(n, source))
+	if len(cite) == 0 || (!r.Unsafe && IsDangerousURL(cite)) {
+		return
+	}
+	_, _ = w.WriteString(` cite="`)
+	_, _ = w.Write(util.EscapeHTML(r.sanitizeURL(util.URLEscape(cite, true))))
+	_ = w.WriteByte('"')
+}
+
 func (r *Renderer) renderCodeBlock(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
 	if entering {
 		_, _ = w.WriteString("<pre><code>")
@@ -318,10 +925,19 @@ func (r *Renderer) renderFencedCodeBlock(
 	if entering {
 		_, _ = w.WriteString("<pre><code")
 		language := n.Language(source)
-		if language != nil {
-			_, _ = w.WriteString(" class=\"language-")
+		if r.CodeClassFunc != nil {
+			if language != nil {
+				if class := r.CodeClassFunc(string(language)); len(class) != 0 {
+					_, _ = w.WriteString(" class=\"")
+					_, _ = w.Write(util.EscapeHTML([]byte(class)))
+					_ = w.WriteByte('"')
+				}
+			}
+		} else if language != nil {
+			_, _ = w.WriteString(" class=\"")
+			_, _ = w.Write(util.EscapeHTML([]byte(r.CodeLanguagePrefix)))
 			r.Writer.Write(w, language)
-			_, _ = w.WriteString("\"")
+			_ = w.WriteByte('"')
 		}
 		_ = w.WriteByte('>')
 		r.writeLines(w, source, n)
@@ -341,7 +957,7 @@ func (r *Renderer) renderHTMLBlock(
 				line := n.Lines().At(i)
 				r.Writer.SecureWrite(w, line.Value(source))
 			}
-		} else {
+		} else if !r.SafeMode {
 			_, _ = w.WriteString("<!-- raw HTML omitted -->\n")
 		}
 	} else {
@@ -349,7 +965,7 @@ func (r *Renderer) renderHTMLBlock(
 			if r.Unsafe {
 				closure := n.ClosureLine
 				r.Writer.SecureWrite(w, closure.Value(source))
-			} else {
+			} else if !r.SafeMode {
 				_, _ = w.WriteString("<!-- raw HTML omitted -->\n")
 			}
 		}
@@ -459,11 +1075,8 @@ func (r *Renderer) renderThematicBreak(
 	if n.Attributes() != nil {
 		RenderAttributes(w, n, ThematicAttributeFilter)
 	}
-	if r.XHTML {
-		_, _ = w.WriteString(" />\n")
-	} else {
-		_, _ = w.WriteString(">\n")
-	}
+	_, _ = w.WriteString(r.VoidElementEnd())
+	_ = w.WriteByte('\n')
 	return ast.WalkContinue, nil
 }
 
@@ -490,9 +1103,9 @@ func (r *Renderer) renderAutoLink(
 	url := n.URL(source)
 	label := n.Label(source)
 	if n.AutoLinkType == ast.AutoLinkEmail && !bytes.HasPrefix(bytes.ToLower(url), []byte("mailto:")) {
-		_, _ = w.WriteString("mailto:")
+		url = append([]byte("mailto:"), url...)
 	}
-	_, _ = w.Write(util.EscapeHTML(util.URLEscape(url, false)))
+	_, _ = w.Write(util.EscapeHTML(r.sanitizeURL(util.URLEscape(url, false))))
 	if n.Attributes() != nil {
 		_ = w.WriteByte('"')
 		RenderAttributes(w, n, LinkAttributeFilter)
@@ -563,7 +1176,7 @@ func (r *Renderer) renderLink(w util.BufWriter, source []byte, node ast.Node, en
 	if entering {
 		_, _ = w.WriteString("<a href=\"")
 		if r.Unsafe || !IsDangerousURL(n.Destination) {
-			_, _ = w.Write(util.EscapeHTML(util.URLEscape(n.Destination, true)))
+			_, _ = w.Write(util.EscapeHTML(r.sanitizeURL(util.URLEscape(n.Destination, true))))
 		}
 		_ = w.WriteByte('"')
 		if n.Title != nil {
@@ -606,7 +1219,7 @@ func (r *Renderer) renderImage(w util.BufWriter, source []byte, node ast.Node, e
 	n := node.(*ast.Image)
 	_, _ = w.WriteString("<img src=\"")
 	if r.Unsafe || !IsDangerousURL(n.Destination) {
-		_, _ = w.Write(util.EscapeHTML(util.URLEscape(n.Destination, true)))
+		_, _ = w.Write(util.EscapeHTML(r.sanitizeURL(util.URLEscape(n.Destination, true))))
 	}
 	_, _ = w.WriteString(`" alt="`)
 	_, _ = w.Write(nodeToHTMLText(n, source))
@@ -619,11 +1232,7 @@ func (r *Renderer) renderImage(w util.BufWriter, source []byte, node ast.Node, e
 	if n.Attributes() != nil {
 		RenderAttributes(w, n, ImageAttributeFilter)
 	}
-	if r.XHTML {
-		_, _ = w.WriteString(" />")
-	} else {
-		_, _ = w.WriteString(">")
-	}
+	_, _ = w.WriteString(r.VoidElementEnd())
 	return ast.WalkSkipChildren, nil
 }
 
@@ -641,7 +1250,9 @@ func (r *Renderer) renderRawHTML(
 		}
 		return ast.WalkSkipChildren, nil
 	}
-	_, _ = w.WriteString("<!-- raw HTML omitted -->")
+	if !r.SafeMode {
+		_, _ = w.WriteString("<!-- raw HTML omitted -->")
+	}
 	return ast.WalkSkipChildren, nil
 }
 
@@ -657,11 +1268,9 @@ func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, en
 		value := segment.Value(source)
 		r.Writer.Write(w, value)
 		if n.HardLineBreak() || (n.SoftLineBreak() && r.HardWraps) {
-			if r.XHTML {
-				_, _ = w.WriteString("<br />\n")
-			} else {
-				_, _ = w.WriteString("<br>\n")
-			}
+			_, _ = w.WriteString("<br")
+			_, _ = w.WriteString(r.VoidElementEnd())
+			_ = w.WriteByte('\n')
 		} else if n.SoftLineBreak() {
 			if r.EastAsianLineBreaks && len(value) != 0 {
 				sibling := node.NextSibling()
@@ -741,6 +1350,9 @@ var replacementCharacter = []byte("\ufffd")
 type WriterConfig struct {
 	// EscapedSpace is an option that indicates that a '\' escaped half-space(0x20) should not be rendered.
 	EscapedSpace bool
+
+	// EntityResolver resolves entity references before the HTML5 entities.
+	EntityResolver func(name string) ([]byte, bool)
 }
 
 // A WriterOption interface sets options for HTML based writers.
@@ -753,6 +1365,14 @@ func WithEscapedSpace() WriterOption {
 	}
 }
 
+// WithEntityResolverWriter is a WriterOption indicates that entity references
+// should be resolved by the given function before the HTML5 entities.
+func WithEntityResolverWriter(resolver func(name string) ([]byte, bool)) WriterOption {
+	return func(c *WriterConfig) {
+		c.EntityResolver = resolver
+	}
+}
+
 type defaultWriter struct {
 	WriterConfig
 }
@@ -877,6 +1497,14 @@ func (d *defaultWriter) Write(writer util.BufWriter, source []byte) {
 				// entity reference
 				if ok && i < limit && source[i] == ';' {
 					name := util.BytesToReadOnlyString(source[start:i])
+					if d.EntityResolver != nil {
+						if v, ok := d.EntityResolver(name); ok {
+							d.RawWrite(writer, source[n:pos])
+							n = i + 1
+							d.RawWrite(writer, v)
+							continue
+						}
+					}
 					entity, ok := util.LookUpHTML5EntityByName(name)
 					if ok {
 						d.RawWrite(writer, source[n:pos])
@@ -897,6 +1525,34 @@ func (d *defaultWriter) Write(writer util.BufWriter, source []byte) {
 	d.RawWrite(writer, source[n:])
 }
 
+// safeModeWriter is a Writer that does not resolve entity references.
+type safeModeWriter struct {
+	Writer
+}
+
+var bAmp = []byte("&amp;")
+
+// Write implements Writer.Write. Write escapes '&' those are not escaped
+// by backslashes so that the underlying Writer writes entity references
+// as they are.
+func (s *safeModeWriter) Write(writer util.BufWriter, source []byte) {
+	if bytes.IndexByte(source, '&') < 0 {
+		s.Writer.Write(writer, source)
+		return
+	}
+	buf := make([]byte, 0, len(source)+16)
+	escaped := false
+	for _, c := range source {
+		if c == '&' && !escaped {
+			buf = append(buf, bAmp...)
+		} else {
+			buf = append(buf, c)
+		}
+		escaped = c == '\\' && !escaped
+	}
+	s.Writer.Write(writer, buf)
+}
+
 // DefaultWriter is a default instance of the Writer.
 var DefaultWriter = NewWriter()
 
@@ -931,6 +1587,35 @@ func IsDangerousURL(url []byte) bool {
 		hasPrefix(url, bFile) || hasPrefix(url, bData)
 }
 
+// DefaultURLSanitizer returns true if the given url is a relative URL or
+// an absolute URL that has one of 'http', 'https', 'ftp', 'ftps' and 'mailto'
+// schemes, otherwise false.
+//
+// URLs those have a malformed scheme like 'java%09script:' are rejected
+// because browsers ignore some characters in schemes.
+func DefaultURLSanitizer(url string) bool {
+	i := strings.IndexAny(url, ":/?#")
+	if i < 0 || url[i] != ':' {
+		return true
+	}
+	return isAllowedURLScheme(url[:i])
+}
+
+func isAllowedURLScheme(scheme string) bool {
+	switch strings.ToLower(scheme) {
+	case "http", "https", "ftp", "ftps", "mailto":
+		return true
+	}
+	return false
+}
+
+func (r *Renderer) sanitizeURL(url []byte) []byte {
+	if r.URLSanitizer == nil || r.URLSanitizer(string(url)) {
+		return url
+	}
+	return []byte(r.URLSanitizerFallback)
+}
+
 func nodeToHTMLText(n ast.Node, source []byte) []byte {
 	var buf bytes.Buffer
 	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
@@ -0,0 +1,471 @@
+// Package mediawiki implements renderer that outputs MediaWiki markup.
+//
+// Links to absolute URLs are rendered as external links like
+// '[https://example.com Text]' and other links are rendered as internal
+// links like '[[Target|Text]]'.
+package mediawiki
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that
+// renders nodes as MediaWiki markup.
+type Renderer struct {
+}
+
+// NewRenderer returns a new Renderer.
+// The Renderer should be registered with a priority smaller than 500
+// to render nodes of extensions like Table.
+func NewRenderer() renderer.NodeRenderer {
+	return &Renderer{}
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	// blocks
+
+	reg.Register(ast.KindDocument, r.renderDocument)
+	reg.Register(ast.KindHeading, r.renderHeading)
+	reg.Register(ast.KindBlockquote, r.renderBlockquote)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindHTMLBlock, r.renderHTMLBlock)
+	reg.Register(ast.KindList, r.renderList)
+	reg.Register(ast.KindListItem, r.renderListItem)
+	reg.Register(ast.KindParagraph, r.renderParagraph)
+	reg.Register(ast.KindTextBlock, r.renderParagraph)
+	reg.Register(ast.KindThematicBreak, r.renderThematicBreak)
+
+	// inlines
+
+	reg.Register(ast.KindAutoLink, r.renderAutoLink)
+	reg.Register(ast.KindCodeSpan, r.renderCodeSpan)
+	reg.Register(ast.KindEmphasis, r.renderEmphasis)
+	reg.Register(ast.KindImage, r.renderImage)
+	reg.Register(ast.KindLink, r.renderLink)
+	reg.Register(ast.KindRawHTML, r.renderRawHTML)
+	reg.Register(ast.KindText, r.renderText)
+	reg.Register(ast.KindString, r.renderString)
+
+	// GFM extensions
+
+	reg.Register(east.KindStrikethrough, r.renderStrikethrough)
+	reg.Register(east.KindTable, r.renderTable)
+	reg.Register(east.KindTableHeader, r.renderTableRow)
+	reg.Register(east.KindTableRow, r.renderTableRow)
+	reg.Register(east.KindTableCell, r.renderTableCell)
+}
+
+var escapeTable = map[byte]string{
+	'&':  "&amp;",
+	'<':  "&lt;",
+	'>':  "&gt;",
+	'\'': "&#39;",
+	'[':  "&#91;",
+	']':  "&#93;",
+	'{':  "&#123;",
+	'|':  "&#124;",
+	'}':  "&#125;",
+	'~':  "&#126;",
+}
+
+// Escape escapes characters those have special meanings in MediaWiki
+// markup with HTML character references.
+func Escape(v []byte) []byte {
+	var b bytes.Buffer
+	for _, c := range v {
+		if s, ok := escapeTable[c]; ok {
+			b.WriteString(s)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.Bytes()
+}
+
+// escapeLineStart escapes characters those have special meanings at the
+// beginning of lines.
+func escapeLineStart(v []byte) []byte {
+	if len(v) != 0 && strings.IndexByte("*#:;=-", v[0]) > -1 {
+		return append([]byte("&#"+strconv.Itoa(int(v[0]))+";"), v[1:]...)
+	}
+	return v
+}
+
+// unescape resolves backslash escapes and character references in texts.
+func unescape(v []byte) []byte {
+	return util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(v)))
+}
+
+func inListItem(n ast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Kind() == ast.KindListItem {
+			return true
+		}
+	}
+	return false
+}
+
+// writeBlockStart writes a blank line between blocks.
+// Blocks in lists are not separated by blank lines because a blank line
+// ends a list.
+func (r *Renderer) writeBlockStart(w util.BufWriter, n ast.Node) {
+	if n.PreviousSibling() == nil || inListItem(n) || n.Parent().Kind() == ast.KindList {
+		return
+	}
+	_ = w.WriteByte('\n')
+}
+
+func (r *Renderer) renderDocument(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	marker := strings.Repeat("=", n.Level)
+	if entering {
+		r.writeBlockStart(w, node)
+		_, _ = w.WriteString(marker + " ")
+	} else {
+		_, _ = w.WriteString(" " + marker + "\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node)
+		_, _ = w.WriteString("<blockquote>\n")
+	} else {
+		_, _ = w.WriteString("</blockquote>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockStart(w, node)
+	var language []byte
+	if n, ok := node.(*ast.FencedCodeBlock); ok {
+		language = n.Language(source)
+	}
+	end := "</pre>\n"
+	if len(language) != 0 {
+		_, _ = w.WriteString(`<syntaxhighlight lang="`)
+		_, _ = w.Write(util.EscapeHTML(language))
+		_, _ = w.WriteString("\">\n")
+		end = "</syntaxhighlight>\n"
+	} else {
+		_, _ = w.WriteString("<pre>\n")
+	}
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		if len(language) != 0 {
+			_, _ = w.Write(line.Value(source))
+		} else {
+			_, _ = w.Write(util.EscapeHTML(line.Value(source)))
+		}
+	}
+	_, _ = w.WriteString(end)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderHTMLBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockStart(w, node)
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		_, _ = w.Write(line.Value(source))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var marker []byte
+	for p := node.Parent(); p != nil; p = p.Parent() {
+		if list, ok := p.(*ast.List); ok {
+			if list.IsOrdered() {
+				marker = append(marker, '#')
+			} else {
+				marker = append(marker, '*')
+			}
+		}
+	}
+	for i, j := 0, len(marker)-1; i < j; i, j = i+1, j-1 {
+		marker[i], marker[j] = marker[j], marker[i]
+	}
+	_, _ = w.Write(marker)
+	_ = w.WriteByte(' ')
+	if !node.HasChildren() {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node)
+		return ast.WalkContinue, nil
+	}
+	// blocks in a list item are written in a line
+	if next := node.NextSibling(); next != nil && node.Parent().Kind() == ast.KindListItem &&
+		next.Kind() != ast.KindList {
+		_, _ = w.WriteString("<br /><br />")
+		return ast.WalkContinue, nil
+	}
+	_ = w.WriteByte('\n')
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node)
+		_, _ = w.WriteString("----\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node)
+		_, _ = w.WriteString("{| class=\"wikitable\"\n")
+	} else {
+		_, _ = w.WriteString("|}\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTableRow(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		if node.PreviousSibling() != nil {
+			_, _ = w.WriteString("|-\n")
+		}
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTableCell(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.TableCell)
+	marker := "|"
+	if node.Parent().Kind() == east.KindTableHeader {
+		marker = "!"
+	}
+	if node.PreviousSibling() == nil {
+		_, _ = w.WriteString(marker + " ")
+	} else {
+		_, _ = w.WriteString(" " + marker + marker + " ")
+	}
+	if n.Alignment != east.AlignNone {
+		_, _ = w.WriteString(`style="text-align:` + n.Alignment.String() + `" | `)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.AutoLink)
+	if n.AutoLinkType == ast.AutoLinkEmail {
+		_, _ = w.WriteString("[mailto:")
+		_, _ = w.Write(n.URL(source))
+		_ = w.WriteByte(' ')
+		_, _ = w.Write(Escape(n.Label(source)))
+		_ = w.WriteByte(']')
+	} else {
+		_, _ = w.Write(n.URL(source))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderCodeSpan(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("<code>")
+	for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+		var value []byte
+		switch t := c.(type) {
+		case *ast.Text:
+			value = t.Segment.Value(source)
+		case *ast.String:
+			value = t.Value
+		}
+		_, _ = w.Write(Escape(bytes.ReplaceAll(value, []byte("\n"), []byte(" "))))
+	}
+	_, _ = w.WriteString("</code>")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Emphasis)
+	if n.Level == 2 {
+		_, _ = w.WriteString("'''")
+	} else {
+		_, _ = w.WriteString("''")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<s>")
+	} else {
+		_, _ = w.WriteString("</s>")
+	}
+	return ast.WalkContinue, nil
+}
+
+// plainText returns texts under the given node without markups.
+func plainText(n ast.Node, source []byte) []byte {
+	var b []byte
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			b = append(b, unescape(t.Segment.Value(source))...)
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b = append(b, ' ')
+			}
+		case *ast.String:
+			b = append(b, t.Value...)
+		default:
+			b = append(b, plainText(c, source)...)
+		}
+	}
+	return b
+}
+
+// isExternal returns true if the given destination has a URL scheme.
+func isExternal(destination []byte) bool {
+	i := bytes.Index(destination, []byte("://"))
+	return i > 0 || bytes.HasPrefix(bytes.ToLower(destination), []byte("mailto:"))
+}
+
+func (r *Renderer) writeLink(w util.BufWriter, destination, label []byte) {
+	if isExternal(destination) {
+		_ = w.WriteByte('[')
+		_, _ = w.Write(bytes.ReplaceAll(destination, []byte(" "), []byte("%20")))
+		if len(label) != 0 {
+			_ = w.WriteByte(' ')
+			_, _ = w.Write(Escape(label))
+		}
+		_ = w.WriteByte(']')
+		return
+	}
+	_, _ = w.WriteString("[[")
+	_, _ = w.Write(destination)
+	if len(label) != 0 && !bytes.Equal(label, destination) {
+		_ = w.WriteByte('|')
+		_, _ = w.Write(Escape(label))
+	}
+	_, _ = w.WriteString("]]")
+}
+
+func (r *Renderer) renderImage(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Image)
+		r.writeLink(w, n.Destination, plainText(n, source))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Link)
+		r.writeLink(w, n.Destination, plainText(n, source))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderRawHTML(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.RawHTML)
+		for i := 0; i < n.Segments.Len(); i++ {
+			segment := n.Segments.At(i)
+			_, _ = w.Write(segment.Value(source))
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderText(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	value := Escape(unescape(n.Segment.Value(source)))
+	if n.PreviousSibling() == nil && n.Parent().Type() == ast.TypeBlock {
+		value = escapeLineStart(value)
+	}
+	_, _ = w.Write(value)
+	if n.NextSibling() != nil {
+		// a newline may start a list or a heading, so soft line breaks
+		// are written as spaces
+		if n.HardLineBreak() {
+			_, _ = w.WriteString("<br />")
+		} else if n.SoftLineBreak() {
+			_ = w.WriteByte(' ')
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.String)
+		if n.IsCode() {
+			_, _ = w.Write(n.Value)
+		} else {
+			_, _ = w.Write(Escape(n.Value))
+		}
+	}
+	return ast.WalkContinue, nil
+}
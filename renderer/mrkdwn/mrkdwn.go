@@ -0,0 +1,384 @@
+// Package mrkdwn implements renderer that outputs Slack's mrkdwn format.
+//
+// mrkdwn has no headings, lists, images and raw HTML. Headings are
+// rendered as bold lines, list items are prefixed by '•' or numbers,
+// images are rendered as links and raw HTML is rendered as text.
+package mrkdwn
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that
+// renders nodes as Slack's mrkdwn.
+type Renderer struct {
+}
+
+// NewRenderer returns a new Renderer.
+//
+// Extensions register their HTML renderers with priority 500, so
+// the Renderer should be registered with a higher priority (a smaller
+// value) to render nodes of extensions like Strikethrough:
+//
+//	markdown := goldmark.New(
+//	    goldmark.WithRenderer(renderer.NewRenderer(
+//	        renderer.WithNodeRenderers(util.Prioritized(mrkdwn.NewRenderer(), 100)))),
+//	)
+func NewRenderer() renderer.NodeRenderer {
+	return &Renderer{}
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	// blocks
+
+	reg.Register(ast.KindDocument, r.renderDocument)
+	reg.Register(ast.KindHeading, r.renderHeading)
+	reg.Register(ast.KindBlockquote, r.renderContainer)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindHTMLBlock, r.renderHTMLBlock)
+	reg.Register(ast.KindList, r.renderContainer)
+	reg.Register(ast.KindListItem, r.renderListItem)
+	reg.Register(ast.KindParagraph, r.renderParagraph)
+	reg.Register(ast.KindTextBlock, r.renderParagraph)
+	reg.Register(ast.KindThematicBreak, r.renderThematicBreak)
+
+	// inlines
+
+	reg.Register(ast.KindAutoLink, r.renderAutoLink)
+	reg.Register(ast.KindCodeSpan, r.renderCodeSpan)
+	reg.Register(ast.KindEmphasis, r.renderEmphasis)
+	reg.Register(ast.KindImage, r.renderImage)
+	reg.Register(ast.KindLink, r.renderLink)
+	reg.Register(ast.KindRawHTML, r.renderRawHTML)
+	reg.Register(ast.KindText, r.renderText)
+	reg.Register(ast.KindString, r.renderString)
+	reg.Register(east.KindStrikethrough, r.renderStrikethrough)
+}
+
+// Escape escapes '&', '<' and '>' those have special meanings in mrkdwn.
+func Escape(v []byte) []byte {
+	if bytes.IndexAny(v, "&<>") < 0 {
+		return v
+	}
+	var b bytes.Buffer
+	for _, c := range v {
+		switch c {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.Bytes()
+}
+
+func isTightList(n ast.Node) bool {
+	list, ok := n.(*ast.List)
+	return ok && list.IsTight
+}
+
+func listItemMarker(n ast.Node) string {
+	list := n.Parent().(*ast.List)
+	if !list.IsOrdered() {
+		return "• "
+	}
+	i := list.Start
+	for c := list.FirstChild(); c != nil && c != n; c = c.NextSibling() {
+		i++
+	}
+	return strconv.Itoa(i) + ". "
+}
+
+// linePrefix returns a prefix of lines in the given block.
+// first is true if the prefix is for the first line of the block.
+// List items write prefixes of their first lines with markers, so
+// the first line of the first block in a list item has no prefix above
+// the list item.
+func linePrefix(n ast.Node, first bool) string {
+	var parts []string
+	child := n
+loop:
+	for p := n.Parent(); p != nil && p.Kind() != ast.KindDocument; child, p = p, p.Parent() {
+		first = first && child == p.FirstChild()
+		switch p.Kind() {
+		case ast.KindBlockquote:
+			parts = append(parts, "> ")
+		case ast.KindListItem:
+			if first {
+				// the list item has written the line until the marker
+				break loop
+			}
+			parts = append(parts, strings.Repeat(" ", len([]rune(listItemMarker(p)))))
+		}
+	}
+	var b strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		b.WriteString(parts[i])
+	}
+	return b.String()
+}
+
+// blockOf returns the nearest block that contains the given node.
+func blockOf(n ast.Node) ast.Node {
+	for n.Type() == ast.TypeInline && n.Parent() != nil {
+		n = n.Parent()
+	}
+	return n
+}
+
+// writeBlockStart writes a blank line between blocks and a prefix of
+// the first line of the block.
+func (r *Renderer) writeBlockStart(w util.BufWriter, n ast.Node, prefix bool) {
+	if n.PreviousSibling() != nil && !isTightList(n.Parent()) &&
+		!(n.Parent().Kind() == ast.KindListItem && isTightList(n.Parent().Parent())) {
+		_, _ = w.WriteString(strings.TrimRight(linePrefix(n, false), " "))
+		_ = w.WriteByte('\n')
+	}
+	if prefix {
+		_, _ = w.WriteString(linePrefix(n, true))
+	}
+}
+
+func (r *Renderer) renderDocument(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderContainer(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, false)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, true)
+		_ = w.WriteByte('*')
+	} else {
+		_, _ = w.WriteString("*\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, true)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, true)
+		_, _ = w.WriteString(listItemMarker(node))
+		if !node.HasChildren() {
+			_ = w.WriteByte('\n')
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockStart(w, node, true)
+	prefix := linePrefix(node, false)
+	_, _ = w.WriteString("```\n")
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		_, _ = w.WriteString(prefix)
+		_, _ = w.Write(Escape(line.Value(source)))
+	}
+	_, _ = w.WriteString(prefix)
+	_, _ = w.WriteString("```\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderHTMLBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockStart(w, node, true)
+	prefix := linePrefix(node, false)
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		if i != 0 {
+			_, _ = w.WriteString(prefix)
+		}
+		_, _ = w.Write(Escape(util.TrimRightSpace(line.Value(source))))
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, true)
+		_, _ = w.WriteString("---\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.AutoLink)
+	_ = w.WriteByte('<')
+	if n.AutoLinkType == ast.AutoLinkEmail {
+		_, _ = w.WriteString("mailto:")
+		_, _ = w.Write(Escape(n.URL(source)))
+		_ = w.WriteByte('|')
+	}
+	_, _ = w.Write(Escape(n.Label(source)))
+	_ = w.WriteByte('>')
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderCodeSpan(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_ = w.WriteByte('`')
+	for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+		var value []byte
+		switch t := c.(type) {
+		case *ast.Text:
+			value = t.Segment.Value(source)
+		case *ast.String:
+			value = t.Value
+		}
+		_, _ = w.Write(Escape(bytes.ReplaceAll(value, []byte("\n"), []byte(" "))))
+	}
+	_ = w.WriteByte('`')
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Emphasis)
+	if n.Level == 2 {
+		_ = w.WriteByte('*')
+	} else {
+		_ = w.WriteByte('_')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	_ = w.WriteByte('~')
+	return ast.WalkContinue, nil
+}
+
+// unescape resolves backslash escapes and character references in texts.
+func unescape(v []byte) []byte {
+	return util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(v)))
+}
+
+// plainText returns texts under the given node without markups.
+func plainText(n ast.Node, source []byte) []byte {
+	var b []byte
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			b = append(b, unescape(t.Segment.Value(source))...)
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b = append(b, ' ')
+			}
+		case *ast.String:
+			b = append(b, t.Value...)
+		default:
+			b = append(b, plainText(c, source)...)
+		}
+	}
+	return b
+}
+
+func (r *Renderer) writeLink(w util.BufWriter, destination, label []byte) {
+	_ = w.WriteByte('<')
+	_, _ = w.Write(Escape(destination))
+	if len(label) != 0 && !bytes.Equal(label, destination) {
+		_ = w.WriteByte('|')
+		_, _ = w.Write(Escape(label))
+	}
+	_ = w.WriteByte('>')
+}
+
+func (r *Renderer) renderImage(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Image)
+		r.writeLink(w, n.Destination, plainText(n, source))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Link)
+		r.writeLink(w, n.Destination, plainText(n, source))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderRawHTML(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.RawHTML)
+		for i := 0; i < n.Segments.Len(); i++ {
+			segment := n.Segments.At(i)
+			_, _ = w.Write(Escape(segment.Value(source)))
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderText(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	_, _ = w.Write(Escape(unescape(n.Segment.Value(source))))
+	if (n.SoftLineBreak() || n.HardLineBreak()) && n.NextSibling() != nil {
+		_ = w.WriteByte('\n')
+		_, _ = w.WriteString(linePrefix(blockOf(n), false))
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.Write(Escape(node.(*ast.String).Value))
+	}
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,388 @@
+// Package orgmode implements renderer that outputs Emacs Org mode markup.
+//
+// Org mode has no way to escape markup characters in texts, so texts are
+// written as they are.
+package orgmode
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that
+// renders nodes as Org mode markup.
+type Renderer struct {
+}
+
+// NewRenderer returns a new Renderer.
+// The Renderer should be registered with a priority smaller than 500
+// to render nodes of extensions like Strikethrough.
+func NewRenderer() renderer.NodeRenderer {
+	return &Renderer{}
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	// blocks
+
+	reg.Register(ast.KindDocument, r.renderDocument)
+	reg.Register(ast.KindHeading, r.renderHeading)
+	reg.Register(ast.KindBlockquote, r.renderBlockquote)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindHTMLBlock, r.renderHTMLBlock)
+	reg.Register(ast.KindList, r.renderList)
+	reg.Register(ast.KindListItem, r.renderListItem)
+	reg.Register(ast.KindParagraph, r.renderParagraph)
+	reg.Register(ast.KindTextBlock, r.renderParagraph)
+	reg.Register(ast.KindThematicBreak, r.renderThematicBreak)
+
+	// inlines
+
+	reg.Register(ast.KindAutoLink, r.renderAutoLink)
+	reg.Register(ast.KindCodeSpan, r.renderCodeSpan)
+	reg.Register(ast.KindEmphasis, r.renderEmphasis)
+	reg.Register(ast.KindImage, r.renderImage)
+	reg.Register(ast.KindLink, r.renderLink)
+	reg.Register(ast.KindRawHTML, r.renderRawHTML)
+	reg.Register(ast.KindText, r.renderText)
+	reg.Register(ast.KindString, r.renderString)
+	reg.Register(east.KindStrikethrough, r.renderStrikethrough)
+}
+
+func isTightList(n ast.Node) bool {
+	list, ok := n.(*ast.List)
+	return ok && list.IsTight
+}
+
+func listItemMarker(n ast.Node) string {
+	list := n.Parent().(*ast.List)
+	if !list.IsOrdered() {
+		return "- "
+	}
+	i := list.Start
+	for c := list.FirstChild(); c != nil && c != n; c = c.NextSibling() {
+		i++
+	}
+	return strconv.Itoa(i) + ". "
+}
+
+// linePrefix returns an indentation of lines in the given block.
+// first is true if the indentation is for the first line of the block.
+// List items write their markers in place of the indentation of
+// their first lines.
+func linePrefix(n ast.Node, first bool) string {
+	var parts []string
+	child := n
+loop:
+	for p := n.Parent(); p != nil && p.Kind() != ast.KindDocument; child, p = p, p.Parent() {
+		first = first && child == p.FirstChild()
+		switch p.Kind() {
+		case ast.KindBlockquote:
+			// the blockquote has written a '#+BEGIN_QUOTE' line
+			first = false
+		case ast.KindListItem:
+			if first {
+				break loop
+			}
+			parts = append(parts, strings.Repeat(" ", len(listItemMarker(p))))
+		}
+	}
+	var b strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		b.WriteString(parts[i])
+	}
+	return b.String()
+}
+
+// blockOf returns the nearest block that contains the given node.
+func blockOf(n ast.Node) ast.Node {
+	for n.Type() == ast.TypeInline && n.Parent() != nil {
+		n = n.Parent()
+	}
+	return n
+}
+
+// writeBlockStart writes a blank line between blocks and an indentation of
+// the first line of the block.
+func (r *Renderer) writeBlockStart(w util.BufWriter, n ast.Node, prefix bool) {
+	if n.PreviousSibling() != nil && !isTightList(n.Parent()) &&
+		!(n.Parent().Kind() == ast.KindListItem && isTightList(n.Parent().Parent())) {
+		_ = w.WriteByte('\n')
+	}
+	if prefix {
+		_, _ = w.WriteString(linePrefix(n, true))
+	}
+}
+
+// writeLines writes lines of the given block with indentations.
+func (r *Renderer) writeLines(w util.BufWriter, source []byte, n ast.Node) {
+	prefix := linePrefix(n, false)
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		_, _ = w.WriteString(prefix)
+		_, _ = w.Write(line.Value(source))
+	}
+}
+
+func (r *Renderer) renderDocument(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	if entering {
+		r.writeBlockStart(w, node, true)
+		_, _ = w.WriteString(strings.Repeat("*", n.Level))
+		_ = w.WriteByte(' ')
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, true)
+		_, _ = w.WriteString("#+BEGIN_QUOTE\n")
+	} else {
+		_, _ = w.WriteString(linePrefix(node, false))
+		_, _ = w.WriteString("#+END_QUOTE\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockStart(w, node, true)
+	var language []byte
+	if n, ok := node.(*ast.FencedCodeBlock); ok {
+		language = n.Language(source)
+	}
+	end := "#+END_EXAMPLE\n"
+	if len(language) != 0 {
+		_, _ = w.WriteString("#+BEGIN_SRC ")
+		_, _ = w.Write(language)
+		_ = w.WriteByte('\n')
+		end = "#+END_SRC\n"
+	} else {
+		_, _ = w.WriteString("#+BEGIN_EXAMPLE\n")
+	}
+	r.writeLines(w, source, node)
+	_, _ = w.WriteString(linePrefix(node, false))
+	_, _ = w.WriteString(end)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderHTMLBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockStart(w, node, true)
+	_, _ = w.WriteString("#+BEGIN_EXPORT html\n")
+	r.writeLines(w, source, node)
+	_, _ = w.WriteString(linePrefix(node, false))
+	_, _ = w.WriteString("#+END_EXPORT\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, false)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, true)
+		_, _ = w.WriteString(listItemMarker(node))
+		if !node.HasChildren() {
+			_ = w.WriteByte('\n')
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, true)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockStart(w, node, true)
+		_, _ = w.WriteString("-----\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) writeLink(w util.BufWriter, destination, label []byte) {
+	_, _ = w.WriteString("[[")
+	_, _ = w.Write(destination)
+	if len(label) != 0 && !bytes.Equal(label, destination) {
+		_, _ = w.WriteString("][")
+		_, _ = w.Write(label)
+	}
+	_, _ = w.WriteString("]]")
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.AutoLink)
+	url := n.URL(source)
+	if n.AutoLinkType == ast.AutoLinkEmail && !bytes.HasPrefix(bytes.ToLower(url), []byte("mailto:")) {
+		url = append([]byte("mailto:"), url...)
+	}
+	r.writeLink(w, url, n.Label(source))
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderCodeSpan(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var value []byte
+	for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			value = append(value, t.Segment.Value(source)...)
+		case *ast.String:
+			value = append(value, t.Value...)
+		}
+	}
+	value = bytes.ReplaceAll(value, []byte("\n"), []byte(" "))
+	// '~' can not appear in '~code~', so codes that contain '~' are
+	// written as '=verbatim='.
+	marker := byte('~')
+	if bytes.IndexByte(value, '~') > -1 {
+		marker = '='
+	}
+	_ = w.WriteByte(marker)
+	_, _ = w.Write(value)
+	_ = w.WriteByte(marker)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Emphasis)
+	if n.Level == 2 {
+		_ = w.WriteByte('*')
+	} else {
+		_ = w.WriteByte('/')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	_ = w.WriteByte('+')
+	return ast.WalkContinue, nil
+}
+
+// unescape resolves backslash escapes and character references in texts.
+func unescape(v []byte) []byte {
+	return util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(v)))
+}
+
+// plainText returns texts under the given node without markups.
+func plainText(n ast.Node, source []byte) []byte {
+	var b []byte
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			b = append(b, unescape(t.Segment.Value(source))...)
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b = append(b, ' ')
+			}
+		case *ast.String:
+			b = append(b, t.Value...)
+		default:
+			b = append(b, plainText(c, source)...)
+		}
+	}
+	return b
+}
+
+func (r *Renderer) renderImage(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		// links without descriptions are displayed as inline images
+		r.writeLink(w, node.(*ast.Image).Destination, nil)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Link)
+		r.writeLink(w, n.Destination, plainText(n, source))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderRawHTML(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.RawHTML)
+		_, _ = w.WriteString("@@html:")
+		for i := 0; i < n.Segments.Len(); i++ {
+			segment := n.Segments.At(i)
+			_, _ = w.Write(segment.Value(source))
+		}
+		_, _ = w.WriteString("@@")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderText(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	_, _ = w.Write(unescape(n.Segment.Value(source)))
+	if (n.SoftLineBreak() || n.HardLineBreak()) && n.NextSibling() != nil {
+		if n.HardLineBreak() {
+			_, _ = w.WriteString("\\\\")
+		}
+		_ = w.WriteByte('\n')
+		_, _ = w.WriteString(linePrefix(blockOf(n), false))
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.Write(node.(*ast.String).Value)
+	}
+	return ast.WalkContinue, nil
+}
@@ -3,6 +3,7 @@ package renderer
 
 import (
 	"bufio"
+	"bytes"
 	"io"
 	"sync"
 
@@ -14,13 +15,23 @@ import (
 type Config struct {
 	Options       map[OptionName]interface{}
 	NodeRenderers util.PrioritizedSlice
+	Parallelism   int
+	Middlewares   []Middleware
+	RenderHook    RenderHook
+
+	// Format is a name of the output format like 'html'.
+	Format string
 }
 
+// DefaultFormat is a default name of the output format.
+const DefaultFormat = "html"
+
 // NewConfig returns a new Config.
 func NewConfig() *Config {
 	return &Config{
 		Options:       map[OptionName]interface{}{},
 		NodeRenderers: util.PrioritizedSlice{},
+		Format:        DefaultFormat,
 	}
 }
 
@@ -46,6 +57,94 @@ func WithNodeRenderers(ps ...util.PrioritizedValue) Option {
 	return &withNodeRenderers{ps}
 }
 
+type withFormat struct {
+	value string
+}
+
+func (o *withFormat) SetConfig(c *Config) {
+	c.Format = o.value
+}
+
+// WithFormat is a functional option that specifies a name of the output
+// format like 'latex' for renderers those NodeRenderers output formats
+// other than HTML. Extensions can see the format by FormatOf.
+// This defaults to DefaultFormat.
+func WithFormat(format string) Option {
+	return &withFormat{format}
+}
+
+type withParallelism struct {
+	value int
+}
+
+func (o *withParallelism) SetConfig(c *Config) {
+	c.Parallelism = o.value
+}
+
+// WithParallelism is a functional option that allow you to render
+// top-level blocks of a document concurrently with n goroutines.
+// Rendered blocks are written in the document order.
+// All NodeRenderers must be safe for concurrent use if n is greater than 1.
+func WithParallelism(n int) Option {
+	return &withParallelism{n}
+}
+
+// A Middleware is a function that wraps NodeRendererFuncs.
+// A Middleware is called for every node with next that renders the node.
+// next does nothing if no NodeRendererFunc is registered for the node.
+// A Middleware can write contents before and after calling next, or skip
+// next to suppress the node.
+type Middleware func(writer util.BufWriter, source []byte, n ast.Node, entering bool,
+	next NodeRendererFunc) (ast.WalkStatus, error)
+
+type withMiddleware struct {
+	value Middleware
+}
+
+func (o *withMiddleware) SetConfig(c *Config) {
+	c.Middlewares = append(c.Middlewares, o.value)
+}
+
+// WithMiddleware is a functional option that wraps every NodeRendererFunc
+// with the given Middleware. Middlewares given first are called first.
+func WithMiddleware(m Middleware) Option {
+	return &withMiddleware{m}
+}
+
+// A RenderHook is a function that is called before a node is entered and
+// after a node is exited. Children of the node are rendered between
+// the two calls.
+// RenderHooks are called for every node even if no NodeRendererFunc
+// is registered for the node.
+type RenderHook func(kind ast.NodeKind, entering bool)
+
+type withRenderHook struct {
+	value RenderHook
+}
+
+func (o *withRenderHook) SetConfig(c *Config) {
+	c.RenderHook = o.value
+}
+
+// WithRenderHook is a functional option that calls the given RenderHook
+// before and after every node is rendered. This is useful for
+// collecting metrics like rendering time per node kind.
+// The RenderHook must be safe for concurrent use if rendering runs
+// in parallel.
+func WithRenderHook(hook RenderHook) Option {
+	return &withRenderHook{hook}
+}
+
+func renderNothing(writer util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+func wrapNodeRendererFunc(m Middleware, next NodeRendererFunc) NodeRendererFunc {
+	return func(writer util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		return m(writer, source, n, entering, next)
+	}
+}
+
 type withOption struct {
 	name  OptionName
 	value interface{}
@@ -93,12 +192,32 @@ type Renderer interface {
 	AddOptions(...Option)
 }
 
+// A FormatProvider interface is an optional interface of Renderers those
+// know a name of their output format.
+type FormatProvider interface {
+	// Format returns a name of the output format like 'html'.
+	Format() string
+}
+
+// FormatOf returns a name of the output format of the given Renderer.
+// FormatOf returns DefaultFormat if the Renderer does not implement
+// FormatProvider.
+func FormatOf(r Renderer) string {
+	if p, ok := r.(FormatProvider); ok {
+		return p.Format()
+	}
+	return DefaultFormat
+}
+
 type renderer struct {
 	config               *Config
 	options              map[OptionName]interface{}
 	nodeRendererFuncsTmp map[ast.NodeKind]NodeRendererFunc
 	maxKind              int
 	nodeRendererFuncs    []NodeRendererFunc
+	parallelism          int
+	hook                 RenderHook
+	format               string
 	initSync             sync.Once
 }
 
@@ -113,6 +232,7 @@ func NewRenderer(options ...Option) Renderer {
 		options:              map[OptionName]interface{}{},
 		config:               config,
 		nodeRendererFuncsTmp: map[ast.NodeKind]NodeRendererFunc{},
+		format:               config.Format,
 	}
 
 	return r
@@ -122,6 +242,11 @@ func (r *renderer) AddOptions(opts ...Option) {
 	for _, opt := range opts {
 		opt.SetConfig(r.config)
 	}
+	r.format = r.config.Format
+}
+
+func (r *renderer) Format() string {
+	return r.format
 }
 
 func (r *renderer) Register(kind ast.NodeKind, v NodeRendererFunc) {
@@ -151,6 +276,19 @@ func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 		for kind, nr := range r.nodeRendererFuncsTmp {
 			r.nodeRendererFuncs[kind] = nr
 		}
+		if len(r.config.Middlewares) != 0 {
+			for kind, f := range r.nodeRendererFuncs {
+				if f == nil {
+					f = renderNothing
+				}
+				for i := len(r.config.Middlewares) - 1; i >= 0; i-- {
+					f = wrapNodeRendererFunc(r.config.Middlewares[i], f)
+				}
+				r.nodeRendererFuncs[kind] = f
+			}
+		}
+		r.parallelism = r.config.Parallelism
+		r.hook = r.config.RenderHook
 		r.config = nil
 		r.nodeRendererFuncsTmp = nil
 	})
@@ -158,17 +296,112 @@ func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 	if !ok {
 		writer = bufio.NewWriter(w)
 	}
+	var err error
+	if r.parallelism > 1 && n.ChildCount() > 1 {
+		err = r.renderParallel(writer, source, n)
+	} else {
+		_, err = r.walk(writer, source, n)
+	}
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func (r *renderer) renderNode(writer util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if r.hook != nil {
+		return r.renderNodeWithHook(writer, source, n, entering)
+	}
+	return r.renderNodeFunc(writer, source, n, entering)
+}
+
+func (r *renderer) renderNodeWithHook(writer util.BufWriter, source []byte, n ast.Node,
+	entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.hook(n.Kind(), true)
+		return r.renderNodeFunc(writer, source, n, entering)
+	}
+	s, err := r.renderNodeFunc(writer, source, n, entering)
+	r.hook(n.Kind(), false)
+	return s, err
+}
+
+func (r *renderer) renderNodeFunc(writer util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if int(n.Kind()) >= len(r.nodeRendererFuncs) {
+		return ast.WalkContinue, nil
+	}
+	f := r.nodeRendererFuncs[n.Kind()]
+	if f != nil {
+		return f(writer, source, n, entering)
+	}
+	return ast.WalkContinue, nil
+}
+
+// walk renders the given node and returns true if the walk was stopped.
+func (r *renderer) walk(writer util.BufWriter, source []byte, n ast.Node) (bool, error) {
+	stopped := false
 	err := ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
-		s := ast.WalkStatus(ast.WalkContinue)
-		var err error
-		f := r.nodeRendererFuncs[n.Kind()]
-		if f != nil {
-			s, err = f(writer, source, n, entering)
+		s, err := r.renderNode(writer, source, n, entering)
+		if s == ast.WalkStop {
+			stopped = true
 		}
 		return s, err
 	})
-	if err != nil {
+	return stopped, err
+}
+
+type renderResult struct {
+	buf     bytes.Buffer
+	stopped bool
+	err     error
+}
+
+// renderParallel renders children of the given node concurrently and
+// writes results in order. Children are split into contiguous chunks
+// to keep the per-goroutine overhead small.
+func (r *renderer) renderParallel(writer util.BufWriter, source []byte, n ast.Node) error {
+	s, err := r.renderNode(writer, source, n, true)
+	if err != nil || s == ast.WalkStop {
 		return err
 	}
-	return writer.Flush()
+	if s != ast.WalkSkipChildren {
+		children := make([]ast.Node, 0, n.ChildCount())
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			children = append(children, c)
+		}
+		chunks := r.parallelism
+		if chunks > len(children) {
+			chunks = len(children)
+		}
+		results := make([]renderResult, chunks)
+		var wg sync.WaitGroup
+		for i := 0; i < chunks; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				result := &results[i]
+				w := bufio.NewWriter(&result.buf)
+				for _, c := range children[i*len(children)/chunks : (i+1)*len(children)/chunks] {
+					result.stopped, result.err = r.walk(w, source, c)
+					if result.err != nil || result.stopped {
+						break
+					}
+				}
+				if err := w.Flush(); result.err == nil {
+					result.err = err
+				}
+			}(i)
+		}
+		wg.Wait()
+		for i := range results {
+			if _, err := writer.Write(results[i].buf.Bytes()); err != nil {
+				return err
+			}
+			if results[i].err != nil || results[i].stopped {
+				return results[i].err
+			}
+		}
+	}
+	_, err = r.renderNode(writer, source, n, false)
+	return err
 }
@@ -8,13 +8,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime/debug"
 	"strconv"
 	"strings"
 
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 )
 
@@ -85,6 +89,187 @@ func ParseCliCaseArg() []int {
 	return ret
 }
 
+// UpdateGoldenEnv is an environment variable that makes GoldenTest rewrite
+// golden files if it is not empty.
+const UpdateGoldenEnv = "GOLDMARK_UPDATE_GOLDEN"
+
+// GoldenTest compares got with the golden file '<dir>/<name>.html'.
+// dir is relative to the working directory of the test, that is the
+// directory of the package. The golden file is rewritten with got if the
+// UpdateGoldenEnv environment variable is set like
+// 'GOLDMARK_UPDATE_GOLDEN=1 go test ./...'.
+func GoldenTest(t TestingT, dir, name string, got string) {
+	path := filepath.Join(dir, name+".html")
+	if len(os.Getenv(UpdateGoldenEnv)) != 0 {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("failed to create a directory for %s: %v", path, err)
+			return
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Errorf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("failed to read %s: %v (set %s to create it)", path, err, UpdateGoldenEnv)
+		return
+	}
+	if !bytes.Equal(expected, []byte(got)) {
+		t.Errorf("%s does not match (set %s to update it):\n%s",
+			path, UpdateGoldenEnv, DiffPretty(expected, []byte(got)))
+	}
+}
+
+// AssertAST compares the given AST with wantJSON.
+// Each node is serialized as a JSON object that has a "Kind", exported
+// fields of the node, "Attributes", "RawText" of blocks and "Children".
+// Texts and segments are serialized as strings in the source, and Text
+// nodes have their value as a "Value". For example:
+//
+//	{"Kind": "Heading", "Level": 1, "Style": "ATX", "RawText": "Title", "Children": [
+//	  {"Kind": "Text", "Value": "Title"}
+//	]}
+//
+// wantJSON is compared regardless of its indentation and key order.
+func AssertAST(t TestingT, root ast.Node, source []byte, wantJSON string) {
+	var want interface{}
+	if err := json.Unmarshal([]byte(wantJSON), &want); err != nil {
+		t.Errorf("invalid JSON: %v", err)
+		return
+	}
+	expected, _ := json.MarshalIndent(want, "", "  ")
+	actual, err := json.MarshalIndent(astToJSON(root, source), "", "  ")
+	if err != nil {
+		t.Errorf("failed to serialize the AST: %v", err)
+		return
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("unexpected AST:\n%s", DiffPretty(expected, actual))
+	}
+}
+
+func astToJSON(n ast.Node, source []byte) map[string]interface{} {
+	m := map[string]interface{}{
+		"Kind": n.Kind().String(),
+	}
+	if t, ok := n.(*ast.Text); ok {
+		m["Value"] = string(t.Text(source))
+		if t.SoftLineBreak() {
+			m["SoftLineBreak"] = true
+		}
+		if t.HardLineBreak() {
+			m["HardLineBreak"] = true
+		}
+		if t.IsRaw() {
+			m["Raw"] = true
+		}
+	} else {
+		fieldsToJSON(m, reflect.Indirect(reflect.ValueOf(n)), source)
+	}
+	if n.Type() == ast.TypeBlock && n.Lines().Len() != 0 {
+		m["RawText"] = string(segmentsValue(n.Lines(), source))
+	}
+	if attrs := n.Attributes(); len(attrs) != 0 {
+		a := map[string]interface{}{}
+		for _, attr := range attrs {
+			a[string(attr.Name)], _ = fieldToJSON(reflect.ValueOf(attr.Value), source)
+		}
+		m["Attributes"] = a
+	}
+	if n.HasChildren() {
+		children := []interface{}{}
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			children = append(children, astToJSON(c, source))
+		}
+		m["Children"] = children
+	}
+	return m
+}
+
+// fieldsToJSON sets exported fields of the given struct to m, including
+// fields promoted from embedded structs.
+func fieldsToJSON(m map[string]interface{}, v reflect.Value, source []byte) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.Anonymous {
+			fieldsToJSON(m, v.Field(i), source)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if value, ok := fieldToJSON(v.Field(i), source); ok {
+			m[f.Name] = value
+		}
+	}
+}
+
+func segmentsValue(segments *text.Segments, source []byte) []byte {
+	var b []byte
+	for i := 0; i < segments.Len(); i++ {
+		segment := segments.At(i)
+		b = append(b, segment.Value(source)...)
+	}
+	return b
+}
+
+// fieldToJSON converts the given field value to a JSON value.
+// It returns false if the value can not be serialized.
+func fieldToJSON(v reflect.Value, source []byte) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, false
+	}
+	switch x := v.Interface().(type) {
+	case text.Segment:
+		return string(x.Value(source)), true
+	case *text.Segments:
+		if x == nil {
+			return nil, false
+		}
+		return string(segmentsValue(x, source)), true
+	case []byte:
+		return string(x), true
+	case *ast.Text:
+		if x == nil {
+			return nil, false
+		}
+		return string(x.Text(source)), true
+	case ast.Node:
+		return nil, false
+	case fmt.Stringer:
+		return x.String(), true
+	}
+	switch v.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return v.Interface(), true
+	case reflect.Uint8:
+		return string([]byte{byte(v.Uint())}), true
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		return fieldToJSON(v.Elem(), source)
+	case reflect.Slice, reflect.Array:
+		values := []interface{}{}
+		for i := 0; i < v.Len(); i++ {
+			value, ok := fieldToJSON(v.Index(i), source)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, value)
+		}
+		return values, true
+	}
+	return nil, false
+}
+
 // DoTestCaseFile runs test cases in a given file.
 func DoTestCaseFile(m goldmark.Markdown, filename string, t TestingT, no ...int) {
 	fp, err := os.Open(filename)
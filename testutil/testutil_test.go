@@ -1,7 +1,46 @@
 package testutil
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
 
 // This will fail to compile if the TestingT interface is changed in a way
 // that doesn't conform to testing.T.
 var _ TestingT = (*testing.T)(nil)
+
+type recordingT struct {
+	*testing.T
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, format)
+}
+
+func TestAssertAST(t *testing.T) {
+	source := []byte("# Title {#id}\n\n- [a](/url \"t\")\n  b\n")
+	doc := goldmark.New(goldmark.WithParserOptions(parser.WithAttribute())).
+		Parser().Parse(text.NewReader(source))
+	expected := `{"Kind": "Document", "Children": [
+	  {"Kind": "Heading", "Level": 1, "Style": "ATX", "RawText": "Title ",
+	   "Attributes": {"id": "id"},
+	   "Children": [{"Kind": "Text", "Value": "Title"}]},
+	  {"Kind": "List", "Marker": "-", "IsTight": true, "Start": 0, "Children": [
+	    {"Kind": "ListItem", "Offset": 2, "Children": [
+	      {"Kind": "TextBlock", "RawText": "[a](/url \"t\")\nb", "Children": [
+	        {"Kind": "Link", "Destination": "/url", "Title": "t", "Children": [
+	          {"Kind": "Text", "Value": "a"}]},
+	        {"Kind": "Text", "Value": "", "SoftLineBreak": true},
+	        {"Kind": "Text", "Value": "b"}]}]}]}]}`
+	AssertAST(t, doc, source, expected)
+
+	rt := &recordingT{T: t}
+	AssertAST(rt, doc, source, `{"Kind": "Document"}`)
+	if len(rt.errors) != 1 {
+		t.Errorf("a mismatch should be reported: %v", rt.errors)
+	}
+}
@@ -4,10 +4,9 @@ package util
 import (
 	"bytes"
 	"io"
-	"net/url"
-	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -27,11 +26,22 @@ func NewCopyOnWriteBuffer(buffer []byte) CopyOnWriteBuffer {
 	}
 }
 
+// capacity returns a capacity of the copied buffer that will be
+// extended by the given length. The capacity at least doubles so that
+// large sequential writes are amortized.
+func (b *CopyOnWriteBuffer) capacity(extra int) int {
+	c := len(b.buffer) + extra + 20
+	if d := len(b.buffer) * 2; d > c {
+		return d
+	}
+	return c
+}
+
 // Write writes given bytes to the buffer.
 // Write allocate new buffer and clears it at the first time.
 func (b *CopyOnWriteBuffer) Write(value []byte) {
 	if !b.copied {
-		b.buffer = make([]byte, 0, len(b.buffer)+20)
+		b.buffer = make([]byte, 0, b.capacity(len(value)))
 		b.copied = true
 	}
 	b.buffer = append(b.buffer, value...)
@@ -47,7 +57,7 @@ func (b *CopyOnWriteBuffer) WriteString(value string) {
 // Append copy buffer at the first time.
 func (b *CopyOnWriteBuffer) Append(value []byte) {
 	if !b.copied {
-		tmp := make([]byte, len(b.buffer), len(b.buffer)+20)
+		tmp := make([]byte, len(b.buffer), b.capacity(len(value)))
 		copy(tmp, b.buffer)
 		b.buffer = tmp
 		b.copied = true
@@ -65,7 +75,7 @@ func (b *CopyOnWriteBuffer) AppendString(value string) {
 // WriteByte allocate new buffer and clears it at the first time.
 func (b *CopyOnWriteBuffer) WriteByte(c byte) error {
 	if !b.copied {
-		b.buffer = make([]byte, 0, len(b.buffer)+20)
+		b.buffer = make([]byte, 0, b.capacity(1))
 		b.copied = true
 	}
 	b.buffer = append(b.buffer, c)
@@ -76,7 +86,7 @@ func (b *CopyOnWriteBuffer) WriteByte(c byte) error {
 // AppendByte copy buffer at the first time.
 func (b *CopyOnWriteBuffer) AppendByte(c byte) {
 	if !b.copied {
-		tmp := make([]byte, len(b.buffer), len(b.buffer)+20)
+		tmp := make([]byte, len(b.buffer), b.capacity(1))
 		copy(tmp, b.buffer)
 		b.buffer = tmp
 		b.copied = true
@@ -84,6 +94,23 @@ func (b *CopyOnWriteBuffer) AppendByte(c byte) {
 	b.buffer = append(b.buffer, c)
 }
 
+// WriteRune writes the utf-8 encoded rune to the buffer.
+// WriteRune allocate new buffer and clears it at the first time.
+func (b *CopyOnWriteBuffer) WriteRune(r rune) (int, error) {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	b.Write(buf[:n])
+	return n, nil
+}
+
+// AppendRune appends the utf-8 encoded rune to the buffer.
+// AppendRune copy buffer at the first time.
+func (b *CopyOnWriteBuffer) AppendRune(r rune) {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	b.Append(buf[:n])
+}
+
 // Bytes returns bytes of this buffer.
 func (b *CopyOnWriteBuffer) Bytes() []byte {
 	return b.buffer
@@ -115,6 +142,39 @@ func ReadWhile(source []byte, index [2]int, pred func(byte) bool) (int, bool) {
 	return j, ok
 }
 
+// ReadUntil read the given source until pred is true.
+// ReadUntil returns a position of the first byte that satisfies pred and
+// true, or index[1] and false if no bytes satisfy pred.
+func ReadUntil(source []byte, index [2]int, pred func(byte) bool) (int, bool) {
+	j := index[0]
+	for ; j < index[1]; j++ {
+		if pred(source[j]) {
+			return j, true
+		}
+	}
+	return j, false
+}
+
+// CountLeadingBytes returns a length of the leading run of the byte c in
+// the source.
+func CountLeadingBytes(source []byte, c byte) int {
+	i := 0
+	for i < len(source) && source[i] == c {
+		i++
+	}
+	return i
+}
+
+// CountTrailingBytes returns a length of the trailing run of the byte c in
+// the source.
+func CountTrailingBytes(source []byte, c byte) int {
+	i := len(source)
+	for i > 0 && source[i-1] == c {
+		i--
+	}
+	return len(source) - i
+}
+
 // IsBlank returns true if the given string is all space characters.
 func IsBlank(bs []byte) bool {
 	for _, b := range bs {
@@ -414,9 +474,69 @@ func TrimRightSpace(source []byte) []byte {
 	return TrimRight(source, spaces)
 }
 
+// ScanWord skips spaces from start and returns the next word delimited by
+// spaces and a position after the word.
+// ScanWord returns nil and len(source) if there are no more words.
+func ScanWord(source []byte, start int) (word []byte, end int) {
+	i := start
+	for i < len(source) && IsSpace(source[i]) {
+		i++
+	}
+	if i >= len(source) {
+		return nil, len(source)
+	}
+	j := i
+	for j < len(source) && !IsSpace(source[j]) {
+		j++
+	}
+	return source[i:j], j
+}
+
+// ScanToEnd returns bytes from start to the last non-space byte of the
+// source. ScanToEnd returns nil if there are no non-space bytes after start.
+func ScanToEnd(source []byte, start int) []byte {
+	if start >= len(source) {
+		return nil
+	}
+	v := TrimRightSpace(source[start:])
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
 // DoFullUnicodeCaseFolding performs full unicode case folding to given bytes.
 func DoFullUnicodeCaseFolding(v []byte) []byte {
-	var rbuf []byte
+	folded, _ := doFullUnicodeCaseFolding(v)
+	return folded
+}
+
+// DoFullUnicodeCaseFoldingString performs full unicode case folding to
+// given string. DoFullUnicodeCaseFoldingString returns the given string
+// without copying it if it has no characters to be folded.
+func DoFullUnicodeCaseFoldingString(v string) string {
+	i := 0
+	for ; i < len(v); i++ {
+		c := v[i]
+		if (c >= 0x41 && c <= 0x5a) || c >= 0xb5 {
+			break
+		}
+	}
+	if i == len(v) {
+		return v
+	}
+	folded, copied := doFullUnicodeCaseFolding(StringToReadOnlyBytes(v))
+	if !copied {
+		return v
+	}
+	// folded is a new buffer that is not shared with others
+	return BytesToReadOnlyString(folded)
+}
+
+// doFullUnicodeCaseFolding performs full unicode case folding to given
+// bytes and returns true if the result is a new buffer. Given bytes are
+// never modified.
+func doFullUnicodeCaseFolding(v []byte) ([]byte, bool) {
 	cob := NewCopyOnWriteBuffer(v)
 	n := 0
 	for i := 0; i < len(v); i++ {
@@ -444,12 +564,8 @@ func DoFullUnicodeCaseFolding(v []byte) []byte {
 		}
 
 		cob.Write(v[n:i])
-		if rbuf == nil {
-			rbuf = make([]byte, 4)
-		}
 		for _, f := range folded {
-			l := utf8.EncodeRune(rbuf, f)
-			cob.Write(rbuf[:l])
+			_, _ = cob.WriteRune(f)
 		}
 		i += length - 1
 		n = i + 1
@@ -457,7 +573,7 @@ func DoFullUnicodeCaseFolding(v []byte) []byte {
 	if cob.IsCopied() {
 		cob.Write(v[n:])
 	}
-	return cob.Bytes()
+	return cob.Bytes(), cob.IsCopied()
 }
 
 // ReplaceSpaces replaces sequence of spaces with the given repl.
@@ -518,16 +634,79 @@ func ToValidRune(v rune) rune {
 func ToLinkReference(v []byte) string {
 	v = TrimLeftSpace(v)
 	v = TrimRightSpace(v)
-	v = DoFullUnicodeCaseFolding(v)
-	return string(ReplaceSpaces(v, ' '))
+	if isASCII(v) {
+		return toASCIILinkReference(v)
+	}
+	// case folding never produces spaces, so spaces can be replaced first
+	folded, copied := doFullUnicodeCaseFolding(ReplaceSpaces(v, ' '))
+	if copied {
+		return BytesToReadOnlyString(folded)
+	}
+	return string(folded)
+}
+
+func isASCII(v []byte) bool {
+	for _, c := range v {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// toASCIILinkReference is a fast path of ToLinkReference for ASCII labels.
+// It folds cases and replaces spaces in a single pass.
+func toASCIILinkReference(v []byte) string {
+	var b strings.Builder
+	b.Grow(len(v))
+	space := false
+	for _, c := range v {
+		if IsSpace(c) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		if c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// htmlEscapeEntry is an HTML escaped form of a byte.
+// The byte should not be escaped if n is 0.
+type htmlEscapeEntry struct {
+	data [8]byte
+	n    uint8
+}
+
+func newHTMLEscapeEntry(s string) htmlEscapeEntry {
+	e := htmlEscapeEntry{n: uint8(len(s))}
+	copy(e.data[:], s)
+	return e
 }
 
-var htmlEscapeTable = [256][]byte{nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, []byte("&quot;"), nil, nil, nil, []byte("&amp;"), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, []byte("&lt;"), nil, []byte("&gt;"), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil} //nolint:golint,lll
+var htmlEscapeTable = [256]htmlEscapeEntry{
+	// CommonMark requires NUL characters to be replaced with U+FFFD
+	0:   newHTMLEscapeEntry("\uFFFD"),
+	'"': newHTMLEscapeEntry("&quot;"),
+	'&': newHTMLEscapeEntry("&amp;"),
+	'<': newHTMLEscapeEntry("&lt;"),
+	'>': newHTMLEscapeEntry("&gt;"),
+}
 
 // EscapeHTMLByte returns HTML escaped bytes if the given byte should be escaped,
 // otherwise nil.
 func EscapeHTMLByte(b byte) []byte {
-	return htmlEscapeTable[b]
+	e := &htmlEscapeTable[b]
+	if e.n == 0 {
+		return nil
+	}
+	return e.data[:e.n:e.n]
 }
 
 // EscapeHTML escapes characters that should be escaped in HTML text.
@@ -536,10 +715,9 @@ func EscapeHTML(v []byte) []byte {
 	n := 0
 	for i := 0; i < len(v); i++ {
 		c := v[i]
-		escaped := htmlEscapeTable[c]
-		if escaped != nil {
+		if e := &htmlEscapeTable[c]; e.n != 0 {
 			cob.Write(v[n:i])
-			cob.Write(escaped)
+			cob.Write(e.data[:e.n])
 			n = i + 1
 		}
 	}
@@ -571,10 +749,38 @@ func UnescapePunctuations(source []byte) []byte {
 	return cob.Bytes()
 }
 
+// NormalizeLineEndings replaces '\r\n' and '\r' with '\n' and NUL characters
+// with U+FFFD as the CommonMark spec requires.
+// NormalizeLineEndings returns the given source as it is if it contains
+// neither '\r' nor NUL characters.
+func NormalizeLineEndings(source []byte) []byte {
+	cob := NewCopyOnWriteBuffer(source)
+	limit := len(source)
+	n := 0
+	for i := 0; i < limit; i++ {
+		switch source[i] {
+		case '\r':
+			cob.Write(source[n:i])
+			_ = cob.WriteByte('\n')
+			if i < limit-1 && source[i+1] == '\n' {
+				i++
+			}
+			n = i + 1
+		case '\x00':
+			cob.Write(source[n:i])
+			cob.WriteString("\uFFFD")
+			n = i + 1
+		}
+	}
+	if cob.IsCopied() {
+		cob.Write(source[n:])
+	}
+	return cob.Bytes()
+}
+
 // ResolveNumericReferences resolve numeric references like '&#1234;" .
 func ResolveNumericReferences(source []byte) []byte {
 	cob := NewCopyOnWriteBuffer(source)
-	buf := make([]byte, 6)
 	limit := len(source)
 	var ok bool
 	n := 0
@@ -594,8 +800,7 @@ func ResolveNumericReferences(source []byte) []byte {
 							v, _ := strconv.ParseUint(BytesToReadOnlyString(source[start:i]), 16, 32)
 							cob.Write(source[n:pos])
 							n = i + 1
-							runeSize := utf8.EncodeRune(buf, ToValidRune(rune(v)))
-							cob.Write(buf[:runeSize])
+							_, _ = cob.WriteRune(ToValidRune(rune(v)))
 							continue
 						}
 						// code point like #1234;
@@ -606,8 +811,7 @@ func ResolveNumericReferences(source []byte) []byte {
 							v, _ := strconv.ParseUint(BytesToReadOnlyString(source[start:i]), 0, 32)
 							cob.Write(source[n:pos])
 							n = i + 1
-							runeSize := utf8.EncodeRune(buf, ToValidRune(rune(v)))
-							cob.Write(buf[:runeSize])
+							_, _ = cob.WriteRune(ToValidRune(rune(v)))
 							continue
 						}
 					}
@@ -655,8 +859,64 @@ func ResolveEntityNames(source []byte) []byte {
 	return cob.Bytes()
 }
 
+// CountEntityReferences returns a number of numeric references and
+// entity references those are resolved by ResolveNumericReferences and
+// ResolveEntityNames.
+func CountEntityReferences(source []byte) int {
+	count := 0
+	limit := len(source)
+	for i := 0; i < limit; i++ {
+		if source[i] != '&' {
+			continue
+		}
+		next := i + 1
+		if next < limit && source[next] == '#' {
+			start := next + 1
+			hex := start < limit && (source[start] == 'x' || source[start] == 'X')
+			var j int
+			var ok bool
+			if hex {
+				start++
+				j, ok = ReadWhile(source, [2]int{start, limit}, IsHexDecimal)
+			} else {
+				j, ok = ReadWhile(source, [2]int{start, limit}, IsNumeric)
+				ok = ok && j-start < 8
+			}
+			if ok && j < limit && source[j] == ';' {
+				count++
+				i = j
+			}
+			continue
+		}
+		j, ok := ReadWhile(source, [2]int{next, limit}, IsAlphaNumeric)
+		if ok && j < limit && source[j] == ';' {
+			if _, ok := LookUpHTML5EntityByName(BytesToReadOnlyString(source[next:j])); ok {
+				count++
+				i = j
+			}
+		}
+	}
+	return count
+}
+
 var htmlSpace = []byte("%20")
 
+// urlPercentEncodeTable holds percent encoded forms like '%E3' of each byte.
+var urlPercentEncodeTable = func() [256][3]byte {
+	const hex = "0123456789ABCDEF"
+	var t [256][3]byte
+	for i := range t {
+		t[i] = [3]byte{'%', hex[i>>4], hex[i&15]}
+	}
+	return t
+}()
+
+// isURLUnreserved returns true if the given byte is an unreserved
+// character of RFC 3986.
+func isURLUnreserved(c byte) bool {
+	return IsAlphaNumeric(c) || c == '-' || c == '_' || c == '.' || c == '~'
+}
+
 // URLEscape escape the given URL.
 // If resolveReference is set true:
 //  1. unescape punctuations
@@ -711,7 +971,13 @@ func URLEscape(v []byte, resolveReference bool) []byte {
 			n = i
 			continue
 		}
-		cob.Write(StringToReadOnlyBytes(url.QueryEscape(string(v[i:stop]))))
+		for _, b := range v[i:stop] {
+			if isURLUnreserved(b) {
+				_ = cob.WriteByte(b)
+			} else {
+				cob.Write(urlPercentEncodeTable[b][:])
+			}
+		}
 		i += int(u8len)
 		n = i
 	}
@@ -721,6 +987,24 @@ func URLEscape(v []byte, resolveReference bool) []byte {
 	return cob.Bytes()
 }
 
+// URLEscapePath escapes the given URL like URLEscape, but keeps a fragment
+// identifier after the first '#' as it is, so that fragments like
+// '#section-2' are never encoded twice.
+func URLEscapePath(v []byte) []byte {
+	i := bytes.IndexByte(v, '#')
+	if i < 0 {
+		return URLEscape(v, false)
+	}
+	path := URLEscape(v[:i], false)
+	if len(path) == i {
+		// the path does not need to be escaped
+		return v
+	}
+	ret := make([]byte, 0, len(path)+len(v)-i)
+	ret = append(ret, path...)
+	return append(ret, v[i:]...)
+}
+
 // FindURLIndex returns a stop index value if the given bytes seem an URL.
 // This function is equivalent to [A-Za-z][A-Za-z0-9.+-]{1,31}:[^<>\x00-\x20]* .
 func FindURLIndex(b []byte) int {
@@ -751,11 +1035,47 @@ func FindURLIndex(b []byte) int {
 	return i
 }
 
-var emailDomainRegexp = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*`) //nolint:golint,lll
+// emailDomainLabelStop returns a stop index of the domain label
+// that starts at the given position. A domain label consists of up to
+// 63 alphanumerics and hyphens. It must start and end with an alphanumeric.
+// emailDomainLabelStop returns -1 if there is no domain label.
+func emailDomainLabelStop(b []byte, start int) int {
+	if start >= len(b) || !IsAlphaNumeric(b[start]) {
+		return -1
+	}
+	last := start
+	limit := start + 62
+	for i := start + 1; i < len(b) && i <= limit; i++ {
+		c := b[i]
+		if IsAlphaNumeric(c) {
+			last = i
+		} else if c != '-' {
+			break
+		}
+	}
+	return last + 1
+}
+
+// findEmailDomainStop returns a stop index of the domain name
+// that starts at the beginning of the given bytes.
+// findEmailDomainStop returns -1 if there is no domain name.
+func findEmailDomainStop(b []byte) int {
+	stop := emailDomainLabelStop(b, 0)
+	if stop < 0 {
+		return -1
+	}
+	for stop < len(b) && b[stop] == '.' {
+		next := emailDomainLabelStop(b, stop+1)
+		if next < 0 {
+			break
+		}
+		stop = next
+	}
+	return stop
+}
 
 // FindEmailIndex returns a stop index value if the given bytes seem an email address.
 func FindEmailIndex(b []byte) int {
-	// TODO: eliminate regexps
 	i := 0
 	for ; i < len(b); i++ {
 		c := b[i]
@@ -773,11 +1093,11 @@ func FindEmailIndex(b []byte) int {
 	if i >= len(b) {
 		return -1
 	}
-	match := emailDomainRegexp.FindSubmatchIndex(b[i:])
-	if match == nil {
+	stop := findEmailDomainStop(b[i:])
+	if stop < 0 {
 		return -1
 	}
-	return i + match[1]
+	return i + stop
 }
 
 var spaces = []byte(" \t\n\x0b\x0c\x0d")
@@ -801,6 +1121,13 @@ func UTF8Len(b byte) int8 {
 	return utf8lenTable[b]
 }
 
+// RuneLenInBytes returns a byte length of the utf-8 encoded rune.
+// RuneLenInBytes returns -1 if the rune is not a valid unicode code point.
+// RuneLenInBytes is a counterpart of UTF8Len.
+func RuneLenInBytes(r rune) int {
+	return utf8.RuneLen(r)
+}
+
 // IsPunct returns true if the given character is a punctuation, otherwise false.
 func IsPunct(c byte) bool {
 	return punctTable[c] == 1
@@ -836,20 +1163,326 @@ func IsAlphaNumeric(c byte) bool {
 	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
 }
 
-// IsEastAsianWideRune returns trhe if the given rune is an east asian wide character, otherwise false.
-func IsEastAsianWideRune(r rune) bool {
+// eastAsianWideTable is a union of ranges of east asian wide characters.
+var eastAsianWideTable = mergeRangeTables(
+	unicode.Hiragana,
+	unicode.Katakana,
+	unicode.Han,
+	unicode.Lm,
+	unicode.Hangul,
 	// https://en.wikipedia.org/wiki/CJK_Symbols_and_Punctuation
-	var CJKSymbolsAndPunctuation = &unicode.RangeTable{
+	&unicode.RangeTable{
 		R16: []unicode.Range16{
 			{0x3000, 0x303F, 1},
 		},
+	},
+)
+
+// IsEastAsianWideRune returns trhe if the given rune is an east asian wide character, otherwise false.
+func IsEastAsianWideRune(r rune) bool {
+	return unicode.Is(eastAsianWideTable, r)
+}
+
+// mergeRangeTables returns a unicode.RangeTable that contains all runes in
+// the given tables. Ranges with strides are expanded into single runes,
+// so the tables should consist of mostly contiguous ranges.
+func mergeRangeTables(tables ...*unicode.RangeTable) *unicode.RangeTable {
+	type runeRange struct{ lo, hi rune }
+	var ranges []runeRange
+	add := func(lo, hi, stride rune) {
+		if stride == 1 {
+			ranges = append(ranges, runeRange{lo, hi})
+			return
+		}
+		for r := lo; r <= hi; r += stride {
+			ranges = append(ranges, runeRange{r, r})
+		}
+	}
+	for _, table := range tables {
+		for _, r := range table.R16 {
+			add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+		}
+		for _, r := range table.R32 {
+			add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].lo < ranges[j].lo
+	})
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if last := len(merged) - 1; last >= 0 && r.lo <= merged[last].hi+1 {
+			if r.hi > merged[last].hi {
+				merged[last].hi = r.hi
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	table := &unicode.RangeTable{}
+	for _, r := range merged {
+		if r.hi <= unicode.MaxLatin1 {
+			table.LatinOffset++
+		}
+		if r.hi <= 0xFFFF {
+			table.R16 = append(table.R16, unicode.Range16{Lo: uint16(r.lo), Hi: uint16(r.hi), Stride: 1})
+		} else if r.lo > 0xFFFF {
+			table.R32 = append(table.R32, unicode.Range32{Lo: uint32(r.lo), Hi: uint32(r.hi), Stride: 1})
+		} else {
+			table.R16 = append(table.R16, unicode.Range16{Lo: uint16(r.lo), Hi: 0xFFFF, Stride: 1})
+			table.R32 = append(table.R32, unicode.Range32{Lo: 0x10000, Hi: uint32(r.hi), Stride: 1})
+		}
+	}
+	return table
+}
+
+// codeFenceLength returns a length of the code fence at the beginning of
+// the given line, otherwise 0.
+func codeFenceLength(line []byte) int {
+	if len(line) == 0 || (line[0] != '`' && line[0] != '~') {
+		return 0
+	}
+	i := CountLeadingBytes(line, line[0])
+	if i < 3 {
+		return 0
+	}
+	return i
+}
+
+// trimBlockMarkers removes blockquote markers, heading markers and list
+// markers at the beginning of the given line.
+func trimBlockMarkers(line []byte) []byte {
+	for {
+		line = TrimLeftSpace(line)
+		i := 0
+		switch {
+		case len(line) != 0 && line[0] == '>':
+			line = line[1:]
+			continue
+		case len(line) != 0 && line[0] == '#':
+			for i < len(line) && line[i] == '#' {
+				i++
+			}
+			if i > 6 {
+				return line
+			}
+		case len(line) != 0 && (line[0] == '-' || line[0] == '*' || line[0] == '+'):
+			i = 1
+		default:
+			for i < len(line) && i < 10 && IsNumeric(line[i]) {
+				i++
+			}
+			if i == 0 || i == 10 || i >= len(line) || (line[i] != '.' && line[i] != ')') {
+				return line
+			}
+			i++
+		}
+		if i < len(line) && !IsSpace(line[i]) {
+			return line
+		}
+		line = line[i:]
+	}
+}
+
+// WordCount returns a number of words in the given Markdown source without
+// parsing it. Words are separated by spaces and each east asian wide
+// character is counted as a word. Heading markers, blockquote markers,
+// list markers and code fences are not counted, but words in code
+// blocks are counted.
+// Tokens that consist of punctuations like '|' and '**' are not counted.
+func WordCount(source []byte) int {
+	count := 0
+	fence := 0
+	var fenceChar byte
+	for len(source) != 0 {
+		line := source
+		if i := bytes.IndexByte(source, '\n'); i > -1 {
+			line, source = source[:i], source[i+1:]
+		} else {
+			source = nil
+		}
+		trimmed := TrimLeftSpace(line)
+		if l := codeFenceLength(trimmed); l != 0 {
+			if fence == 0 {
+				fence, fenceChar = l, trimmed[0]
+				continue
+			}
+			if trimmed[0] == fenceChar && l >= fence && IsBlank(trimmed[l:]) {
+				fence = 0
+				continue
+			}
+		}
+		if fence == 0 {
+			line = trimBlockMarkers(line)
+		}
+		count += countWords(line)
+	}
+	return count
+}
+
+func countWords(line []byte) int {
+	count := 0
+	inWord := false
+	word := false
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRune(line[i:])
+		i += size
+		switch {
+		case IsSpaceRune(r):
+			inWord = false
+		case IsEastAsianWideRune(r):
+			count++
+			inWord = false
+		case !inWord:
+			inWord = true
+			word = false
+			fallthrough
+		default:
+			if !word && !IsPunctRune(r) && !unicode.IsSymbol(r) {
+				word = true
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// StripMarkdown returns texts in the given Markdown source without
+// heading markers, blockquote markers, list markers, emphasis delimiters,
+// link syntax, code fences and HTML tags.
+//
+// StripMarkdown does not parse the source. It processes the source line by
+// line with a simple state machine, so the result is an approximation and
+// is not CommonMark compliant. For example, it does not handle reference
+// definitions, nested brackets and constructs that span multiple lines.
+// Use a parser and walk the AST if you need correct results.
+func StripMarkdown(source []byte) []byte {
+	result := make([]byte, 0, len(source))
+	fence := 0
+	var fenceChar byte
+	for len(source) != 0 {
+		line := source
+		newline := false
+		if i := bytes.IndexByte(source, '\n'); i > -1 {
+			line, source = source[:i], source[i+1:]
+			newline = true
+		} else {
+			source = nil
+		}
+		trimmed := TrimLeftSpace(line)
+		if l := codeFenceLength(trimmed); l != 0 && (fence == 0 ||
+			trimmed[0] == fenceChar && l >= fence && IsBlank(trimmed[l:])) {
+			if fence == 0 {
+				fence, fenceChar = l, trimmed[0]
+			} else {
+				fence = 0
+			}
+			continue
+		}
+		if fence != 0 {
+			result = append(result, line...)
+		} else {
+			result = append(result, stripInlineMarkdown(stripBlockMarkdown(line))...)
+		}
+		if newline {
+			result = append(result, '\n')
+		}
+	}
+	return result
+}
+
+// stripBlockMarkdown removes block markers and a closing sequence of an
+// ATX heading in the given line.
+func stripBlockMarkdown(line []byte) []byte {
+	heading := false
+	if t := TrimLeftSpace(line); len(t) != 0 && t[0] == '#' {
+		heading = true
+	}
+	line = trimBlockMarkers(line)
+	if len(line) != 0 && line[0] == '#' {
+		heading = false
+	}
+	line = TrimRightSpace(line)
+	if heading {
+		i := len(line)
+		for i > 0 && line[i-1] == '#' {
+			i--
+		}
+		if i == 0 || IsSpace(line[i-1]) {
+			line = TrimRightSpace(line[:i])
+		}
+	}
+	// thematic breaks and setext heading underlines
+	if len(line) != 0 && len(bytes.Trim(line, "-*_= ")) == 0 {
+		return nil
+	}
+	return line
+}
+
+// stripInlineMarkdown removes emphasis delimiters, link syntax, code span
+// delimiters and HTML tags in the given line.
+func stripInlineMarkdown(line []byte) []byte {
+	result := make([]byte, 0, len(line))
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch c {
+		case '\\':
+			if i+1 < len(line) && IsPunct(line[i+1]) {
+				i++
+				result = append(result, line[i])
+				continue
+			}
+		case '`':
+			for i+1 < len(line) && line[i+1] == '`' {
+				i++
+			}
+			continue
+		case '*', '~':
+			continue
+		case '_':
+			prev := i == 0 || !IsAlphaNumeric(line[i-1])
+			next := i+1 >= len(line) || !IsAlphaNumeric(line[i+1])
+			if prev || next {
+				continue
+			}
+		case '!':
+			if i+1 < len(line) && line[i+1] == '[' {
+				continue
+			}
+		case '[':
+			continue
+		case ']':
+			// skip a destination like '(url)' or a label like '[label]'
+			if i+1 < len(line) && (line[i+1] == '(' || line[i+1] == '[') {
+				closer := byte(')')
+				if line[i+1] == '[' {
+					closer = ']'
+				}
+				if j := bytes.IndexByte(line[i+2:], closer); j > -1 {
+					i += j + 2
+				}
+			}
+			continue
+		case '<':
+			j := bytes.IndexByte(line[i+1:], '>')
+			if j < 0 {
+				break
+			}
+			tag := line[i+1 : i+1+j]
+			if bytes.IndexAny(tag, " \t") < 0 && bytes.IndexAny(tag, ":@") > -1 {
+				// an autolink like '<https://example.com>'
+				result = append(result, tag...)
+				i += j + 1
+				continue
+			}
+			if len(tag) != 0 && (tag[0] == '/' || tag[0] == '!' || IsAlphaNumeric(tag[0])) {
+				i += j + 1
+				continue
+			}
+		}
+		result = append(result, c)
 	}
-	return unicode.Is(unicode.Hiragana, r) ||
-		unicode.Is(unicode.Katakana, r) ||
-		unicode.Is(unicode.Han, r) ||
-		unicode.Is(unicode.Lm, r) ||
-		unicode.Is(unicode.Hangul, r) ||
-		unicode.Is(CJKSymbolsAndPunctuation, r)
+	return result
 }
 
 // A BufWriter is a subset of the bufio.Writer .
@@ -929,11 +1562,25 @@ type bytesFilter struct {
 	slots     [][][]byte
 }
 
-// NewBytesFilter returns a new BytesFilter.
+// DefaultBytesFilterSize is a number of slots of BytesFilters created by
+// NewBytesFilter. It is optimized for 50 or fewer distinct elements.
+const DefaultBytesFilterSize = 64
+
+// NewBytesFilter returns a new BytesFilter with DefaultBytesFilterSize slots.
 func NewBytesFilter(elements ...[]byte) BytesFilter {
+	return NewBytesFilterWithSize(DefaultBytesFilterSize, elements...)
+}
+
+// NewBytesFilterWithSize returns a new BytesFilter with the given number of
+// slots. More slots reduce hash collisions for large sets of elements.
+// NewBytesFilterWithSize panics if numSlots is not a power of two.
+func NewBytesFilterWithSize(numSlots int, elements ...[]byte) BytesFilter {
+	if numSlots <= 0 || numSlots&(numSlots-1) != 0 {
+		panic("util: number of slots of BytesFilter must be a power of two: " + strconv.Itoa(numSlots))
+	}
 	s := &bytesFilter{
 		threshold: 3,
-		slots:     make([][][]byte, 64),
+		slots:     make([][][]byte, numSlots),
 	}
 	for _, element := range elements {
 		s.Add(element)
@@ -959,13 +1606,13 @@ func (s *bytesFilter) Add(b []byte) {
 }
 
 func (s *bytesFilter) Extend(bs ...[]byte) BytesFilter {
-	newFilter := NewBytesFilter().(*bytesFilter)
+	newFilter := NewBytesFilterWithSize(len(s.slots)).(*bytesFilter)
 	newFilter.chars = s.chars
 	newFilter.threshold = s.threshold
 	for k, v := range s.slots {
 		newSlot := make([][]byte, len(v))
 		copy(newSlot, v)
-		newFilter.slots[k] = v
+		newFilter.slots[k] = newSlot
 	}
 	for _, b := range bs {
 		newFilter.Add(b)
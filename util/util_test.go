@@ -0,0 +1,699 @@
+package util
+
+import (
+	"math/rand"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+var emailDomainRegexp = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*`) //nolint:golint,lll
+
+func findEmailIndexByRegexp(b []byte) int {
+	i := 0
+	for ; i < len(b); i++ {
+		c := b[i]
+		if emailTable[c]&1 != 1 {
+			break
+		}
+	}
+	if i == 0 {
+		return -1
+	}
+	if i >= len(b) || b[i] != '@' {
+		return -1
+	}
+	i++
+	if i >= len(b) {
+		return -1
+	}
+	match := emailDomainRegexp.FindSubmatchIndex(b[i:])
+	if match == nil {
+		return -1
+	}
+	return i + match[1]
+}
+
+func TestFindEmailIndex(t *testing.T) {
+	cases := []string{
+		"",
+		"a",
+		"a@",
+		"@example.com",
+		"a@example.com",
+		"a@example.com.",
+		"a@example..com",
+		"a@-example.com",
+		"a@example-.com",
+		"a@ex-am-ple.com",
+		"a@example.com-",
+		"a@example.c-",
+		"a@1.2.3.4",
+		"a@example.com>",
+		"a@" + strings.Repeat("a", 63) + ".com",
+		"a@" + strings.Repeat("a", 64) + ".com",
+		"a@" + strings.Repeat("a", 62) + "-.com",
+		"a@" + strings.Repeat("-", 61) + "a",
+		"a@a" + strings.Repeat("-", 61) + "a",
+		"a@a" + strings.Repeat("-", 62) + "a",
+		"a@a" + strings.Repeat("-", 70),
+		"a@日本.com",
+	}
+	for _, c := range cases {
+		expected := findEmailIndexByRegexp([]byte(c))
+		if actual := FindEmailIndex([]byte(c)); actual != expected {
+			t.Errorf("%q: expected %d, but got %d", c, expected, actual)
+		}
+	}
+
+	chars := []byte("ab09-.@_+ ")
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 100000; n++ {
+		b := []byte("a@")
+		l := r.Intn(140)
+		for i := 0; i < l; i++ {
+			if r.Intn(10) == 0 {
+				b = append(b, chars[r.Intn(len(chars))])
+			} else {
+				b = append(b, chars[r.Intn(6)])
+			}
+		}
+		expected := findEmailIndexByRegexp(b)
+		if actual := FindEmailIndex(b); actual != expected {
+			t.Fatalf("%q: expected %d, but got %d", b, expected, actual)
+		}
+	}
+}
+
+func BenchmarkFindEmailIndex(b *testing.B) {
+	source := []byte("someone@mail.example.com")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FindEmailIndex(source)
+	}
+}
+
+func TestCopyOnWriteBuffer(t *testing.T) {
+	source := []byte("hello")
+	b := NewCopyOnWriteBuffer(source)
+	b.Append([]byte(" world"))
+	if !b.IsCopied() || string(b.Bytes()) != "hello world" || string(source) != "hello" {
+		t.Errorf("unexpected Append result: %q", b.Bytes())
+	}
+	b = NewCopyOnWriteBuffer(source)
+	b.Write([]byte("bye"))
+	_ = b.WriteByte('!')
+	if string(b.Bytes()) != "bye!" || string(source) != "hello" {
+		t.Errorf("unexpected Write result: %q", b.Bytes())
+	}
+	b = NewCopyOnWriteBuffer(source)
+	if n, _ := b.WriteRune('日'); n != 3 || string(b.Bytes()) != "日" {
+		t.Errorf("unexpected WriteRune result: %d, %q", n, b.Bytes())
+	}
+	b = NewCopyOnWriteBuffer(source)
+	b.AppendRune('é')
+	if string(b.Bytes()) != "helloé" || string(source) != "hello" {
+		t.Errorf("unexpected AppendRune result: %q", b.Bytes())
+	}
+}
+
+func BenchmarkCopyOnWriteBufferWrite(b *testing.B) {
+	source := make([]byte, 100)
+	chunk := []byte(strings.Repeat("x", 100))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf := NewCopyOnWriteBuffer(source)
+		for j := 0; j < 10000; j++ {
+			buf.Write(chunk)
+		}
+	}
+}
+
+func urlEscapeByQueryEscape(v []byte) []byte {
+	cob := NewCopyOnWriteBuffer(v)
+	limit := len(v)
+	n := 0
+	for i := 0; i < limit; {
+		c := v[i]
+		if urlEscapeTable[c] == 1 {
+			i++
+			continue
+		}
+		if c == '%' && i+2 < limit && IsHexDecimal(v[i+1]) && IsHexDecimal(v[i+1]) {
+			i += 3
+			continue
+		}
+		u8len := utf8lenTable[c]
+		if u8len == 99 {
+			i++
+			continue
+		}
+		if c == ' ' {
+			cob.Write(v[n:i])
+			cob.Write(htmlSpace)
+			i++
+			n = i
+			continue
+		}
+		if int(u8len) > len(v) {
+			u8len = int8(len(v) - 1)
+		}
+		if u8len == 0 {
+			i++
+			n = i
+			continue
+		}
+		cob.Write(v[n:i])
+		stop := i + int(u8len)
+		if stop > len(v) {
+			i++
+			n = i
+			continue
+		}
+		cob.Write([]byte(url.QueryEscape(string(v[i:stop]))))
+		i += int(u8len)
+		n = i
+	}
+	if cob.IsCopied() && n < limit {
+		cob.Write(v[n:])
+	}
+	return cob.Bytes()
+}
+
+func TestURLEscape(t *testing.T) {
+	cases := []string{
+		"",
+		"https://example.com/path?q=1&r=2#frag",
+		"/日本語/パス",
+		"a b\"c<d>e\\\\f`g{h}|i^j~k",
+		"%E3%81%82%zz%",
+		"\xe3\x81",
+		"\xff\xfe",
+	}
+	for i := 0; i < 256; i++ {
+		cases = append(cases, "a"+string([]byte{byte(i)})+"b")
+	}
+	for _, c := range cases {
+		expected := string(urlEscapeByQueryEscape([]byte(c)))
+		if actual := string(URLEscape([]byte(c), false)); actual != expected {
+			t.Errorf("%q: expected %q, but got %q", c, expected, actual)
+		}
+	}
+}
+
+func TestURLEscapePath(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		{"", ""},
+		{"../page.md#anchor", "../page.md#anchor"},
+		{"/日本語 page.md", "/%E6%97%A5%E6%9C%AC%E8%AA%9E%20page.md"},
+		{"/日本語.md#見出し %20", "/%E6%97%A5%E6%9C%AC%E8%AA%9E.md#見出し %20"},
+		{"#only-fragment", "#only-fragment"},
+		{"a#b#c", "a#b#c"},
+	}
+	for _, c := range cases {
+		if actual := string(URLEscapePath([]byte(c.source))); actual != c.expected {
+			t.Errorf("%q: expected %q, but got %q", c.source, c.expected, actual)
+		}
+	}
+}
+
+func BenchmarkURLEscape(b *testing.B) {
+	source := []byte("https://example.com/日本語/パス?q=値")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		URLEscape(source, false)
+	}
+}
+
+func fnv1aHash(b []byte) uint64 {
+	var hash uint64 = 14695981039346656037
+	for _, c := range b {
+		hash ^= uint64(c)
+		hash *= 1099511628211
+	}
+	return hash
+}
+
+// attributeNames is a set of attribute names those are used by
+// html.GlobalAttributeFilter and html.ImageAttributeFilter.
+var attributeNames = strings.Fields(`accesskey autocapitalize autofocus class
+contenteditable dir draggable enterkeyhint hidden id inert inputmode is itemid
+itemprop itemref itemscope itemtype lang part role slot spellcheck style
+tabindex title translate align alt crossorigin decoding height importance
+intrinsicsize ismap loading referrerpolicy sizes src srcset usemap width`)
+
+func bucketUtilization(names []string, hash func([]byte) uint64) int {
+	used := map[uint64]bool{}
+	for _, name := range names {
+		used[hash([]byte(name))%64] = true
+	}
+	return len(used)
+}
+
+func TestBytesHashDistribution(t *testing.T) {
+	djb2 := bucketUtilization(attributeNames, bytesHash)
+	fnv1a := bucketUtilization(attributeNames, fnv1aHash)
+	t.Logf("%d names: DJB2 uses %d buckets, FNV-1a uses %d buckets", len(attributeNames), djb2, fnv1a)
+	if djb2 < fnv1a {
+		t.Errorf("bytesHash uses %d buckets, but FNV-1a uses %d buckets", djb2, fnv1a)
+	}
+
+	filter := NewBytesFilter()
+	for _, name := range attributeNames {
+		filter.Add([]byte(name))
+	}
+	for _, name := range attributeNames {
+		if !filter.Contains([]byte(name)) {
+			t.Errorf("%s should be contained", name)
+		}
+	}
+	if filter.Contains([]byte("onclick")) {
+		t.Errorf("onclick should not be contained")
+	}
+}
+
+func TestNewBytesFilterWithSize(t *testing.T) {
+	filter := NewBytesFilterWithSize(256, []byte("a"), []byte("b"))
+	extended := filter.Extend([]byte("c"))
+	if n := len(extended.(*bytesFilter).slots); n != 256 {
+		t.Errorf("Extend should preserve the number of slots, but got %d", n)
+	}
+	if !extended.Contains([]byte("a")) || !extended.Contains([]byte("c")) || filter.Contains([]byte("c")) {
+		t.Error("unexpected Extend result")
+	}
+	for _, size := range []int{0, -1, 3, 100} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d: NewBytesFilterWithSize should panic", size)
+				}
+			}()
+			NewBytesFilterWithSize(size)
+		}()
+	}
+}
+
+func BenchmarkBytesHash(b *testing.B) {
+	names := make([][]byte, len(attributeNames))
+	for i, name := range attributeNames {
+		names[i] = []byte(name)
+	}
+	b.Run("DJB2", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, name := range names {
+				bytesHash(name)
+			}
+		}
+	})
+	b.Run("FNV-1a", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, name := range names {
+				fnv1aHash(name)
+			}
+		}
+	})
+}
+
+func BenchmarkBytesFilterContains(b *testing.B) {
+	filter := NewBytesFilter()
+	names := make([][]byte, len(attributeNames))
+	for i, name := range attributeNames {
+		names[i] = []byte(name)
+		filter.Add(names[i])
+	}
+	for i := 0; i < b.N; i++ {
+		for _, name := range names {
+			filter.Contains(name)
+		}
+	}
+}
+
+func TestEscapeHTML(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		c := byte(i)
+		var expected []byte
+		switch c {
+		case 0:
+			expected = []byte("\uFFFD")
+		case '"':
+			expected = []byte("&quot;")
+		case '&':
+			expected = []byte("&amp;")
+		case '<':
+			expected = []byte("&lt;")
+		case '>':
+			expected = []byte("&gt;")
+		}
+		if actual := EscapeHTMLByte(c); string(actual) != string(expected) || (actual == nil) != (expected == nil) {
+			t.Errorf("%q: expected %q, but got %q", c, expected, actual)
+		}
+	}
+	if actual := string(EscapeHTML([]byte(`<a href="?a=1&b=2">`))); actual != "&lt;a href=&quot;?a=1&amp;b=2&quot;&gt;" {
+		t.Errorf("unexpected result: %s", actual)
+	}
+}
+
+func BenchmarkEscapeHTML(b *testing.B) {
+	source := []byte(strings.Repeat(`<p class="x">Tom & Jerry</p> plain text `, 20))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		EscapeHTML(source)
+	}
+}
+
+func BenchmarkCharacterClass(b *testing.B) {
+	source := []byte(strings.Repeat("Markdown *is* a [lightweight](https://example.com) markup language.\n", 20))
+	b.Run("IsSpace", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			n := 0
+			for _, c := range source {
+				if IsSpace(c) {
+					n++
+				}
+			}
+		}
+	})
+	b.Run("IsPunct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			n := 0
+			for _, c := range source {
+				if IsPunct(c) {
+					n++
+				}
+			}
+		}
+	})
+	runes := []rune(strings.Repeat("Markdown は軽量マークアップ言語です。한국어 text.", 20))
+	b.Run("IsEastAsianWideRune", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			n := 0
+			for _, r := range runes {
+				if IsEastAsianWideRune(r) {
+					n++
+				}
+			}
+		}
+	})
+}
+
+func TestCountEntityReferences(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected int
+	}{
+		{"plain text", 0},
+		{"&amp; &lt;&gt; &ouml;", 4},
+		{"&#35; &#x22; &#X22; &#12345678; &#x;", 3},
+		{"&unknown; &amp &; &#;", 0},
+		{"&&amp;amp;", 1},
+	}
+	for _, c := range cases {
+		if actual := CountEntityReferences([]byte(c.source)); actual != c.expected {
+			t.Errorf("%q: expected %d, but got %d", c.source, c.expected, actual)
+		}
+	}
+}
+
+func TestWordCount(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected int
+	}{
+		{"", 0},
+		{"Hello, world!", 2},
+		{"# Heading one\n\n## Heading *two*", 4},
+		{"> - quoted item\n> 1. numbered item", 4},
+		{"- [link](/url) and **bold** text", 4},
+		{"```go\nfmt.Println(x)\n```\n", 1},
+		{"~~~~\n```\nin code\n~~~~", 2},
+		{"---\n***\n| a | b |\n|---|---|", 2},
+		{"#hashtag 12345678901. not a list", 5},
+		{"café naïve 日本語", 5},
+	}
+	for _, c := range cases {
+		if actual := WordCount([]byte(c.source)); actual != c.expected {
+			t.Errorf("%q: expected %d, but got %d", c.source, c.expected, actual)
+		}
+	}
+}
+
+func TestStripMarkdown(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		{"", ""},
+		{"# Heading #\n\nSetext\n---\n", "Heading\n\nSetext\n\n"},
+		{"> - **bold** and *em* and ~~del~~", "bold and em and del"},
+		{"1. snake_case _em_ `code` \\*not em\\*", "snake_case em code *not em*"},
+		{"[link](/url \"title\") ![image](/a.png) [ref][label]", "link image ref"},
+		{"<b>tag</b> <https://example.com> <!-- comment -->", "tag https://example.com "},
+		{"```go\n# not a heading\n```\n", "# not a heading\n"},
+		{"1 < 2 and a > b", "1 < 2 and a > b"},
+	}
+	for _, c := range cases {
+		if actual := string(StripMarkdown([]byte(c.source))); actual != c.expected {
+			t.Errorf("%q: expected %q, but got %q", c.source, c.expected, actual)
+		}
+	}
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		{"", ""},
+		{"a\nb\n", "a\nb\n"},
+		{"a\r\nb\r\n", "a\nb\n"},
+		{"a\rb\r", "a\nb\n"},
+		{"a\r\r\nb\n\r", "a\n\nb\n\n"},
+		{"a\x00b", "a\uFFFDb"},
+	}
+	for _, c := range cases {
+		if actual := string(NormalizeLineEndings([]byte(c.source))); actual != c.expected {
+			t.Errorf("%q: expected %q, but got %q", c.source, c.expected, actual)
+		}
+	}
+
+	source := []byte("no carriage returns\n")
+	if actual := NormalizeLineEndings(source); &actual[0] != &source[0] {
+		t.Error("NormalizeLineEndings should not copy a source without CR and NUL")
+	}
+}
+
+func TestRuneLenInBytes(t *testing.T) {
+	for _, r := range []rune{'a', 'é', '日', '😀', utf8.RuneError} {
+		buf := make([]byte, 4)
+		expected := utf8.EncodeRune(buf, r)
+		if actual := RuneLenInBytes(r); actual != expected {
+			t.Errorf("%q: expected %d, but got %d", r, expected, actual)
+		}
+		if actual := int(UTF8Len(buf[0])); actual != expected {
+			t.Errorf("%q: UTF8Len expected %d, but got %d", r, expected, actual)
+		}
+	}
+	for _, r := range []rune{-1, 0xD800, utf8.MaxRune + 1} {
+		if actual := RuneLenInBytes(r); actual != -1 {
+			t.Errorf("%U: expected -1, but got %d", r, actual)
+		}
+	}
+}
+
+func TestToLinkReferenceASCII(t *testing.T) {
+	slow := func(v []byte) string {
+		v = TrimRightSpace(TrimLeftSpace(v))
+		return string(ReplaceSpaces(DoFullUnicodeCaseFolding(v), ' '))
+	}
+	rnd := rand.New(rand.NewSource(1))
+	buf := make([]byte, 32)
+	for i := 0; i < 10000; i++ {
+		for j := range buf {
+			buf[j] = byte(rnd.Intn(utf8.RuneSelf))
+		}
+		if expected, actual := slow(buf), ToLinkReference(buf); expected != actual {
+			t.Errorf("%q: expected %q, but got %q", buf, expected, actual)
+		}
+	}
+	if actual := ToLinkReference([]byte(" Foo \t\n BAR ")); actual != "foo bar" {
+		t.Errorf("unexpected result: %q", actual)
+	}
+}
+
+func TestDoFullUnicodeCaseFoldingString(t *testing.T) {
+	for _, v := range []string{"", "abc", "ABC", "Straße", "ẞ", "ΣΊΣΥΦΟΣ", "ﬃ", "日本語 ABC"} {
+		expected := string(DoFullUnicodeCaseFolding([]byte(v)))
+		if actual := DoFullUnicodeCaseFoldingString(v); actual != expected {
+			t.Errorf("%q: expected %q, but got %q", v, expected, actual)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() {
+		_ = DoFullUnicodeCaseFoldingString("already folded")
+	}); allocs != 0 {
+		t.Errorf("folded strings should not be copied: %v allocs", allocs)
+	}
+}
+
+func TestToLinkReferenceUnicode(t *testing.T) {
+	for v, expected := range map[string]string{
+		" ΑΓΑΠΗ \t\n Straße ": "αγαπη strasse",
+		"ẞ  ﬃ":                "ss ffi",
+		"日本語\nラベル":            "日本語 ラベル",
+	} {
+		if actual := ToLinkReference([]byte(v)); actual != expected {
+			t.Errorf("%q: expected %q, but got %q", v, expected, actual)
+		}
+	}
+}
+
+func BenchmarkToLinkReference(b *testing.B) {
+	labels := make([][]byte, 1000)
+	for i := range labels {
+		labels[i] = []byte("Link Label  " + strings.Repeat("X", i%20) + " number " + string(rune('A'+i%26)))
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, label := range labels {
+			ToLinkReference(label)
+		}
+	}
+}
+
+func TestIsEastAsianWideRune(t *testing.T) {
+	cjkSymbolsAndPunctuation := &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{0x3000, 0x303F, 1},
+		},
+	}
+	expected := func(r rune) bool {
+		return unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) ||
+			unicode.Is(unicode.Han, r) ||
+			unicode.Is(unicode.Lm, r) ||
+			unicode.Is(unicode.Hangul, r) ||
+			unicode.Is(cjkSymbolsAndPunctuation, r)
+	}
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if actual := IsEastAsianWideRune(r); actual != expected(r) {
+			t.Errorf("%U: expected %v, but got %v", r, expected(r), actual)
+		}
+	}
+}
+
+func TestScanWord(t *testing.T) {
+	source := []byte("  go  title=\"a b\"\t\n")
+	var words []string
+	for word, end := ScanWord(source, 0); word != nil; word, end = ScanWord(source, end) {
+		words = append(words, string(word))
+	}
+	if strings.Join(words, "|") != `go|title="a|b"` {
+		t.Errorf("unexpected words: %q", words)
+	}
+	if word, end := ScanWord(source, 18); word != nil || end != len(source) {
+		t.Errorf("unexpected result: %q, %d", word, end)
+	}
+
+	if actual := string(ScanToEnd(source, 4)); actual != `  title="a b"` {
+		t.Errorf("unexpected result: %q", actual)
+	}
+	if actual := ScanToEnd(source, 18); actual != nil {
+		t.Errorf("unexpected result: %q", actual)
+	}
+	if actual := ScanToEnd(source, 100); actual != nil {
+		t.Errorf("unexpected result: %q", actual)
+	}
+}
+
+func TestReadUntil(t *testing.T) {
+	source := []byte("key = value")
+	if i, ok := ReadUntil(source, [2]int{0, len(source)}, IsSpace); i != 3 || !ok {
+		t.Errorf("unexpected result: %d, %v", i, ok)
+	}
+	if i, ok := ReadUntil(source, [2]int{4, 6}, IsAlphaNumeric); i != 6 || ok {
+		t.Errorf("unexpected result: %d, %v", i, ok)
+	}
+	if i, ok := ReadUntil(source, [2]int{3, 3}, IsSpace); i != 3 || ok {
+		t.Errorf("unexpected result: %d, %v", i, ok)
+	}
+}
+
+func TestCountLeadingBytes(t *testing.T) {
+	cases := []struct {
+		source   string
+		leading  int
+		trailing int
+	}{
+		{"", 0, 0},
+		{"### heading ##", 3, 2},
+		{"######", 6, 6},
+		{"a#", 0, 1},
+	}
+	for _, c := range cases {
+		if actual := CountLeadingBytes([]byte(c.source), '#'); actual != c.leading {
+			t.Errorf("%q: expected %d leading bytes, but got %d", c.source, c.leading, actual)
+		}
+		if actual := CountTrailingBytes([]byte(c.source), '#'); actual != c.trailing {
+			t.Errorf("%q: expected %d trailing bytes, but got %d", c.source, c.trailing, actual)
+		}
+	}
+}
+
+func TestCharacterClass(t *testing.T) {
+	// ASCII characters are compared exhaustively.
+	// CommonMark treats ASCII symbols like '$' and '+' as punctuations.
+	for r := rune(0); r < utf8.RuneSelf; r++ {
+		c := byte(r)
+		if expected := unicode.IsPunct(r) || unicode.IsSymbol(r); IsPunct(c) != expected || IsPunctRune(r) != expected {
+			t.Errorf("IsPunct(%q): expected %v", r, expected)
+		}
+		// A vertical tab and a form feed are not spaces for the block
+		// structure, but IsSpaceRune falls back to unicode.IsSpace.
+		if expected := unicode.IsSpace(r) && r != '\v' && r != '\f'; IsSpace(c) != expected {
+			t.Errorf("IsSpace(%q): expected %v", r, expected)
+		}
+		if expected := unicode.IsSpace(r); IsSpaceRune(r) != expected {
+			t.Errorf("IsSpaceRune(%q): expected %v", r, expected)
+		}
+		if expected := unicode.IsDigit(r); IsNumeric(c) != expected {
+			t.Errorf("IsNumeric(%q): expected %v", r, expected)
+		}
+		if expected := unicode.IsLetter(r) || unicode.IsDigit(r); IsAlphaNumeric(c) != expected {
+			t.Errorf("IsAlphaNumeric(%q): expected %v", r, expected)
+		}
+		if expected := strings.ContainsRune("0123456789abcdefABCDEF", r); IsHexDecimal(c) != expected {
+			t.Errorf("IsHexDecimal(%q): expected %v", r, expected)
+		}
+	}
+
+	// Byte functions only know ASCII, so bytes greater than 0x7f
+	// (including utf-8 continuation bytes) never belong to any class.
+	for i := utf8.RuneSelf; i < 256; i++ {
+		c := byte(i)
+		if IsPunct(c) || IsSpace(c) || IsNumeric(c) || IsAlphaNumeric(c) || IsHexDecimal(c) {
+			t.Errorf("%#x should not belong to any character class", c)
+		}
+	}
+
+	// Rune functions follow the unicode package for non-ASCII runes.
+	// Unlike ASCII, non-ASCII symbols (e.g. '€') are not punctuations.
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 100000; n++ {
+		v := utf8.RuneSelf + r.Int31n(unicode.MaxRune-utf8.RuneSelf+1)
+		if n%2 == 0 {
+			// half of the runes are in the BMP, that holds most punctuations
+			v = utf8.RuneSelf + r.Int31n(0x10000-utf8.RuneSelf)
+		}
+		if expected := unicode.IsPunct(v); IsPunctRune(v) != expected {
+			t.Fatalf("IsPunctRune(%U): expected %v", v, expected)
+		}
+		if expected := unicode.IsSpace(v); IsSpaceRune(v) != expected {
+			t.Fatalf("IsSpaceRune(%U): expected %v", v, expected)
+		}
+	}
+}